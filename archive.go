@@ -0,0 +1,183 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/httpio"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/s3io"
+)
+
+// archiveKind identifies the container format of an input bundle.
+type archiveKind int
+
+const (
+	notArchive archiveKind = iota
+	zipArchive
+	tarArchive
+)
+
+// archiveKindOf returns the archive kind implied by the extension of name.
+func archiveKindOf(name string) archiveKind {
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return zipArchive
+	case strings.HasSuffix(name, ".tar"), strings.HasSuffix(name, ".tar.gz"),
+		strings.HasSuffix(name, ".tgz"), strings.HasSuffix(name, ".tar.zst"):
+		return tarArchive
+	default:
+		return notArchive
+	}
+}
+
+// runArchive transforms every file in the zip or tar archive at inputPath
+// with transformEntry and writes a mirrored archive of the same kind, with
+// the same entry names, to outputPath or stdout. Tar output is compressed
+// with format. Entries ending in .gz or .zst are decompressed before they are
+// transformed and compressed again afterwards. S3 objects and HTTP(S)
+// downloads are read, and S3 objects written, within ctx.
+func runArchive(ctx context.Context, kind archiveKind, inputPath, outputPath string, format compress.Format, transformEntry func(r io.Reader, w io.Writer) error) error {
+	if kind == zipArchive {
+		zr, closeInput, err := openZip(ctx, inputPath)
+		if err != nil {
+			return parseError(err)
+		}
+		defer closeInput()
+
+		return writeOutput(ctx, outputPath, compress.None, func(w io.Writer) error {
+			return mirrorZip(zr, w, transformEntry)
+		})
+	}
+
+	in, err := openInput(ctx, inputPath, false)
+	if err != nil {
+		return parseError(err)
+	}
+	defer in.Close()
+
+	return writeOutput(ctx, outputPath, format, func(w io.Writer) error {
+		return mirrorTar(tar.NewReader(in), w, transformEntry)
+	})
+}
+
+// openZip opens the zip archive at name. Local files are read in place;
+// S3 objects and HTTP(S) downloads are buffered in memory since zip needs
+// random access.
+func openZip(ctx context.Context, name string) (*zip.Reader, func() error, error) {
+	if !s3io.IsURL(name) && !httpio.IsURL(name) {
+		rc, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &rc.Reader, rc.Close, nil
+	}
+
+	in, err := openInput(ctx, name, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer in.Close()
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return zr, func() error { return nil }, nil
+}
+
+// mirrorZip writes each entry of zr to a new zip archive on w, transforming
+// regular files and copying directories.
+func mirrorZip(zr *zip.Reader, w io.Writer, transformEntry func(r io.Reader, w io.Writer) error) error {
+	zw := zip.NewWriter(w)
+	for _, f := range zr.File {
+		header := f.FileHeader
+		if f.FileInfo().IsDir() {
+			if _, err := zw.CreateHeader(&header); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Sizes and checksums are recomputed for the transformed content
+		header.CompressedSize64, header.UncompressedSize64, header.CRC32 = 0, 0, 0
+		ew, err := zw.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+		r, err := f.Open()
+		if err != nil {
+			return parseError(fmt.Errorf("%s: %w", f.Name, err))
+		}
+		err = transformArchiveEntry(f.Name, r, ew, transformEntry)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// mirrorTar writes each entry of tr to a new tar archive on w, transforming
+// regular files and copying everything else.
+func mirrorTar(tr *tar.Reader, w io.Writer, transformEntry func(r io.Reader, w io.Writer) error) error {
+	tw := tar.NewWriter(w)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return parseError(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Tar headers carry the entry size up front, so the transformed
+		// output is buffered before it is written
+		var buf bytes.Buffer
+		if err := transformArchiveEntry(header.Name, tr, &buf, transformEntry); err != nil {
+			return err
+		}
+		header.Size = int64(buf.Len())
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := buf.WriteTo(tw); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// transformArchiveEntry transforms the archive entry name from r into w,
+// keeping the entry's compression format.
+func transformArchiveEntry(name string, r io.Reader, w io.Writer, transformEntry func(r io.Reader, w io.Writer) error) error {
+	dr, err := compress.NewReader(name, r)
+	if err != nil {
+		return parseError(fmt.Errorf("%s: %w", name, err))
+	}
+	defer dr.Close()
+
+	cw, err := compress.NewWriter(w, compress.FormatOf(name))
+	if err != nil {
+		return err
+	}
+	if err := transformEntry(dr, cw); err != nil {
+		cw.Close()
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return cw.Close()
+}
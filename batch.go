@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+)
+
+// batchResult records the outcome of transforming one file in batch mode.
+type batchResult struct {
+	path string
+	err  error
+}
+
+// runBatch transforms every JSON file in the directory inputDir, or matching
+// the glob pattern inputDir, with transformFile, using up to concurrency
+// workers. Each output is written to the same relative path under outputDir
+// and compressed with format. Every file is logged as it is reported, followed
+// by a summary of successes and failures once every file has been processed.
+// Once ctx is done no further file is started, and the files left over are
+// counted in the failure returned. With a checkpoint file every transformed
+// file is recorded in it, files recorded by an earlier run are skipped, and
+// the file is removed once every file has been transformed.
+func runBatch(ctx context.Context, inputDir, outputDir, checkpoint string, format compress.Format, concurrency int, transformFile func(r io.Reader, w io.Writer) error) error {
+	root, files, err := discoverFiles(inputDir)
+	if err != nil {
+		return usageError(err)
+	}
+	if len(files) == 0 {
+		return usageError(fmt.Errorf("no JSON files found in %q", inputDir))
+	}
+
+	// Resume a batch interrupted after transforming some of its files
+	saved := &batchCheckpoint{path: checkpoint}
+	if checkpoint != "" {
+		if saved.done, err = stream.LoadCheckpoint(checkpoint); err != nil {
+			return usageError(fmt.Errorf("checkpoint %s: %w", checkpoint, err))
+		}
+		var remaining []string
+		for _, path := range files {
+			if _, ok := saved.done[path]; !ok {
+				remaining = append(remaining, path)
+			}
+		}
+		if resumed := len(files) - len(remaining); resumed > 0 {
+			slog.Info("batch resumed", "checkpoint", checkpoint, "already_transformed", resumed)
+		}
+		files = remaining
+	}
+
+	// Count the files and their sizes towards the progress reported
+	report := progressFrom(ctx)
+	report.addFiles(len(files))
+	for _, path := range files {
+		report.addTotal(inputSize(path, false))
+	}
+
+	// Workers pick files by index so results keep the discovery order
+	jobs := make(chan int)
+	results := make([]batchResult, len(files))
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				err := recoverTransform(func() error {
+					return transformBatchFile(ctx, root, files[j], outputDir, format, transformFile)
+				})
+				if err == nil {
+					err = saved.record(files[j])
+				}
+				progressFrom(ctx).fileDone()
+				results[j] = batchResult{path: files[j], err: err}
+			}
+		}()
+	}
+
+	// Files not started by the time ctx is done are left out
+	started := 0
+dispatch:
+	for ; started < len(files); started++ {
+		select {
+		case jobs <- started:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Report every file, keeping the first failure to decide the exit code
+	// and to be reported along with the summary
+	var firstErr error
+	failed := 0
+	for _, result := range results[:started] {
+		if result.err != nil {
+			failed++
+			fileErr := &fileError{file: result.path, err: result.err}
+			logError("file failed", fileErr)
+			if firstErr == nil {
+				firstErr = fileErr
+			}
+		} else {
+			slog.Info("file transformed", "file", result.path)
+		}
+	}
+	slog.Info("batch finished", "transformed", started-failed, "failed", failed, "skipped", len(files)-started)
+
+	if started < len(files) {
+		return transformError(fmt.Errorf("%d of %d files not transformed: %w", len(files)-started, len(files), ctx.Err()))
+	}
+	if firstErr != nil {
+		return &exitError{code: exitCode(firstErr), err: fmt.Errorf("%d of %d files failed, first: %w", failed, len(files), firstErr)}
+	}
+	return saved.remove()
+}
+
+// batchCheckpoint records the files of a batch transformed so far in a
+// checkpoint file, keyed by input path; it records nothing without a path.
+type batchCheckpoint struct {
+	path string
+
+	mu   sync.Mutex
+	done map[string]string
+}
+
+// record adds file to the checkpoint and saves it.
+func (c *batchCheckpoint) record(file string) error {
+	if c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done[file] = "transformed"
+	if err := stream.SaveCheckpoint(c.path, c.done); err != nil {
+		return transformError(fmt.Errorf("save checkpoint: %w", err))
+	}
+	return nil
+}
+
+// remove deletes the checkpoint once the whole batch has been transformed.
+func (c *batchCheckpoint) remove() error {
+	if c.path == "" {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return transformError(err)
+	}
+	return nil
+}
+
+// discoverFiles returns the JSON files in the directory pattern, searched
+// recursively, or the files matching pattern when it is not a directory,
+// along with the root their output paths are made relative to.
+func discoverFiles(pattern string) (root string, files []string, err error) {
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		err = filepath.WalkDir(pattern, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.Contains(d.Name(), ".json") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		return pattern, files, err
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+	return globRoot(pattern), files, nil
+}
+
+// globRoot returns the longest leading directory of pattern that contains no
+// glob metacharacters.
+func globRoot(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for strings.ContainsAny(dir, `*?[\`) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// transformBatchFile transforms the file at path into the same relative
+// location under outputDir, compressed with format, or like the input when
+// format is None.
+func transformBatchFile(ctx context.Context, root, path, outputDir string, format compress.Format, transformFile func(r io.Reader, w io.Writer) error) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return usageError(err)
+	}
+	outPath := filepath.Join(outputDir, rel)
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return transformError(err)
+	}
+
+	in, err := openInput(ctx, path, false)
+	if err != nil {
+		return parseError(err)
+	}
+	defer in.Close()
+
+	// Compressed inputs produce outputs compressed the same way by default
+	if format == compress.None {
+		format = compress.FormatOf(path)
+	}
+	return writeOutput(ctx, outPath, format, func(w io.Writer) error {
+		return transformFile(in, w)
+	})
+}
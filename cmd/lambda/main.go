@@ -0,0 +1,31 @@
+// Command lambda runs the transformer as an AWS Lambda function. The event
+// payload is a DynamoDB-typed JSON document and the response is the plain
+// JSON result.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+func main() {
+	t := transform.New()
+	lambda.Start(handler(t))
+}
+
+// handler returns the Lambda handler converting each event with t.
+func handler(t *transform.Transformer) func(context.Context, json.RawMessage) (map[string]interface{}, error) {
+	return func(ctx context.Context, event json.RawMessage) (map[string]interface{}, error) {
+		// Parse the raw event ourselves so large integers keep their precision
+		inputMap, err := transform.ParseReader(bytes.NewReader(event))
+		if err != nil {
+			return nil, err
+		}
+		return t.TransformChecked(inputMap)
+	}
+}
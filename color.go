@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// ANSI escape sequences of the colors of JSON tokens.
+const (
+	colorKey    = "\x1b[34;1m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[36m"
+	colorBool   = "\x1b[33m"
+	colorNull   = "\x1b[90m"
+	colorReset  = "\x1b[0m"
+)
+
+// useColor reports whether JSON output is to be colorized for the --color
+// mode: always, never, or auto, which colorizes pretty-printed output to a
+// terminal unless the NO_COLOR environment variable is set.
+func useColor(mode string, toTerminal, pretty bool) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		return toTerminal && pretty && os.Getenv("NO_COLOR") == "", nil
+	default:
+		return false, fmt.Errorf("unknown color mode %q, want auto, always or never", mode)
+	}
+}
+
+// colorizeJSON returns the JSON text data with its object keys, strings,
+// numbers, booleans and nulls wrapped in ANSI colors, leaving punctuation
+// and whitespace as they are.
+func colorizeJSON(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(data) * 2)
+	for i := 0; i < len(data); {
+		end, color := i+1, ""
+		switch c := data[i]; {
+		case c == '"':
+			end = stringEnd(data, i)
+			color = colorString
+			if isObjectKey(data, end) {
+				color = colorKey
+			}
+		case c == '-' || c >= '0' && c <= '9':
+			for end < len(data) && bytes.IndexByte([]byte("+-.eE0123456789"), data[end]) >= 0 {
+				end++
+			}
+			color = colorNumber
+		case bytes.HasPrefix(data[i:], []byte("true")):
+			end, color = i+len("true"), colorBool
+		case bytes.HasPrefix(data[i:], []byte("false")):
+			end, color = i+len("false"), colorBool
+		case bytes.HasPrefix(data[i:], []byte("null")):
+			end, color = i+len("null"), colorNull
+		}
+		if color == "" {
+			buf.WriteByte(data[i])
+		} else {
+			buf.WriteString(color)
+			buf.Write(data[i:end])
+			buf.WriteString(colorReset)
+		}
+		i = end
+	}
+	return buf.Bytes()
+}
+
+// stringEnd returns the index just past the JSON string starting with the
+// quote at data[start], or len(data) if it is not terminated.
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// isObjectKey reports whether the string ending before data[end] is an
+// object key, followed by a colon.
+func isObjectKey(data []byte, end int) bool {
+	rest := bytes.TrimLeft(data[end:], " \t\r\n")
+	return len(rest) > 0 && rest[0] == ':'
+}
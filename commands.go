@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	summary string
+
+	// fileArgs reports whether the command takes file names as arguments.
+	fileArgs bool
+	// argWords lists the words the command accepts as arguments, if fixed.
+	argWords []string
+
+	// subcommands are run by naming them after the command, as in "gen
+	// schema", instead of the command itself.
+	subcommands []command
+
+	// setup registers the command's flags on fs and returns the function
+	// running the command once fs has been parsed from args.
+	setup func(fs *flag.FlagSet) func(args []string) error
+}
+
+// commands returns the subcommands of the CLI in the order they are listed.
+func commands() []command {
+	return []command{
+		{name: "transform", summary: "convert DynamoDB typed JSON into plain JSON (the default command)", setup: transformCommand},
+		{name: "gen", summary: "generate DynamoDB typed JSON from plain JSON", argWords: genTargets, setup: genCommand, subcommands: []command{
+			{name: "gen schema", summary: "infer a JSON Schema describing the transformed output", fileArgs: true, setup: genSchemaCommand},
+			{name: "gen go", summary: "generate Go structs matching the transformed output", fileArgs: true, setup: genGoCommand},
+			{name: "gen ts", summary: "generate TypeScript interfaces matching the transformed output", fileArgs: true, setup: genTSCommand},
+			{name: "gen proto", summary: "generate a .proto file modelling the transformed output", fileArgs: true, setup: genProtoCommand},
+		}},
+		{name: "validate", summary: "check typed JSON against the DynamoDB format", fileArgs: true, setup: validateCommand},
+		{name: "diff", summary: "compare the plain JSON produced from two typed documents", fileArgs: true, setup: diffCommand},
+		{name: "serve", summary: "serve transformations over HTTP and gRPC", setup: serveCommand},
+		{name: "version", summary: "print the version, git commit and build date", setup: versionCommand},
+		{name: "completion", summary: "print a bash, zsh or fish completion script", argWords: completionShells, setup: completionCommand},
+	}
+}
+
+// lookupCommand returns the subcommand called name.
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// run executes the CLI, returning an error that carries its exit code.
+// Arguments starting with a flag or an input file rather than a command
+// name run the transform command, so command lines written before
+// subcommands existed, such as "transformer a.json b.json", keep working.
+func run(args []string) error {
+	if len(args) > 0 && args[0] == "help" {
+		printUsage()
+		return nil
+	}
+	cmd, _ := lookupCommand("transform")
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		named, ok := lookupCommand(args[0])
+		switch {
+		case ok:
+			cmd, args = named, args[1:]
+		case isCommandName(args[0]):
+			printUsage()
+			return usageError(fmt.Errorf("unknown command %q", args[0]))
+		}
+	}
+	if len(args) > 0 {
+		for _, sub := range cmd.subcommands {
+			if sub.name == cmd.name+" "+args[0] {
+				cmd, args = sub, args[1:]
+				break
+			}
+		}
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	exec := cmd.setup(fs)
+	configureLogging := loggingFlags(fs)
+	configureTracing := tracingFlags(fs)
+	startProfiling := pprofFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := configureLogging(); err != nil {
+		return usageError(err)
+	}
+	flushTraces, err := configureTracing()
+	if err != nil {
+		return usageError(err)
+	}
+	defer flushTraces()
+	stopProfiling, err := startProfiling()
+	if err != nil {
+		return usageError(err)
+	}
+	defer stopProfiling()
+	return exec(args)
+}
+
+// isCommandName reports whether arg, which names no command, is more likely
+// a mistyped command than an input: a bare word that is not an existing file.
+func isCommandName(arg string) bool {
+	if strings.ContainsAny(arg, "./\\:") {
+		return false
+	}
+	_, err := os.Stat(arg)
+	return err != nil
+}
+
+// printUsage writes the list of subcommands to stderr.
+func printUsage() {
+	program := filepath.Base(os.Args[0])
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", program)
+	for _, cmd := range commands() {
+		fmt.Fprintf(os.Stderr, "  %-12s%s\n", cmd.name, cmd.summary)
+		for _, sub := range cmd.subcommands {
+			fmt.Fprintf(os.Stderr, "  %-12s%s\n", sub.name, sub.summary)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"%s <command> -h\" for the flags of a command.\n", program)
+}
+
+// genTargets names the subcommands of gen, which generate something other
+// than typed JSON.
+var genTargets = []string{"schema", "go", "ts", "proto"}
+
+// genCommand registers the flags of the gen command on fs and returns the
+// function running it. gen generates DynamoDB typed JSON from plain JSON: it
+// is the transform command with --reverse on by default, so it accepts the
+// same inputs and outputs.
+func genCommand(fs *flag.FlagSet) func(args []string) error {
+	exec := transformCommand(fs)
+	reverse := fs.Lookup("reverse")
+	reverse.DefValue = "true"
+	reverse.Value.Set("true")
+	return exec
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/parquetfile"
+)
+
+// completionShells are the shells completion scripts can be generated for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// flagArg describes what a flag takes as its value, for completion.
+type flagArg int
+
+const (
+	argNone flagArg = iota // boolean flags take no value
+	argText                // free-form values are not completed
+	argFile
+	argDir
+	argWords
+)
+
+// pathFlags names the flags whose value is a file or directory.
+var pathFlags = map[string]flagArg{
+	"config":          argFile,
+	"output":          argFile,
+	"rules":           argFile,
+	"validate-schema": argFile,
+	"avro-schema":     argFile,
+	"stats-file":      argFile,
+	"cpuprofile":      argFile,
+	"memprofile":      argFile,
+	"input-dir":       argDir,
+	"binary-dir":      argDir,
+	"plugins-dir":     argDir,
+}
+
+// flagWords returns the fixed values accepted by the named flag, if any.
+func flagWords(name string) []string {
+	switch name {
+	case "binary":
+		return []string{"base64", "hex", "file"}
+	case "epoch-unit":
+		return []string{"seconds", "milliseconds", "microseconds", "nanoseconds"}
+	case "compress":
+		return []string{"none", "gzip", "zstd"}
+	case "codec":
+		return codec.Names()
+	case "format":
+		return outputFormats
+	case "parquet-compression":
+		return parquetfile.CompressionNames()
+	case "key-case":
+		return []string{"preserve", "camel", "snake", "pascal", "kebab"}
+	case "on-error":
+		return []string{"default", "skip", "fail"}
+	case "log-level":
+		return []string{"debug", "info", "warn", "error"}
+	case "color":
+		return []string{"auto", "always", "never"}
+	case "log-format", "error-format":
+		return []string{"text", "json"}
+	default:
+		return nil
+	}
+}
+
+// completionFlag is a flag of a command as seen by completion scripts.
+type completionFlag struct {
+	name  string
+	usage string
+	arg   flagArg
+	words []string
+}
+
+// commandFlags returns the flags registered by cmd, in sorted order.
+func commandFlags(cmd command) []completionFlag {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cmd.setup(fs)
+	loggingFlags(fs)
+	tracingFlags(fs)
+	pprofFlags(fs)
+
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		cf := completionFlag{name: f.Name, usage: f.Usage, arg: argText}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			cf.arg = argNone
+		} else if arg, ok := pathFlags[f.Name]; ok {
+			cf.arg = arg
+		} else if words := flagWords(f.Name); words != nil {
+			cf.arg, cf.words = argWords, words
+		}
+		flags = append(flags, cf)
+	})
+	return flags
+}
+
+// completionCommand registers the flags of the completion command on fs and
+// returns the function running it, which prints a completion script for the
+// shell named by its argument, generated from the commands and their flags.
+func completionCommand(fs *flag.FlagSet) func(args []string) error {
+	programFlag := fs.String("program", filepath.Base(os.Args[0]), "Name of the installed program the script completes")
+
+	return func(_ []string) error {
+		if fs.NArg() != 1 {
+			return usageError(fmt.Errorf("completion needs a shell: %s", strings.Join(completionShells, ", ")))
+		}
+		var write func(w io.Writer, program string) error
+		switch fs.Arg(0) {
+		case "bash":
+			write = writeBashCompletion
+		case "zsh":
+			write = writeZshCompletion
+		case "fish":
+			write = writeFishCompletion
+		default:
+			return usageError(fmt.Errorf("unsupported shell %q, want %s", fs.Arg(0), strings.Join(completionShells, ", ")))
+		}
+		if *programFlag == "" {
+			return usageError(errors.New("--program must not be empty"))
+		}
+		if err := write(os.Stdout, *programFlag); err != nil {
+			return transformError(err)
+		}
+		return nil
+	}
+}
+
+// commandNames returns the names of every subcommand.
+func commandNames() []string {
+	var names []string
+	for _, cmd := range commands() {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+// shellFunc returns a shell function name derived from program.
+func shellFunc(program string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+// writeBashCompletion writes a bash completion script for program. Flags
+// are completed after "-", and values after flags that take files,
+// directories or a fixed set of words.
+func writeBashCompletion(w io.Writer, program string) error {
+	fn := shellFunc(program)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\" cmd=transform\n")
+	fmt.Fprintf(&b, "    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then\n        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n        return\n    fi\n", strings.Join(commandNames(), " "))
+	b.WriteString("    if [[ ${COMP_WORDS[1]} != -* ]]; then\n        cmd=${COMP_WORDS[1]}\n    fi\n")
+	b.WriteString("    case $cmd in\n")
+	for _, cmd := range commands() {
+		flags := commandFlags(cmd)
+		fmt.Fprintf(&b, "    %s)\n        case $prev in\n", cmd.name)
+		// Free-form values are left to the user
+		var textFlags []string
+		for _, f := range flags {
+			var reply string
+			switch f.arg {
+			case argNone:
+				continue
+			case argText:
+				textFlags = append(textFlags, "-"+f.name, "--"+f.name)
+				continue
+			case argFile:
+				reply = `COMPREPLY=($(compgen -f -- "$cur"))`
+			case argDir:
+				reply = `COMPREPLY=($(compgen -d -- "$cur"))`
+			case argWords:
+				reply = fmt.Sprintf(`COMPREPLY=($(compgen -W %q -- "$cur"))`, strings.Join(f.words, " "))
+			}
+			fmt.Fprintf(&b, "        -%[1]s|--%[1]s)\n            %[2]s\n            return\n            ;;\n", f.name, reply)
+		}
+		if textFlags != nil {
+			fmt.Fprintf(&b, "        %s)\n            return\n            ;;\n", strings.Join(textFlags, "|"))
+		}
+		b.WriteString("        esac\n")
+		names := make([]string, len(flags))
+		for i, f := range flags {
+			names[i] = "--" + f.name
+		}
+		fmt.Fprintf(&b, "        if [[ $cur == -* ]]; then\n            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+		switch {
+		case cmd.fileArgs:
+			b.WriteString("        else\n            COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+		case cmd.argWords != nil:
+			fmt.Fprintf(&b, "        else\n            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(cmd.argWords, " "))
+		}
+		b.WriteString("        fi\n        ;;\n")
+	}
+	b.WriteString("    esac\n}\n")
+	fmt.Fprintf(&b, "complete -o filenames -F %s %s\n", fn, program)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeZshCompletion writes a zsh completion script for program, describing
+// each command and flag with its usage text.
+func writeZshCompletion(w io.Writer, program string) error {
+	fn := shellFunc(program)
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n%s() {\n", program, fn)
+	b.WriteString("    local -a commands\n    commands=(\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(&b, "        %s\n", zshQuote(cmd.name+":"+zshEscape(cmd.summary)))
+	}
+	b.WriteString("    )\n")
+	b.WriteString("    local cmd=transform\n")
+	b.WriteString("    if (( CURRENT == 2 )) && [[ ${words[2]} != -* ]]; then\n        _describe command commands\n        return\n    fi\n")
+	b.WriteString("    if [[ ${words[2]} != -* ]]; then\n        cmd=${words[2]}\n        shift words\n        (( CURRENT-- ))\n    fi\n")
+	b.WriteString("    case $cmd in\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(&b, "    %s)\n        _arguments", cmd.name)
+		for _, f := range commandFlags(cmd) {
+			spec := "--" + f.name + "[" + zshEscape(f.usage) + "]"
+			switch f.arg {
+			case argText:
+				spec += ":value: "
+			case argFile:
+				spec += ":file:_files"
+			case argDir:
+				spec += ":directory:_files -/"
+			case argWords:
+				spec += ":value:(" + strings.Join(f.words, " ") + ")"
+			}
+			fmt.Fprintf(&b, " \\\n            %s", zshQuote(spec))
+		}
+		switch {
+		case cmd.fileArgs:
+			fmt.Fprintf(&b, " \\\n            %s", zshQuote("*:file:_files"))
+		case cmd.argWords != nil:
+			fmt.Fprintf(&b, " \\\n            %s", zshQuote("1:value:("+strings.Join(cmd.argWords, " ")+")"))
+		}
+		b.WriteString("\n        ;;\n")
+	}
+	b.WriteString("    esac\n}\n\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, program)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// zshEscape escapes the characters _arguments and _describe treat specially
+// in descriptions.
+func zshEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+}
+
+// zshQuote quotes s as a single-quoted zsh word.
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// writeFishCompletion writes a fish completion script for program. Flags
+// without a command apply to transform, the default command.
+func writeFishCompletion(w io.Writer, program string) error {
+	names := strings.Join(commandNames(), " ")
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", program)
+	fmt.Fprintf(&b, "complete -c %s -f\n", program)
+	for _, cmd := range commands() {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", program, cmd.name, fishQuote(cmd.summary))
+	}
+	for _, cmd := range commands() {
+		cond := "__fish_seen_subcommand_from " + cmd.name
+		if cmd.name == "transform" {
+			cond += "; or not __fish_seen_subcommand_from " + names
+		}
+		for _, f := range commandFlags(cmd) {
+			fmt.Fprintf(&b, "complete -c %s -n %s -l %s -d %s", program, fishQuote(cond), f.name, fishQuote(f.usage))
+			switch f.arg {
+			case argText:
+				b.WriteString(" -x")
+			case argFile:
+				b.WriteString(" -r -F")
+			case argDir:
+				b.WriteString(" -x -a '(__fish_complete_directories)'")
+			case argWords:
+				fmt.Fprintf(&b, " -x -a %s", fishQuote(strings.Join(f.words, " ")))
+			}
+			b.WriteString("\n")
+		}
+		switch {
+		case cmd.fileArgs:
+			fmt.Fprintf(&b, "complete -c %s -n %s -F\n", program, fishQuote(cond))
+		case cmd.argWords != nil:
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", program, fishQuote(cond), fishQuote(strings.Join(cmd.argWords, " ")))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fishQuote quotes s as a single-quoted fish word.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
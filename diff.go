@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/jsonpatch"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// diffCommand registers the flags of the diff command on fs and returns the
+// function running it, which transforms two typed JSON documents and prints
+// the differences between the results, one line per path or as a JSON Patch,
+// failing with exitDifferent if there are any. Either document may be "-" to
+// read it from stdin.
+func diffCommand(fs *flag.FlagSet) func(args []string) error {
+	rawFlag := fs.Bool("raw", false, "Compare the documents as they are instead of transforming them first")
+	patchFlag := fs.Bool("patch", false, "Print the differences as a JSON Patch (RFC 6902) turning the first document into the second")
+	newTransformer := transformerFlags(fs)
+	httpFlags(fs)
+
+	return func(_ []string) error {
+		if fs.NArg() != 2 {
+			return usageError(errors.New("diff needs two documents, e.g. diff old.json new.json"))
+		}
+
+		t, err := newTransformer(context.Background())
+		if err != nil {
+			return usageError(err)
+		}
+
+		docs := make([]map[string]interface{}, 2)
+		for i, path := range fs.Args() {
+			doc, err := parseInput(path, path == "-")
+			if err != nil {
+				return parseError(fmt.Errorf("%s: %w", path, err))
+			}
+			if !*rawFlag {
+				err = recoverTransform(func() error {
+					doc, err = t.TransformChecked(doc)
+					return err
+				})
+				if err != nil {
+					return transformError(err)
+				}
+			}
+			docs[i] = doc
+		}
+
+		if *patchFlag {
+			return printPatch(os.Stdout, jsonpatch.Diff(docs[0], docs[1]))
+		}
+
+		var lines []diffLine
+		diffValues("", docs[0], docs[1], &lines)
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lines[i].path < lines[j].path
+		})
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(os.Stdout, line.text); err != nil {
+				return transformError(err)
+			}
+		}
+		if len(lines) > 0 {
+			return differentError(fmt.Errorf("%d differences found", len(lines)))
+		}
+		return nil
+	}
+}
+
+// printPatch writes patch to w as an indented JSON array, failing with
+// exitDifferent unless it is empty.
+func printPatch(w io.Writer, patch []jsonpatch.Operation) error {
+	out, err := marshalOutput(patch, "  ", false)
+	if err != nil {
+		return transformError(err)
+	}
+	if _, err := fmt.Fprintln(w, string(out)); err != nil {
+		return transformError(err)
+	}
+	if len(patch) > 0 {
+		return differentError(fmt.Errorf("%d differences found", len(patch)))
+	}
+	return nil
+}
+
+// diffLine is one difference between two documents.
+type diffLine struct {
+	path string
+	text string
+}
+
+// diffValues appends the differences between a and b, found at path, to
+// lines: "-" for values only in a, "+" for values only in b and "~" for
+// values that changed. Objects are compared key by key and arrays index by index.
+func diffValues(path string, a, b interface{}, lines *[]diffLine) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			for key, value := range av {
+				if other, ok := bv[key]; ok {
+					diffValues(diffPath(path, key), value, other, lines)
+				} else {
+					addDiff(lines, "-", diffPath(path, key), value)
+				}
+			}
+			for key, value := range bv {
+				if _, ok := av[key]; !ok {
+					addDiff(lines, "+", diffPath(path, key), value)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				elemPath := diffPath(path, strconv.Itoa(i))
+				switch {
+				case i >= len(bv):
+					addDiff(lines, "-", elemPath, av[i])
+				case i >= len(av):
+					addDiff(lines, "+", elemPath, bv[i])
+				default:
+					diffValues(elemPath, av[i], bv[i], lines)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*lines = append(*lines, diffLine{
+			path: path,
+			text: fmt.Sprintf("~ %s: %s -> %s", path, diffValue(a), diffValue(b)),
+		})
+	}
+}
+
+// addDiff appends a line reporting value as added or removed at path.
+func addDiff(lines *[]diffLine, sign, path string, value interface{}) {
+	*lines = append(*lines, diffLine{path: path, text: fmt.Sprintf("%s %s: %s", sign, path, diffValue(value))})
+}
+
+// diffPath appends key to the dotted path.
+func diffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// diffValue returns the compact, key-sorted JSON encoding of v.
+func diffValue(v interface{}) string {
+	out, err := transform.MarshalSorted(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
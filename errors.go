@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Exit codes reported by the CLI.
+const (
+	exitOK        = 0
+	exitUsage     = 1
+	exitParse     = 2
+	exitTransform = 3
+	exitDifferent = 4
+)
+
+// errFlagsReported marks command-line errors the flag package has already
+// printed along with the usage text.
+var errFlagsReported = errors.New("invalid command line")
+
+// exitError associates an error with the exit code it should produce.
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error returns the message of the underlying error.
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// fileError associates an error with the input file it occurred in. The
+// file is reported alongside the message rather than within it.
+type fileError struct {
+	file string
+	err  error
+}
+
+// Error returns the message of the underlying error.
+func (e *fileError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *fileError) Unwrap() error {
+	return e.err
+}
+
+// usageError marks err as a command-line usage error.
+func usageError(err error) error {
+	return &exitError{code: exitUsage, err: err}
+}
+
+// parseError marks err as a failure to read or parse the input.
+func parseError(err error) error {
+	return &exitError{code: exitParse, err: err}
+}
+
+// transformError marks err as a failure to transform or write the output.
+func transformError(err error) error {
+	return &exitError{code: exitTransform, err: err}
+}
+
+// differentError marks err as the documents compared by diff differing.
+func differentError(err error) error {
+	return &exitError{code: exitDifferent, err: err}
+}
+
+// exitCode returns the exit code for err, treating unclassified errors as
+// transform errors.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitTransform
+}
+
+// streamError classifies an error from a record stream, which may fail either
+// while decoding the input or while transforming and writing the output.
+func streamError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, transform.ErrTrailingData) {
+		return parseError(err)
+	}
+	return transformError(err)
+}
+
+// recoverTransform runs fn, turning a panic raised by a transformation rule
+// into an error so the CLI can report it and exit cleanly.
+func recoverTransform(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("transform failed: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// errorDetails is a failure as written by --error-format json.
+type errorDetails struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Path    string `json:"path,omitempty"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// describeError returns the details of err: its exit code and message, and
+// the JSON Pointer, input file and line it occurred at when known. Records of
+// newline-delimited streams are located by their line.
+func describeError(err error) errorDetails {
+	d := errorDetails{Code: exitCode(err), Message: err.Error()}
+	var pathErr *transform.PathError
+	var dupErr *transform.DuplicateKeyError
+	var limitErr *transform.LimitError
+	if errors.As(err, &pathErr) {
+		d.Path = pathErr.Pointer
+	} else if errors.As(err, &dupErr) {
+		d.Path = dupErr.Pointers[0]
+	} else if errors.As(err, &limitErr) {
+		d.Path = limitErr.Pointer
+	}
+	var fileErr *fileError
+	if errors.As(err, &fileErr) {
+		d.File = fileErr.file
+	}
+	var syntaxErr *transform.SyntaxError
+	var recordErr *transform.RecordError
+	if errors.As(err, &syntaxErr) {
+		d.Line = syntaxErr.Line
+	} else if errors.As(err, &recordErr) {
+		d.Line = recordErr.Index + 1
+	}
+	return d
+}
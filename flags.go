@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/goplugin"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/httpio"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemacheck"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// parseOptionFlags registers the flags that limit or check the input of
+// whole-document parsing on fs and returns a function returning the selected
+// options once fs has been parsed.
+func parseOptionFlags(fs *flag.FlagSet) func() []transform.ParseOption {
+	rejectDuplicatesFlag := fs.Bool("reject-duplicate-keys", false, "Fail on input objects holding the same key twice, reporting their paths, instead of keeping the last value")
+	maxInputSizeFlag := fs.Int64("max-input-size", 0, "Fail on input documents larger than this many bytes after decompression; 0 means no limit")
+	maxDepthFlag := fs.Int("max-depth", 0, "Fail on input nesting objects and arrays, type tags included, deeper than this; 0 means no limit")
+	allowCommentsFlag := fs.Bool("allow-comments", false, "Accept // and /* */ comments and trailing commas in the input, as in JSONC files; always on for .jsonc and .json5 files")
+	maxKeysFlag := fs.Int("max-keys", 0, "Fail on input documents holding more than this many keys in total, type tags included; 0 means no limit")
+
+	return func() []transform.ParseOption {
+		var opts []transform.ParseOption
+		if *rejectDuplicatesFlag {
+			opts = append(opts, transform.RejectDuplicateKeys())
+		}
+		if *maxInputSizeFlag > 0 {
+			opts = append(opts, transform.MaxInputSize(*maxInputSizeFlag))
+		}
+		if *maxDepthFlag > 0 {
+			opts = append(opts, transform.MaxDepth(*maxDepthFlag))
+		}
+		if *maxKeysFlag > 0 {
+			opts = append(opts, transform.MaxKeys(*maxKeysFlag))
+		}
+		if *allowCommentsFlag {
+			opts = append(opts, transform.AllowComments())
+		}
+		return opts
+	}
+}
+
+// transformerFlags registers the flags that configure a Transformer on fs and
+// returns a function that builds the Transformer once fs has been parsed,
+// applying extra after the options selected by the flags. WebAssembly rules
+// plugins are called within ctx.
+func transformerFlags(fs *flag.FlagSet) func(ctx context.Context, extra ...transform.Option) (*transform.Transformer, error) {
+	reverseSetsFlag := fs.Bool("reverse-sets", false, "With --reverse, encode arrays of unique strings or numbers as SS/NS sets")
+	strictFlag := fs.Bool("strict", false, "Omit fields with invalid values or unknown type keys instead of using defaults")
+	onErrorFlag := fs.String("on-error", "default", "What to do with an invalid value: default replaces it with its type's default, skip omits it and fail aborts the run")
+	epochUnitFlag := fs.String("epoch-unit", "seconds", "Unit for converted timestamps: seconds, milliseconds, microseconds or nanoseconds")
+	var dateLayouts stringList
+	fs.Var(&dateLayouts, "date-layout", "Additional date layout tried after RFC3339, e.g. 2006-01-02, RFC1123 or epoch (repeatable)")
+	floatNumbersFlag := fs.Bool("float-numbers", false, "Parse every N value into float64 instead of preserving integer precision")
+	listPassthroughFlag := fs.Bool("list-passthrough", false, "Keep plain scalars in L values instead of dropping them")
+	binaryFlag := fs.String("binary", "base64", "Output format for B values: base64, hex or file")
+	binaryDirFlag := fs.String("binary-dir", ".", "Directory B values are written to with --binary file")
+	codecFlag := fs.String("codec", codec.Default().Name(), "JSON implementation: "+strings.Join(codec.Names(), ", "))
+	escapeHTMLFlag := fs.Bool("escape-html", false, "Escape <, > and & in output strings as \\u003c, \\u003e and \\u0026, as encoding/json does by default, for consumers embedding the JSON in HTML")
+	rulesFlag := fs.String("rules", "", "Load custom rules from this file: a YAML rules configuration, a JSON object mapping type tags to CEL expressions, a Lua or Starlark (.star) script, or a WebAssembly plugin")
+	pluginsDirFlag := fs.String("plugins-dir", "", "Load rules from every Go plugin (.so) in this directory")
+	var includePaths, excludePaths stringList
+	fs.Var(&includePaths, "include", "Keep only the attributes at these comma-separated dotted key paths, e.g. user.email,items.*.price (repeatable)")
+	fs.Var(&excludePaths, "exclude", "Drop the attributes at these comma-separated dotted key paths, e.g. user.password (repeatable)")
+	validateSchemaFlag := fs.String("validate-schema", "", "Check every transformed document against the JSON Schema (draft 2020-12) in this file")
+	keyCaseFlag := fs.String("key-case", "preserve", "Rewrite output keys at every level to this convention: preserve, camel, snake, pascal or kebab")
+	parallelFlag := fs.Int("parallel", 1, "Transform top-level attributes, or NDJSON records, on up to this many goroutines")
+	flattenFlag := fs.Bool("flatten", false, "Flatten nested objects in the output into keys joining their key paths, e.g. address.city; with --reverse, unflatten the input instead")
+	unflattenFlag := fs.Bool("unflatten", false, "Split output keys holding the --flatten-delimiter into nested objects; with --reverse, flatten the input instead")
+	delimiterFlag := fs.String("flatten-delimiter", ".", "Separator between the keys of a path for --flatten and --unflatten")
+	extendedJSONFlag := fs.Bool("extended-json", false, "Write MongoDB Extended JSON v2 in canonical mode, wrapping numbers in $numberInt, $numberLong, $numberDouble or $numberDecimal, timestamps in $date and B values in $binary, for mongoimport")
+
+	return func(ctx context.Context, extra ...transform.Option) (*transform.Transformer, error) {
+		if err := codec.Use(*codecFlag); err != nil {
+			return nil, err
+		}
+		codec.SetEscapeHTML(*escapeHTMLFlag)
+
+		binaryFormat, err := transform.ParseBinaryFormat(*binaryFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		epochUnit, err := transform.ParseEpochUnit(*epochUnitFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		onError, err := transform.ParseErrorMode(*onErrorFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		keyCase, err := transform.ParseKeyCase(*keyCaseFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		include, err := parseFieldPaths(includePaths)
+		if err != nil {
+			return nil, fmt.Errorf("--include: %w", err)
+		}
+		exclude, err := parseFieldPaths(excludePaths)
+		if err != nil {
+			return nil, fmt.Errorf("--exclude: %w", err)
+		}
+
+		if *flattenFlag && *unflattenFlag {
+			return nil, errors.New("--flatten and --unflatten cannot be combined")
+		}
+		if *delimiterFlag == "" {
+			return nil, errors.New("--flatten-delimiter cannot be empty")
+		}
+
+		var ruleOpts []transform.Option
+		if *pluginsDirFlag != "" {
+			rules, err := goplugin.LoadDir(*pluginsDirFlag)
+			if err != nil {
+				return nil, err
+			}
+			for typeKey, rule := range rules {
+				ruleOpts = append(ruleOpts, transform.WithRule(typeKey, rule))
+			}
+		}
+		if *rulesFlag != "" {
+			opts, err := loadRules(ctx, *rulesFlag)
+			if err != nil {
+				return nil, err
+			}
+			ruleOpts = append(ruleOpts, opts...)
+		}
+
+		if *validateSchemaFlag != "" {
+			schema, err := schemacheck.Load(*validateSchemaFlag)
+			if err != nil {
+				return nil, err
+			}
+			ruleOpts = append(ruleOpts, schema.Option())
+		}
+
+		// Warn about missing required fields and schema violations unless
+		// they fail the run
+		ruleOpts = append(ruleOpts, transform.WithWarningHandler(logOutputWarning))
+
+		// Log the key path of omitted values when debugging
+		if debugEnabled() {
+			ruleOpts = append(ruleOpts, transform.WithMiddleware(logOmitted))
+		}
+		// Log the rule applied to every value and the keys dropped with -vv
+		if traceEnabled() {
+			ruleOpts = append(ruleOpts, transform.WithMiddleware(logRules), transform.WithDroppedKeyHandler(logDroppedKey))
+		}
+
+		// Flags come after the rules so explicitly given ones take precedence
+		// over a rules configuration file
+		opts := append(ruleOpts,
+			transform.WithStrict(*strictFlag),
+			transform.WithOnError(onError),
+			transform.WithDateLayouts(dateLayouts...),
+			transform.WithFloatNumbers(*floatNumbersFlag),
+			transform.WithListPassthrough(*listPassthroughFlag),
+			transform.WithReverseSets(*reverseSetsFlag),
+			transform.WithBinaryFormat(binaryFormat),
+			transform.WithBinaryDir(*binaryDirFlag),
+			transform.WithKeyCase(keyCase),
+			transform.WithInclude(include...),
+			transform.WithExclude(exclude...),
+			transform.WithParallelism(*parallelFlag),
+		)
+		if isFlagSet(fs, "epoch-unit") || *rulesFlag == "" {
+			opts = append(opts, transform.WithEpochUnit(epochUnit))
+		}
+		if *extendedJSONFlag {
+			opts = append(opts, transform.WithExtendedJSON(true))
+		}
+		if *flattenFlag {
+			opts = append(opts, transform.WithFlatten(*delimiterFlag))
+		}
+		if *unflattenFlag {
+			opts = append(opts, transform.WithUnflatten(*delimiterFlag))
+		}
+		return transform.New(append(opts, extra...)...), nil
+	}
+}
+
+// envPrefix starts the name of the environment variable that sets each flag.
+const envPrefix = "TRANSFORMER_"
+
+// httpOptions configure how http:// and https:// inputs are fetched, as set
+// by the flags registered with httpFlags.
+var httpOptions httpio.Options
+
+// httpFlags registers the flags that configure how http:// and https://
+// inputs are fetched on fs.
+func httpFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&httpOptions.Timeout, "http-timeout", 30*time.Second, "Timeout of each attempt at fetching an http(s) input, including reading its body; 0 sets none")
+	fs.IntVar(&httpOptions.Retries, "http-retries", 3, "Number of times a failed fetch of an http(s) input is retried, with exponential backoff")
+	fs.DurationVar(&httpOptions.Backoff, "http-backoff", httpio.DefaultBackoff, "Delay before the first retry of an http(s) input, doubled for each retry after it")
+	fs.StringVar(&httpOptions.Token, "http-token", "", "Bearer token sent in the Authorization header when fetching http(s) inputs")
+}
+
+// parseFlags parses args into fs, reporting flag errors as usage errors.
+// Flags not given in args are then taken from their environment variables,
+// so explicit flags take precedence over the environment, which takes
+// precedence over the defaults.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return usageError(errFlagsReported)
+	}
+	if err := setFlagsFromEnv(fs); err != nil {
+		return usageError(err)
+	}
+	return nil
+}
+
+// setFlagsFromEnv sets every flag in fs that was not given explicitly from
+// its environment variable, if set. Flags set this way count as explicitly
+// given, as if they had been passed on the command line.
+func setFlagsFromEnv(fs *flag.FlagSet) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || given[f.Name] {
+			return
+		}
+		name := envName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %w", value, name, setErr)
+		}
+	})
+	return err
+}
+
+// envName returns the environment variable for the named flag, e.g.
+// TRANSFORMER_BATCH_SIZE for --batch-size.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeatable flag.
+type stringList []string
+
+// String returns the collected values separated by commas.
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set appends a value each time the flag is given.
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// fileList is a flag.Value collecting the files named by every occurrence
+// of a repeatable flag, replacing its default files with the first one.
+type fileList struct {
+	files []string
+	// given reports whether the flag was given at least once
+	given bool
+}
+
+// String returns the files separated by commas.
+func (l *fileList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(l.files, ",")
+}
+
+// Set appends a file each time the flag is given.
+func (l *fileList) Set(value string) error {
+	if !l.given {
+		l.files, l.given = nil, true
+	}
+	l.files = append(l.files, value)
+	return nil
+}
+
+// byteSize is a flag.Value holding a number of bytes, given as a plain
+// number or with a unit such as 512KB, 100MB or 1GiB.
+type byteSize int64
+
+// String returns the number of bytes.
+func (b *byteSize) String() string {
+	return strconv.FormatInt(int64(*b), 10)
+}
+
+// Set parses a size with an optional decimal (KB, MB, GB) or binary (KiB,
+// MiB, GiB) unit.
+func (b *byteSize) Set(value string) error {
+	units := []struct {
+		suffix string
+		scale  int64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+		{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"B", 1},
+	}
+	number, scale := strings.TrimSpace(value), int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(strings.ToUpper(number), strings.ToUpper(unit.suffix)) {
+			number, scale = strings.TrimSpace(number[:len(number)-len(unit.suffix)]), unit.scale
+			break
+		}
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid size %q", value)
+	}
+	*b = byteSize(n * scale)
+	return nil
+}
+
+// parseFieldPaths parses the comma-separated field paths of every occurrence
+// of a repeatable flag.
+func parseFieldPaths(values stringList) ([]transform.FieldPath, error) {
+	var paths []transform.FieldPath
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			p, err := transform.ParseFieldPath(strings.TrimSpace(s))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// isFlagSet reports whether the named flag was explicitly given in fs.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// flagIsTrue reports whether the named boolean flag of fs is set to true.
+func flagIsTrue(fs *flag.FlagSet, name string) bool {
+	f := fs.Lookup(name)
+	return f != nil && f.Value.String() == "true"
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/avrofile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/cborfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/csvfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/msgpackfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/parquetfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/tomlfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// outputFormats are the values of --format.
+var outputFormats = []string{"json", "avro", "parquet", "csv", "toml", "msgpack", "cbor"}
+
+// recordWriter writes documents in an output format other than JSON.
+type recordWriter interface {
+	Write(doc map[string]interface{}) error
+	Close() error
+}
+
+// encoder wraps a function writing transformed JSON documents one after
+// another, so that the documents reach the underlying writer in another
+// output format instead.
+type encoder func(write func(io.Writer) error) func(io.Writer) error
+
+// outputFormatFlags registers the flags selecting the output format on fs and
+// returns a function returning the encoder of the selected format once fs
+// has been parsed, or nil for JSON.
+func outputFormatFlags(fs *flag.FlagSet) func() (encoder, error) {
+	formatFlag := fs.String("format", "json", "Output format: json, avro for an Avro Object Container File, parquet for a Parquet file, csv for a CSV file with a header row of the transformed records, nested objects flattened into dotted columns, toml for a single document as TOML, msgpack for a stream of MessagePack maps or cbor for a sequence of CBOR maps")
+	avroSchemaFlag := fs.String("avro-schema", "", "With --format avro, write records of the Avro schema in this file instead of one inferred from the output")
+	parquetCompressionFlag := fs.String("parquet-compression", "snappy", "With --format parquet, compress pages with "+strings.Join(parquetfile.CompressionNames(), ", "))
+
+	return func() (encoder, error) {
+		if *avroSchemaFlag != "" && *formatFlag != "avro" {
+			return nil, errors.New("--avro-schema needs --format avro")
+		}
+		if isFlagSet(fs, "parquet-compression") && *formatFlag != "parquet" {
+			return nil, errors.New("--parquet-compression needs --format parquet")
+		}
+		switch *formatFlag {
+		case "json":
+			return nil, nil
+		case "avro":
+			return avroEncoder(*avroSchemaFlag), nil
+		case "parquet":
+			codec, err := parquetfile.ParseCompression(*parquetCompressionFlag)
+			if err != nil {
+				return nil, err
+			}
+			return inferringEncoder(func(w io.Writer, schema map[string]interface{}) (recordWriter, error) {
+				return parquetfile.NewWriter(w, schema, codec)
+			}), nil
+		case "csv":
+			return inferringEncoder(func(w io.Writer, schema map[string]interface{}) (recordWriter, error) {
+				return csvfile.NewWriter(w, schema)
+			}), nil
+		case "toml":
+			return recordEncoder(func(w io.Writer) (recordWriter, error) {
+				return tomlfile.NewWriter(w), nil
+			}), nil
+		case "msgpack":
+			return recordEncoder(func(w io.Writer) (recordWriter, error) {
+				return msgpackfile.NewWriter(w), nil
+			}), nil
+		case "cbor":
+			return recordEncoder(func(w io.Writer) (recordWriter, error) {
+				return cborfile.NewWriter(w)
+			}), nil
+		default:
+			return nil, fmt.Errorf("unknown output format %q, want %s", *formatFlag, strings.Join(outputFormats, ", "))
+		}
+	}
+}
+
+// avroEncoder returns the encoder writing documents as records of an Avro
+// Object Container File. With a schema file, records are converted as they
+// are transformed; otherwise every record is held until the schema has been
+// inferred from all of them.
+func avroEncoder(schemaFile string) encoder {
+	return func(write func(io.Writer) error) func(io.Writer) error {
+		if schemaFile == "" {
+			return inferringEncoder(func(w io.Writer, schema map[string]interface{}) (recordWriter, error) {
+				avroSchema, err := avrofile.Infer(schema, "Document")
+				if err != nil {
+					return nil, err
+				}
+				return avrofile.NewWriter(w, avroSchema)
+			})(write)
+		}
+		return recordEncoder(func(w io.Writer) (recordWriter, error) {
+			schema, err := readAvroSchema(schemaFile)
+			if err != nil {
+				return nil, err
+			}
+			aw, err := avrofile.NewWriter(w, schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", schemaFile, err)
+			}
+			return aw, nil
+		})(write)
+	}
+}
+
+// recordEncoder returns the encoder passing every document to the record
+// writer open returns as soon as it has been transformed.
+func recordEncoder(open func(w io.Writer) (recordWriter, error)) encoder {
+	return func(write func(io.Writer) error) func(io.Writer) error {
+		return func(w io.Writer) error {
+			rw, err := open(w)
+			if err != nil {
+				return err
+			}
+			if err := decodePiped(write, rw.Write); err != nil {
+				return err
+			}
+			return rw.Close()
+		}
+	}
+}
+
+// canonicalEncoder is the encoder rewriting every JSON document in the JSON
+// Canonicalization Scheme, one per line.
+func canonicalEncoder(write func(io.Writer) error) func(io.Writer) error {
+	return recordEncoder(func(w io.Writer) (recordWriter, error) {
+		return canonicalWriter{w: w}, nil
+	})(write)
+}
+
+// canonicalWriter writes documents as canonical JSON lines.
+type canonicalWriter struct {
+	w io.Writer
+}
+
+// Write writes doc as a line of canonical JSON.
+func (cw canonicalWriter) Write(doc map[string]interface{}) error {
+	out, err := transform.MarshalCanonical(doc)
+	if err != nil {
+		return err
+	}
+	_, err = cw.w.Write(append(out, '\n'))
+	return err
+}
+
+// Close does nothing, as every document is written at once.
+func (cw canonicalWriter) Close() error {
+	return nil
+}
+
+// inferringEncoder returns the encoder holding every document until all have
+// been written, and then writing them with the record writer open returns
+// for the JSON Schema inferred from them.
+func inferringEncoder(open func(w io.Writer, schema map[string]interface{}) (recordWriter, error)) encoder {
+	return func(write func(io.Writer) error) func(io.Writer) error {
+		return func(w io.Writer) error {
+			var buf bytes.Buffer
+			if err := write(&buf); err != nil {
+				return err
+			}
+			var docs []map[string]interface{}
+			inferrer := schemagen.New()
+			err := decodeDocuments(&buf, func(doc map[string]interface{}) error {
+				docs = append(docs, doc)
+				inferrer.Add(doc)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if len(docs) == 0 {
+				return errors.New("no records to infer the output schema from")
+			}
+
+			rw, err := open(w, inferrer.Schema())
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				if err := rw.Write(doc); err != nil {
+					return err
+				}
+			}
+			return rw.Close()
+		}
+	}
+}
+
+// readAvroSchema returns the Avro schema in the named local file or S3
+// object.
+func readAvroSchema(file string) (string, error) {
+	in, err := openInput(context.Background(), file, false)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	schema, err := io.ReadAll(in)
+	if err != nil {
+		return "", err
+	}
+	return string(schema), nil
+}
+
+// decodePiped runs write in its own goroutine and passes every document it
+// writes to fn as soon as it has been decoded. It returns the error of write,
+// which fails in turn when fn or decoding does, or else the error of
+// decoding.
+func decodePiped(write func(io.Writer) error, fn func(map[string]interface{}) error) error {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := write(pw)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	err := decodeDocuments(pr, fn)
+	// Unblock write when decoding stopped early
+	pr.CloseWithError(err)
+	if writeErr := <-done; writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// decodeDocuments passes every JSON document read from r to fn.
+func decodeDocuments(r io.Reader, fn func(map[string]interface{}) error) error {
+	dec := codec.Default().NewDecoder(r)
+	for {
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+}
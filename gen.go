@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/gogen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/protogen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/tsgen"
+)
+
+// genSchemaCommand registers the flags of the gen schema command on fs and
+// returns the function running it, which prints a JSON Schema describing
+// every document selected by describedDocuments.
+func genSchemaCommand(fs *flag.FlagSet) func(args []string) error {
+	outputFlag := fs.String("output", "", "Write the schema to this file or s3://bucket/key URL instead of stdout")
+	infer := describedDocuments(fs)
+
+	return func(_ []string) error {
+		schema, err := infer()
+		if err != nil {
+			return err
+		}
+		err = writeOutput(context.Background(), *outputFlag, compress.FormatOf(*outputFlag), func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(schema)
+		})
+		if err != nil {
+			return transformError(err)
+		}
+		return nil
+	}
+}
+
+// genGoCommand registers the flags of the gen go command on fs and returns
+// the function running it, which prints Go struct definitions with json tags
+// matching every document selected by describedDocuments, so consumers can
+// unmarshal them into typed values.
+func genGoCommand(fs *flag.FlagSet) func(args []string) error {
+	outputFlag := fs.String("output", "", "Write the Go source to this file or s3://bucket/key URL instead of stdout")
+	packageFlag := fs.String("package", "main", "Package clause of the generated source")
+	typeFlag := fs.String("type", "Document", "Name of the struct type describing a whole document")
+	infer := describedDocuments(fs)
+
+	return func(_ []string) error {
+		schema, err := infer()
+		if err != nil {
+			return err
+		}
+		src, err := gogen.Generate(schema, *packageFlag, *typeFlag)
+		if err != nil {
+			return usageError(err)
+		}
+		err = writeOutput(context.Background(), *outputFlag, compress.FormatOf(*outputFlag), func(w io.Writer) error {
+			_, err := w.Write(src)
+			return err
+		})
+		if err != nil {
+			return transformError(err)
+		}
+		return nil
+	}
+}
+
+// genTSCommand registers the flags of the gen ts command on fs and returns
+// the function running it, which prints TypeScript interfaces matching every
+// document selected by describedDocuments.
+func genTSCommand(fs *flag.FlagSet) func(args []string) error {
+	outputFlag := fs.String("output", "", "Write the TypeScript source to this file or s3://bucket/key URL instead of stdout")
+	typeFlag := fs.String("type", "Document", "Name of the interface describing a whole document")
+	infer := describedDocuments(fs)
+
+	return func(_ []string) error {
+		schema, err := infer()
+		if err != nil {
+			return err
+		}
+		src, err := tsgen.Generate(schema, *typeFlag)
+		if err != nil {
+			return usageError(err)
+		}
+		err = writeOutput(context.Background(), *outputFlag, compress.FormatOf(*outputFlag), func(w io.Writer) error {
+			_, err := w.Write(src)
+			return err
+		})
+		if err != nil {
+			return transformError(err)
+		}
+		return nil
+	}
+}
+
+// genProtoCommand registers the flags of the gen proto command on fs and
+// returns the function running it, which prints a proto3 file with messages
+// modelling every document selected by describedDocuments.
+func genProtoCommand(fs *flag.FlagSet) func(args []string) error {
+	outputFlag := fs.String("output", "", "Write the .proto file to this file or s3://bucket/key URL instead of stdout")
+	packageFlag := fs.String("package", "documents.v1", "Proto package of the generated file")
+	goPackageFlag := fs.String("go-package", "", "Set the go_package option of the generated file")
+	typeFlag := fs.String("type", "Document", "Name of the message describing a whole document")
+	infer := describedDocuments(fs)
+
+	return func(_ []string) error {
+		schema, err := infer()
+		if err != nil {
+			return err
+		}
+		src, err := protogen.Generate(schema, *typeFlag, protogen.Options{Package: *packageFlag, GoPackage: *goPackageFlag})
+		if err != nil {
+			return usageError(err)
+		}
+		err = writeOutput(context.Background(), *outputFlag, compress.FormatOf(*outputFlag), func(w io.Writer) error {
+			_, err := w.Write(src)
+			return err
+		})
+		if err != nil {
+			return transformError(err)
+		}
+		return nil
+	}
+}
+
+// describedDocuments registers the flags selecting the documents the gen
+// schema, gen go, gen ts and gen proto commands describe on fs, and returns a function that
+// transforms them and infers their JSON Schema once fs has been parsed.
+// Documents are read from the files given as arguments, "-" meaning stdin,
+// or else from --config or piped stdin.
+func describedDocuments(fs *flag.FlagSet) func() (map[string]interface{}, error) {
+	schemaFlag := fs.String("config", "schema.json", "Used to read the json file, either a local path, an s3://bucket/key URL or an http(s):// URL")
+	ndjsonFlag := fs.Bool("ndjson", false, "Read newline-delimited JSON records, each one a document")
+	plainFlag := fs.Bool("plain", false, "Describe documents that are already plain JSON instead of transforming typed JSON")
+	parseOpts := parseOptionFlags(fs)
+	newTransformer := transformerFlags(fs)
+	httpFlags(fs)
+
+	return func() (map[string]interface{}, error) {
+		t, err := newTransformer(context.Background())
+		if err != nil {
+			return nil, usageError(err)
+		}
+		opts := parseOpts()
+		if len(opts) > 0 && *ndjsonFlag {
+			return nil, usageError(errors.New("--reject-duplicate-keys and the --max-* input limits need whole documents and cannot be combined with --ndjson"))
+		}
+
+		inferrer := schemagen.New()
+		add := func(doc map[string]interface{}) error {
+			if !*plainFlag {
+				var err error
+				if doc, err = t.TransformChecked(doc); err != nil {
+					return transformError(err)
+				}
+			}
+			inferrer.Add(doc)
+			return nil
+		}
+
+		if fs.NArg() == 0 {
+			// Read from stdin when input is piped in and no --config flag is given
+			useStdin := !isFlagSet(fs, "config") && stdinIsPiped()
+			if err := readDocuments(*schemaFlag, useStdin, *ndjsonFlag, opts, add); err != nil {
+				if useStdin {
+					return nil, err
+				}
+				return nil, &fileError{file: *schemaFlag, err: err}
+			}
+		}
+		for _, name := range fs.Args() {
+			if err := readDocuments(name, name == "-", *ndjsonFlag, opts, add); err != nil {
+				return nil, &fileError{file: name, err: err}
+			}
+		}
+		return inferrer.Schema(), nil
+	}
+}
+
+// readDocuments passes the document read from the named input, or with
+// ndjson each of its records, to fn. Failures to read input are returned as
+// parse errors and errors from fn as they are.
+func readDocuments(file string, useStdin, ndjson bool, opts []transform.ParseOption, fn func(map[string]interface{}) error) error {
+	if !ndjson {
+		doc, err := readDocument(context.Background(), file, useStdin, opts...)
+		if err != nil {
+			return parseError(err)
+		}
+		return fn(doc)
+	}
+
+	in, err := openInput(context.Background(), file, useStdin)
+	if err != nil {
+		return parseError(err)
+	}
+	defer in.Close()
+
+	dec := transform.NewRecordDecoder(in)
+	for index := 0; ; index++ {
+		var record map[string]interface{}
+		if err := dec.Decode(&record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return parseError(&transform.RecordError{Index: index, Err: err})
+		}
+		if err := fn(record); err != nil {
+			return &transform.RecordError{Index: index, Err: err}
+		}
+	}
+}
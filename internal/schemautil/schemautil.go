@@ -0,0 +1,85 @@
+// Package schemautil holds the helpers shared by the packages that read
+// inferred JSON Schemas, generate code from them or convert documents to
+// other formats: reading schema keywords, naming generated types and
+// building the JSON Pointers that locate errors.
+package schemautil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StringList returns v as a list of strings, whether it is a single string,
+// a []string or a decoded JSON array.
+func StringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var list []string
+		for _, elem := range v {
+			if s, ok := elem.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+// Nullable reports whether schema allows null.
+func Nullable(schema map[string]interface{}) bool {
+	for _, t := range StringList(schema["type"]) {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// Unique returns name, or name with the lowest numeric suffix that makes it
+// unique, and records it in used.
+func Unique(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// Singular returns the name of one element of a list named name, dropping a
+// plural ending.
+func Singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ies") && len(name) > 4:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 3:
+		return strings.TrimSuffix(name, "s")
+	default:
+		return name + "Item"
+	}
+}
+
+// JoinPointer appends key to the JSON Pointer pointer as a reference token,
+// escaping "~" and "/" within it.
+func JoinPointer(pointer, key string) string {
+	return pointer + "/" + pointerEscaper.Replace(key)
+}
+
+// pointerEscaper escapes a key for use as a JSON Pointer reference token.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// DescribePointer returns the JSON Pointer pointer for error messages,
+// naming the whole document when it is empty.
+func DescribePointer(pointer string) string {
+	if pointer == "" {
+		return "document"
+	}
+	return pointer
+}
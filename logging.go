@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// loggingFlags registers the flags that configure logging on fs and returns
+// a function installing the selected logger as the slog default once fs has
+// been parsed.
+func loggingFlags(fs *flag.FlagSet) func() error {
+	levelFlag := fs.String("log-level", "info", "Lowest level of log messages written to stderr: debug, info, warn or error")
+	formatFlag := fs.String("log-format", "text", "Format of log messages: text or json")
+	errorFormatFlag := fs.String("error-format", "text", "Format of the error reported when the command fails: text, a log message, or json, a single JSON object on stderr with code, message, path, file and line")
+	quietFlag := fs.Bool("quiet", false, "Write nothing to stderr but the error failing the command: no progress, warnings or other log messages; overrides --log-level")
+	verbose := 0
+	fs.Var(&verbosityFlag{level: &verbose, step: 1}, "v", "Log per-file diagnostics, such as the format, compression and timing of every input and output; overrides --log-level")
+	fs.Var(&verbosityFlag{level: &verbose, step: 1}, "verbose", "Same as -v")
+	fs.Var(&verbosityFlag{level: &verbose, step: 2}, "vv", "Also log per-key diagnostics: the rule applied to every value and the keys dropped, such as those left empty once their whitespace is trimmed")
+
+	return func() error {
+		switch *errorFormatFlag {
+		case "text", "json":
+			errorFormat = *errorFormatFlag
+		default:
+			return fmt.Errorf("unknown error format %q, want text or json", *errorFormatFlag)
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(*levelFlag)); err != nil {
+			return fmt.Errorf("unknown log level %q, want debug, info, warn or error", *levelFlag)
+		}
+		switch {
+		case *quietFlag && verbose > 0:
+			return errors.New("--quiet cannot be combined with -v or -vv")
+		case *quietFlag:
+			level = slog.LevelError
+		case verbose == 1:
+			level = slog.LevelDebug
+		case verbose > 1:
+			level = levelTrace
+		}
+		opts := &slog.HandlerOptions{Level: level, ReplaceAttr: nameTraceLevel}
+
+		var handler slog.Handler
+		switch *formatFlag {
+		case "text":
+			handler = slog.NewTextHandler(os.Stderr, opts)
+		case "json":
+			handler = slog.NewJSONHandler(os.Stderr, opts)
+		default:
+			return fmt.Errorf("unknown log format %q, want text or json", *formatFlag)
+		}
+		slog.SetDefault(slog.New(handler))
+		return nil
+	}
+}
+
+// levelTrace is the log level of the per-key diagnostics logged with -vv,
+// below debug.
+const levelTrace = slog.LevelDebug - 4
+
+// nameTraceLevel is a slog.HandlerOptions.ReplaceAttr writing levelTrace
+// as TRACE rather than DEBUG-4.
+func nameTraceLevel(_ []string, a slog.Attr) slog.Attr {
+	if level, ok := a.Value.Any().(slog.Level); ok && a.Key == slog.LevelKey && level == levelTrace {
+		a.Value = slog.StringValue("TRACE")
+	}
+	return a
+}
+
+// verbosityFlag is a boolean flag.Value raising the verbosity in level by
+// step each time it is given, so -v -v is the same as -vv.
+type verbosityFlag struct {
+	level *int
+	step  int
+}
+
+// String returns the verbosity reached.
+func (v *verbosityFlag) String() string {
+	if v.level == nil {
+		return "0"
+	}
+	return strconv.Itoa(*v.level)
+}
+
+// Set raises the verbosity when value is true.
+func (v *verbosityFlag) Set(value string) error {
+	on, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if on {
+		*v.level += v.step
+	}
+	return nil
+}
+
+// IsBoolFlag lets the flag be given without a value.
+func (v *verbosityFlag) IsBoolFlag() bool {
+	return true
+}
+
+// errorFormat is the format of the error main reports, set by --error-format.
+var errorFormat = "text"
+
+// logError logs err at error level with msg, adding the input file and the
+// record index of errors from record streams as fields.
+func logError(msg string, err error, attrs ...interface{}) {
+	var fileErr *fileError
+	if errors.As(err, &fileErr) {
+		attrs = append(attrs, "file", fileErr.file)
+	}
+	var recordErr *transform.RecordError
+	if errors.As(err, &recordErr) {
+		attrs = append(attrs, "record", recordErr.Index)
+	}
+	slog.Error(msg, append(attrs, "err", err)...)
+}
+
+// reportError reports the failure of the command on stderr in the format
+// selected by --error-format.
+func reportError(err error) {
+	if errorFormat == "json" {
+		json.NewEncoder(os.Stderr).Encode(describeError(err))
+		return
+	}
+	logError("command failed", err, "exit_code", exitCode(err))
+}
+
+// logOmitted is middleware logging, at debug level, the key path and type
+// of every value the transformation omits.
+func logOmitted(next transform.TransformFunc) transform.TransformFunc {
+	return func(a transform.Attribute) (interface{}, bool) {
+		result, ok := next(a)
+		if !ok {
+			slog.Debug("value omitted", "path", transform.JSONPointer(a.Path), "type", a.Type)
+		}
+		return result, ok
+	}
+}
+
+// logWarnings logs each warning with the JSON Pointer path, reason and
+// offending value.
+func logWarnings(warnings []transform.Warning) {
+	for _, w := range warnings {
+		slog.Warn("invalid value", "path", w.Path, "reason", w.Reason, "value", w.Value)
+	}
+}
+
+// logOutputWarning logs a warning about the transformed output of a record,
+// such as a missing required field.
+func logOutputWarning(record int, w transform.Warning) {
+	slog.Warn(w.Reason, "record", record, "path", w.Path)
+}
+
+// debugEnabled reports whether the default logger writes debug messages.
+func debugEnabled() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// traceEnabled reports whether the default logger writes the per-key
+// diagnostics of -vv.
+func traceEnabled() bool {
+	return slog.Default().Enabled(context.Background(), levelTrace)
+}
+
+// logRules is middleware logging, with -vv, the rule applied to every value
+// and whether it produced a valid result.
+func logRules(next transform.TransformFunc) transform.TransformFunc {
+	return func(a transform.Attribute) (interface{}, bool) {
+		result, ok := next(a)
+		slog.Log(context.Background(), levelTrace, "rule applied", "path", transform.JSONPointer(a.Path), "type", a.Type, "valid", ok)
+		return result, ok
+	}
+}
+
+// logDroppedKey logs, with -vv, a key the transformation drops.
+func logDroppedKey(path []string, key, reason string) {
+	slog.Log(context.Background(), levelTrace, "key dropped", "path", transform.JSONPointer(append(path[:len(path):len(path)], key)), "key", key, "reason", reason)
+}
@@ -1,167 +1,773 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"strconv"
-	"strings"
+	"os/signal"
+	"runtime"
+	"syscall"
 	"time"
-)
 
-// TransformationRule represents a function that transforms a value based on a schema key type.
-type TransformationRule func(interface{}) interface{}
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 
-// TransformRules is a map that associates each schema key type with its corresponding transformation function.
-var (
-	TransformRules = map[string]TransformationRule{
-		"S":    FormatString,
-		"N":    FormatNum,
-		"BOOL": FormatBool,
-		"NULL": FormatNull,
-		"M":    FormatMap,
-		"L":    FormatList,
-	}
+	"github.com/Ravali181221/Ravali_Challenge/pkg/celrules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/httpio"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/jsonpatch"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/s3io"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
 )
 
 func main() {
-	// Parse command-line flags
-	schemaFlag := flag.String("config", "schema.json", "Used to read the json file")
-	flag.Parse()
-
-	// Read and parse the schema file
-	inputMap, err := ParseSchema(*schemaFlag)
-	if err != nil {
-		fmt.Println("error :", err)
+	err := run(os.Args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
 		return
 	}
-
-	// Transform the JSON according to the schema rules
-	output := TransformJSON(inputMap)
-
-	// Marshal the transformed JSON and print it
-	out, err := json.Marshal(output)
-	if err != nil {
-		fmt.Println("error :", err)
-		return
+	// Flag errors have already been printed by the flag package
+	if !errors.Is(err, errFlagsReported) {
+		reportError(err)
 	}
-	fmt.Println(string(out))
+	os.Exit(exitCode(err))
 }
 
-// TransformJSON recursively applies transformation rules to the input JSON.
-func TransformJSON(inputMap map[string]interface{}) map[string]interface{} {
-	output := make(map[string]interface{})
+// transformCommand registers the flags of the transform command on fs and
+// returns the function running it. The command converts DynamoDB typed JSON
+// into plain JSON, or the reverse with --reverse, in any of the input and
+// output modes selected by its flags.
+func transformCommand(fs *flag.FlagSet) func(args []string) error {
+	// Register command-line flags
+	configFlag := &fileList{files: []string{"schema.json"}}
+	fs.Var(configFlag, "config", "Used to read the json file, either a local path, an s3://bucket/key URL or an http(s):// URL; repeat it, or name the files as arguments, to merge the transformed documents")
+	onConflictFlag := fs.String("on-conflict", "error", "How several merged documents combine: error fails on a key holding different values, first-wins keeps the first value and deep-merge merges nested objects, the last file winning for other values")
+	ndjsonFlag := fs.Bool("ndjson", false, "Read and write newline-delimited JSON records")
+	reverseFlag := fs.Bool("reverse", false, "Convert plain JSON into DynamoDB typed JSON")
+	sourceFlag := fs.String("source", "", "Read records from a source such as kinesis://stream-name or dynamodb://table instead of a file")
+	sinkFlag := fs.String("sink", "", "Write newline-delimited records to a sink such as kafka://broker:9092/topic or dynamodb://table instead of --output")
+	batchSizeFlag := fs.Int("batch-size", 1, "With --source or --sink, the largest number of records written to the sink at once")
+	batchTimeoutFlag := fs.Duration("batch-timeout", time.Second, "With --source or --sink, how long a partial batch waits for more records")
+	streamsFlag := fs.Bool("streams", false, "Read a DynamoDB Streams event and write one transformed record per line")
+	newTransformer := transformerFlags(fs)
+	httpFlags(fs)
+	indentFlag := fs.String("indent", "", "Indent output with this string, e.g. two spaces or a tab")
+	prettyFlag := fs.Bool("pretty", false, "Pretty-print output indented with two spaces")
+	compactFlag := fs.Bool("compact", true, "Print compact output; an explicit --compact overrides --indent and --pretty")
+	colorFlag := fs.String("color", "auto", "Colorize keys, strings, numbers, booleans and nulls of JSON output: auto does so for pretty-printed output to a terminal unless NO_COLOR is set, always and never regardless")
+	outputFlag := fs.String("output", "", "Write output to this file or s3://bucket/key URL instead of stdout; the file is replaced atomically")
+	var splitSizeFlag byteSize
+	fs.Var(&splitSizeFlag, "split-size", "Split --ndjson, --streams or array output into numbered --output chunks, e.g. out-00001.ndjson, of at most this size before compression, such as 100MB; 0 means no limit")
+	splitRecordsFlag := fs.Int("split-records", 0, "Split --ndjson, --streams or array output into numbered --output chunks of at most this many records; 0 means no limit")
+	newEncoder := outputFormatFlags(fs)
+	sortKeysFlag := fs.Bool("sort-keys", false, "Emit object keys in lexicographic order at every nesting level")
+	preserveOrderFlag := fs.Bool("preserve-order", false, "Emit object keys in the order of the input keys they came from at every nesting level, instead of the order of the JSON codec")
+	emitPatchFlag := fs.Bool("emit-patch", false, "Write a JSON Patch (RFC 6902) turning the input, with its type tags naively dropped, into the transformed document instead of the document itself; with --reverse, turning the plain input into the typed output")
+	canonicalFlag := fs.Bool("canonical", false, "Write JSON in the JSON Canonicalization Scheme (RFC 8785), so outputs can be hashed, signed and compared byte for byte")
+	compressFlag := fs.String("compress", "", "Compress output with gzip or zstd; defaults to the --output extension (.gz or .zst)")
+	inputDirFlag := fs.String("input-dir", "", "Transform every JSON file in this directory, or matching this glob pattern, into the --output directory")
+	concurrencyFlag := fs.Int("concurrency", runtime.NumCPU(), "With --input-dir, the number of files transformed at once")
+	checkpointFlag := fs.String("checkpoint", "", "Record progress in this file so an interrupted --input-dir, --ndjson, --source or --sink run started again with the same flags resumes where it stopped; it is removed once the run completes")
+	streamFlag := fs.Bool("stream", false, "Decode and transform one top-level attribute at a time, bounding memory for very large documents")
+	watchFlag := fs.Bool("watch", false, "Re-run the transformation whenever the --config file or --input-dir files change")
+	statsFlag := fs.Bool("stats", false, "Log a summary of the transformation once it finishes: counts per type tag, dropped keys, invalid numbers, date conversions, records and wall time")
+	statsFileFlag := fs.String("stats-file", "", "Write the --stats summary as JSON to this file or s3://bucket/key URL")
+	progressFlag := fs.Bool("progress", false, "Report records transformed, bytes read, rate and estimated time left on stderr while the transformation runs; on by default when stderr is a terminal, off with --quiet")
+	profileFlag := fs.Bool("profile", false, "Print the time spent and number of calls per type tag and key path once the transformation finishes")
+	warningsFlag := fs.Bool("warnings", false, "Log a warning for every value the transformation drops or replaces with a default, such as unknown type tags, empty keys or invalid numbers")
+	timeoutFlag := fs.Duration("timeout", 0, "Stop reading and transforming with an error once this much time has passed, e.g. 30s or 5m; 0 means no limit. SIGINT and SIGTERM stop it the same way, and a second one kills it")
+	filterFlag := fs.String("filter", "", "With --ndjson, --streams, --source or --sink, only emit records matching this CEL expression over the transformed record, e.g. 'status == \"ACTIVE\" && amount > 100'")
+	parseOpts := parseOptionFlags(fs)
+
+	return func(args []string) (err error) {
+		// Input files may be named by --config or as arguments, "-" meaning
+		// stdin, and several are merged
+		inputs := configFlag.files
+		if fs.NArg() > 0 {
+			if !configFlag.given {
+				inputs = nil
+			}
+			inputs = append(inputs[:len(inputs):len(inputs)], fs.Args()...)
+		}
+		schemaFile := inputs[0]
+
+		outputFormat, err := compress.ParseFormat(*compressFlag)
+		if err != nil {
+			return usageError(err)
+		}
+		if !isFlagSet(fs, "compress") {
+			outputFormat = compress.FormatOf(*outputFlag)
+		}
+		colorize, err := useColor(*colorFlag, *outputFlag == "" && isTerminal(os.Stdout), outputIndent(fs, *indentFlag, *prettyFlag, *compactFlag) != "")
+		if err != nil {
+			return usageError(err)
+		}
+
+		// Count and time what the transformation does and report it once it
+		// finishes; under --watch every re-run reports its own
+		var extra []transform.Option
+		var stats *transform.Stats
+		if (*statsFlag || *statsFileFlag != "") && !*watchFlag {
+			stats = transform.NewStats()
+			extra = append(extra, transform.WithStats(stats))
+			start := time.Now()
+			defer func() {
+				if statsErr := reportStats(stats.Report(), time.Since(start), *statsFlag, *statsFileFlag); statsErr != nil && err == nil {
+					err = transformError(statsErr)
+				}
+			}()
+		}
+
+		// Report progress on a terminal, or wherever it was asked for, until
+		// the transformation finishes
+		var report *progress
+		showProgress := isTerminal(os.Stderr)
+		if isFlagSet(fs, "progress") {
+			showProgress = *progressFlag
+		}
+		if showProgress && !flagIsTrue(fs, "quiet") && !*watchFlag {
+			if stats == nil {
+				stats = transform.NewStats()
+				extra = append(extra, transform.WithStats(stats))
+			}
+			report = newProgress(os.Stderr, isTerminal(os.Stderr), stats)
+		}
+
+		if *profileFlag && !*watchFlag {
+			profile := transform.NewProfile()
+			extra = append(extra, transform.WithMiddleware(profile.Middleware()))
+			defer func() {
+				if profileErr := writeProfile(os.Stderr, profile); profileErr != nil && err == nil {
+					err = transformError(profileErr)
+				}
+			}()
+		}
+
+		// Filter the records of record streams on their transformed values
+		if *filterFlag != "" {
+			if !*ndjsonFlag && !*streamsFlag && *sourceFlag == "" && *sinkFlag == "" {
+				return usageError(errors.New("--filter selects records and needs --ndjson, --streams, --source or --sink"))
+			}
+			filter, err := celrules.CompileFilter(*filterFlag)
+			if err != nil {
+				return usageError(err)
+			}
+			extra = append(extra, transform.WithRecordFilter(filter))
+		}
+
+		// Stop reading, transforming and writing on SIGINT or SIGTERM, or
+		// once --timeout has passed
+		if *timeoutFlag < 0 {
+			return usageError(errors.New("--timeout cannot be negative"))
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		// Restore the default handling once a signal arrives, so a second one
+		// kills a run that is slow to stop
+		context.AfterFunc(ctx, stop)
+		if *timeoutFlag > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeoutFlag)
+			defer cancel()
+		}
+
+		t, err := newTransformer(ctx, extra...)
+		if err != nil {
+			return usageError(err)
+		}
+
+		// Other output formats encode whole documents or NDJSON records
+		encode, err := newEncoder()
+		if err != nil {
+			return usageError(err)
+		}
+		if encode != nil && (*reverseFlag || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "" || *inputDirFlag != "" || archiveKindOf(schemaFile) != notArchive) {
+			return usageError(fmt.Errorf("--format %s writes transformed documents or --ndjson records and cannot be combined with --reverse, --stream, --streams, --source, --sink, --input-dir or archive input", fs.Lookup("format").Value))
+		}
+
+		// Warnings are collected per document, so record streams cannot report them
+		if *warningsFlag && (*reverseFlag || *ndjsonFlag || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "") {
+			return usageError(errors.New("--warnings needs whole typed documents and cannot be combined with --reverse, --ndjson, --stream, --streams, --source or --sink"))
+		}
+		if flagIsTrue(fs, "extended-json") && (*reverseFlag || encode != nil || flagIsTrue(fs, "flatten") || flagIsTrue(fs, "unflatten")) {
+			return usageError(errors.New("--extended-json writes transformed JSON and cannot be combined with --reverse, --flatten, --unflatten or another --format"))
+		}
+		if *emitPatchFlag && (encode != nil || *canonicalFlag || *ndjsonFlag || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "") {
+			return usageError(errors.New("--emit-patch needs whole documents written as JSON and cannot be combined with another --format, --canonical, --ndjson, --stream, --streams, --source or --sink"))
+		}
+
+		// Canonical JSON re-encodes whole documents or NDJSON records
+		if *canonicalFlag {
+			if encode != nil || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "" || *inputDirFlag != "" || archiveKindOf(schemaFile) != notArchive {
+				return usageError(errors.New("--canonical writes JSON documents or --ndjson records and cannot be combined with another --format, --stream, --streams, --source, --sink, --input-dir or archive input"))
+			}
+			if *indentFlag != "" || *prettyFlag {
+				return usageError(errors.New("--canonical writes no whitespace and cannot be combined with --indent or --pretty"))
+			}
+			encode = canonicalEncoder
+		}
+		if *preserveOrderFlag && (*sortKeysFlag || *canonicalFlag || *emitPatchFlag || encode != nil || *ndjsonFlag || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "") {
+			return usageError(errors.New("--preserve-order needs whole documents written as JSON and cannot be combined with --sort-keys, --canonical, --emit-patch, another --format, --ndjson, --stream, --streams, --source or --sink"))
+		}
+		if *streamFlag && (flagIsTrue(fs, "flatten") || flagIsTrue(fs, "unflatten")) {
+			return usageError(errors.New("--stream writes attributes as they are transformed and cannot be combined with --flatten or --unflatten"))
+		}
+		if len(parseOpts()) > 0 && (*ndjsonFlag || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "") {
+			return usageError(errors.New("--reject-duplicate-keys, --allow-comments and the --max-* input limits need whole documents and cannot be combined with --ndjson, --stream, --streams, --source or --sink"))
+		}
+
+		// Read from stdin when input is piped in and no --config flag or
+		// file argument is given, otherwise from the schema file
+		useStdin := !isFlagSet(fs, "config") && fs.NArg() == 0 && stdinIsPiped() || len(inputs) == 1 && schemaFile == "-"
+
+		// Merge several documents into one
+		onConflict, err := transform.ParseMergeStrategy(*onConflictFlag)
+		if err != nil {
+			return usageError(err)
+		}
+		if len(inputs) > 1 && (*reverseFlag || *ndjsonFlag || *streamFlag || *streamsFlag || *sourceFlag != "" || *sinkFlag != "" || *inputDirFlag != "" || *watchFlag || *emitPatchFlag || *preserveOrderFlag) {
+			return usageError(errors.New("merging several inputs needs whole typed documents and cannot be combined with --reverse, --ndjson, --stream, --streams, --source, --sink, --input-dir, --watch, --emit-patch or --preserve-order"))
+		}
+
+		// Split record output into numbered chunks of the output
+		split := splitOptions{size: int64(splitSizeFlag), records: *splitRecordsFlag}
+		if split.records < 0 {
+			return usageError(errors.New("--split-records cannot be negative"))
+		}
+		if split.enabled() {
+			if *outputFlag == "" {
+				return usageError(errors.New("--split-size and --split-records need an --output path to number the chunks after"))
+			}
+			if encode != nil || *reverseFlag || *streamFlag || *sourceFlag != "" || *sinkFlag != "" || *inputDirFlag != "" || archiveKindOf(schemaFile) != notArchive || *emitPatchFlag || outputIndent(fs, *indentFlag, *prettyFlag, *compactFlag) != "" {
+				return usageError(errors.New("--split-size and --split-records write one JSON record per line and cannot be combined with another --format, --reverse, --stream, --source, --sink, --input-dir, archive input, --emit-patch, --indent or --pretty"))
+			}
+		}
+
+		// Resume interrupted batches and record streams from a checkpoint
+		if *checkpointFlag != "" {
+			if *inputDirFlag == "" && !*ndjsonFlag && *sourceFlag == "" && *sinkFlag == "" {
+				return usageError(errors.New("--checkpoint records the progress of --input-dir, --ndjson, --source or --sink runs"))
+			}
+			if *watchFlag || *ndjsonFlag && (encode != nil || split.enabled()) {
+				return usageError(errors.New("--checkpoint cannot be combined with --watch, or with --ndjson written in another --format or split into chunks"))
+			}
+		}
+
+		// Name the input file in failures to read or transform it
+		if !useStdin && len(inputs) == 1 && *inputDirFlag == "" && *sourceFlag == "" {
+			defer func() {
+				if code := exitCode(err); code == exitParse || code == exitTransform {
+					err = &fileError{file: schemaFile, err: err}
+				}
+			}()
+		}
+
+		// Re-run the whole command whenever the input changes
+		if *watchFlag {
+			target := schemaFile
+			if *inputDirFlag != "" {
+				target = *inputDirFlag
+				if info, err := os.Stat(target); err != nil || !info.IsDir() {
+					target = globRoot(target)
+				}
+			}
+			if useStdin || s3io.IsURL(target) || httpio.IsURL(target) || *sourceFlag != "" || *sinkFlag != "" {
+				return usageError(errors.New("--watch needs a local --config file or --input-dir"))
+			}
+			return runWatch(target, *outputFlag, func() error {
+				return run(append([]string{fs.Name()}, withoutWatchFlag(args)...))
+			})
+		}
+
+		if report != nil {
+			ctx = withProgress(ctx, report)
+			if *inputDirFlag == "" {
+				for i, file := range inputs {
+					report.addTotal(inputSize(file, i == 0 && useStdin))
+				}
+			}
+			report.run()
+			defer report.finish()
+		}
+
+		// Stream records from a source or into a sink until the source is
+		// exhausted or interrupted, as well as checkpointed --ndjson records
+		if *sourceFlag != "" || *sinkFlag != "" || *ndjsonFlag && *checkpointFlag != "" {
+			openRecords := func() (io.ReadCloser, error) {
+				return openInput(ctx, schemaFile, useStdin)
+			}
+			return runPipeline(ctx, *sourceFlag, *sinkFlag, *checkpointFlag, *outputFlag, outputFormat, openRecords, t, stream.Options{
+				BatchSize:    *batchSizeFlag,
+				BatchTimeout: *batchTimeoutFlag,
+				Reverse:      *reverseFlag,
+			})
+		}
+
+		// transformFile transforms one file of an archive or batch
+		indent := outputIndent(fs, *indentFlag, *prettyFlag, *compactFlag)
+		docOpts := documentOptions{
+			reverse:   *reverseFlag,
+			warn:      *warningsFlag,
+			parseOpts: parseOpts(),
+			indent:    indent,
+			sortKeys:  *sortKeysFlag,
+			patch:     *emitPatchFlag,
+			keepOrder: *preserveOrderFlag,
+		}
+		transformFile := func(r io.Reader, w io.Writer) error {
+			if *ndjsonFlag {
+				err := recoverTransform(func() error {
+					if *reverseFlag {
+						return t.ReverseNDJSONContext(ctx, r, w)
+					}
+					return t.TransformNDJSONContext(ctx, r, w)
+				})
+				if err != nil {
+					return streamError(err)
+				}
+				return nil
+			}
+			return transformDocument(ctx, r, w, t, docOpts)
+		}
+
+		// Transform every file matched by --input-dir into the --output directory
+		if *inputDirFlag != "" {
+			if *outputFlag == "" {
+				return usageError(errors.New("--input-dir needs an --output directory"))
+			}
+			if *concurrencyFlag < 1 {
+				return usageError(errors.New("--concurrency must be at least 1"))
+			}
+			return runBatch(ctx, *inputDirFlag, *outputFlag, *checkpointFlag, outputFormat, *concurrencyFlag, transformFile)
+		}
+
+		// Transform every entry of a zip or tar bundle into a mirrored archive
+		if kind := archiveKindOf(schemaFile); kind != notArchive && !useStdin {
+			return runArchive(ctx, kind, schemaFile, *outputFlag, outputFormat, transformFile)
+		}
+
+		// Stream records one at a time in NDJSON mode
+		if *ndjsonFlag {
+			in, err := openInput(ctx, schemaFile, useStdin)
+			if err != nil {
+				return parseError(err)
+			}
+			defer in.Close()
 
-	// Iterate over each key-value pair in the input JSON
-	for key, value := range inputMap {
-		key = sanitizeKey(key)
-		if key == "" {
-			continue
+			write := func(w io.Writer) error {
+				return recoverTransform(func() error {
+					if *reverseFlag {
+						return t.ReverseNDJSONContext(ctx, in, w)
+					}
+					return t.TransformNDJSONContext(ctx, in, w)
+				})
+			}
+			if encode != nil {
+				write = encode(write)
+			}
+			if split.enabled() {
+				err = writeSplit(ctx, *outputFlag, outputFormat, split, false, write)
+			} else {
+				err = writeOutput(ctx, *outputFlag, outputFormat, write)
+			}
+			if err != nil {
+				return streamError(err)
+			}
+			return nil
 		}
 
-		outMap := make(map[string]interface{})
+		// Convert a DynamoDB Streams event into one record per line
+		if *streamsFlag {
+			in, err := openInput(ctx, schemaFile, useStdin)
+			if err != nil {
+				return parseError(err)
+			}
+			defer in.Close()
 
-		// Check if the value is a map (object)
-		if val, ok := value.(map[string]interface{}); ok {
-			// Iterate over each key-value pair in the nested map
-			for k, v := range val {
-				k = sanitizeKey(k)
-				// Apply transformation rule if one exists for the key type
-				if rule, ok := TransformRules[k]; ok {
-					outMap[key] = rule(v)
+			var records []transform.StreamRecord
+			err = recoverTransform(func() error {
+				records, err = t.TransformStreamEvent(transform.NewContextReader(ctx, in))
+				return err
+			})
+			if err != nil {
+				return streamError(err)
+			}
+			write := func(w io.Writer) error {
+				enc := codec.Default().NewEncoder(w)
+				for _, record := range records {
+					if err := enc.Encode(record); err != nil {
+						return err
+					}
 				}
+				return nil
+			}
+			if split.enabled() {
+				err = writeSplit(ctx, *outputFlag, outputFormat, split, false, write)
+			} else {
+				err = writeOutput(ctx, *outputFlag, outputFormat, write)
 			}
+			if err != nil {
+				return transformError(err)
+			}
+			return nil
 		}
 
-		// Merge transformed values into the output map
-		if len(outMap) > 0 {
-			for k, v := range outMap {
-				output[k] = v
+		// Transform one top-level attribute at a time to bound memory use
+		if *streamFlag {
+			if indent != "" || *sortKeysFlag {
+				return usageError(errors.New("--stream writes compact output in input order and cannot be combined with --indent, --pretty or --sort-keys"))
+			}
+			in, err := openInput(ctx, schemaFile, useStdin)
+			if err != nil {
+				return parseError(err)
+			}
+			defer in.Close()
+
+			err = writeOutput(ctx, *outputFlag, outputFormat, func(w io.Writer) error {
+				return recoverTransform(func() error {
+					if *reverseFlag {
+						return t.StreamReverseContext(ctx, in, w)
+					}
+					return t.StreamTransformContext(ctx, in, w)
+				})
+			})
+			if err != nil {
+				return streamError(err)
+			}
+			return nil
+		}
+
+		// Read, transform and marshal every input before anything is written
+		var out []byte
+		if len(inputs) > 1 {
+			out, err = mergeInputs(ctx, inputs, t, docOpts, onConflict)
+		} else {
+			out, err = transformInput(ctx, schemaFile, useStdin, t, docOpts)
+		}
+		if err != nil {
+			return err
+		}
+		write := func(w io.Writer) error {
+			text := out
+			if colorize && encode == nil {
+				text = colorizeJSON(out)
+			}
+			_, err := fmt.Fprintln(w, string(text))
+			return err
+		}
+		if split.enabled() {
+			err := writeSplit(ctx, *outputFlag, outputFormat, split, true, func(w io.Writer) error {
+				return writeArrayRecords(w, out)
+			})
+			if err != nil {
+				return transformError(err)
 			}
+			return nil
 		}
+		if encode != nil {
+			write = encode(write)
+		}
+		if err := writeOutput(ctx, *outputFlag, outputFormat, write); err != nil {
+			return transformError(err)
+		}
+		return nil
 	}
+}
 
-	return output
+// outputIndent returns the indent selected by the --indent, --pretty and
+// --compact flags.
+func outputIndent(fs *flag.FlagSet, indent string, pretty, compact bool) string {
+	if indent == "" && pretty {
+		indent = "  "
+	}
+	if isFlagSet(fs, "compact") && compact {
+		indent = ""
+	}
+	return indent
 }
 
-// FormatString transforms string values, converting RFC3339 formatted strings to Unix Epoch.
-func FormatString(v interface{}) interface{} {
-	strVal := v.(string)
-	if t, err := time.Parse(time.RFC3339, strVal); err == nil {
-		return t.Unix()
+// documentOptions are the settings for transforming one whole document.
+type documentOptions struct {
+	reverse bool
+	// warn logs the values the transformation drops or replaces
+	warn bool
+	// parseOpts check the input before it is decoded
+	parseOpts []transform.ParseOption
+	indent    string
+	sortKeys  bool
+	// patch writes the JSON Patch from the input to the output instead
+	patch bool
+	// keepOrder writes keys in input order, as recorded in keyOrder
+	keepOrder bool
+	keyOrder  *transform.KeyOrder
+	// items receives the documents of input whose root is an array
+	items *[]map[string]interface{}
+}
+
+// forDocument returns the options for parsing and transforming one
+// document, which may be an array of documents, recording its key order
+// with keepOrder.
+func (o documentOptions) forDocument() documentOptions {
+	o.items = new([]map[string]interface{})
+	o.parseOpts = append(o.parseOpts[:len(o.parseOpts):len(o.parseOpts)], transform.AcceptArray(o.items))
+	if o.keepOrder {
+		o.keyOrder = new(transform.KeyOrder)
+		o.parseOpts = append(o.parseOpts[:len(o.parseOpts):len(o.parseOpts)], transform.RecordKeyOrder(o.keyOrder))
 	}
-	return strVal
+	return o
 }
 
-// FormatNum transforms numeric values, parsing them into float64.
-func FormatNum(v interface{}) interface{} {
-	numStr := v.(string)
-	num := 0.0
-	if val, err := strconv.ParseFloat(numStr, 64); err == nil {
-		num = val
+// transformMap transforms inputMap according to the schema rules, or back
+// into typed JSON with opts.reverse, and marshals the result. A nil inputMap
+// stands for the documents of a root array in opts.items, which are
+// transformed one by one into an array. It fails without transforming once
+// ctx is done.
+func transformMap(ctx context.Context, inputMap map[string]interface{}, t *transform.Transformer, opts documentOptions) ([]byte, error) {
+	var result interface{}
+	if inputMap == nil && opts.items != nil && *opts.items != nil {
+		results := make([]interface{}, len(*opts.items))
+		for i, item := range *opts.items {
+			r, err := transformResult(ctx, i, item, opts.keyOrder.Item(i), t, opts)
+			if err != nil {
+				return nil, transformError(fmt.Errorf("item %d: %w", i, err))
+			}
+			results[i] = r
+		}
+		result = results
+	} else {
+		r, err := transformResult(ctx, 0, inputMap, opts.keyOrder, t, opts)
+		if err != nil {
+			return nil, transformError(err)
+		}
+		result = r
 	}
-	return num
+
+	out, err := marshalOutput(result, opts.indent, opts.sortKeys)
+	if err != nil {
+		return nil, transformError(err)
+	}
+	return out, nil
 }
 
-// FormatBool transforms boolean values.
-func FormatBool(v interface{}) interface{} {
-	boolStr := v.(string)
-	switch boolStr {
-	case "1", "t", "true":
-		return true
+// transformResult transforms the document inputMap at index of the input
+// like transformMap, returning what is to be marshaled for it: the output
+// ordered by order if set, or the JSON Patch to it with opts.patch.
+func transformResult(ctx context.Context, index int, inputMap map[string]interface{}, order *transform.KeyOrder, t *transform.Transformer, opts documentOptions) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var output map[string]interface{}
+	err := recoverTransform(func() error {
+		if opts.reverse {
+			output = t.Reverse(inputMap)
+		} else if err := t.Check(inputMap); err != nil {
+			return err
+		} else {
+			if opts.warn {
+				logWarnings(t.Validate(inputMap))
+			}
+			output = t.TransformContext(ctx, inputMap)
+			// Plugin calls aborted once ctx is done drop their values
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return t.CheckOutput(index, output)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.patch {
+		from := inputMap
+		if !opts.reverse {
+			from = t.Untyped(inputMap)
+		}
+		return jsonpatch.Diff(from, output), nil
+	}
+	if order != nil {
+		return t.OrderKeys(output, order), nil
+	}
+	return output, nil
+}
+
+// transformInput reads and parses the JSON document, or array of documents,
+// in the named file or stdin with useStdin, transforms it with transformMap
+// and returns the marshaled output.
+func transformInput(ctx context.Context, file string, useStdin bool, t *transform.Transformer, opts documentOptions) ([]byte, error) {
+	opts = opts.forDocument()
+	inputMap, err := readDocument(ctx, file, useStdin, opts.parseOpts...)
+	if err != nil {
+		return nil, parseError(err)
+	}
+	return transformMap(ctx, inputMap, t, opts)
+}
+
+// transformDocument reads a single JSON document from r, transforms it with
+// transformMap and writes it to w, failing once ctx is done.
+func transformDocument(ctx context.Context, r io.Reader, w io.Writer, t *transform.Transformer, opts documentOptions) error {
+	opts = opts.forDocument()
+	inputMap, err := transform.ParseReader(transform.NewContextReader(ctx, r), opts.parseOpts...)
+	if err != nil {
+		return parseError(err)
+	}
+	out, err := transformMap(ctx, inputMap, t, opts)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// openInput returns stdin when useStdin is set, otherwise the named file, S3
+// object or document fetched over HTTP(S), which is requested within ctx.
+// Gzip and zstd input is decompressed on the fly. The bytes read count
+// towards the progress carried by ctx.
+func openInput(ctx context.Context, fileName string, useStdin bool) (io.ReadCloser, error) {
+	var in io.ReadCloser
+	switch {
+	case useStdin:
+		fileName = ""
+		in = io.NopCloser(os.Stdin)
+	case s3io.IsURL(fileName):
+		client, err := newS3Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if in, err = s3io.Open(ctx, client, fileName); err != nil {
+			return nil, err
+		}
+	case httpio.IsURL(fileName):
+		body, err := httpio.Open(ctx, fileName, httpOptions)
+		if err != nil {
+			return nil, err
+		}
+		in = body
 	default:
-		return false
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		in = f
+	}
+
+	in = countReads(ctx, in)
+	r, err := compress.NewReader(fileName, in)
+	if err != nil {
+		in.Close()
+		return nil, err
 	}
+	slog.Debug("input opened", "file", inputName(fileName, useStdin), "size", inputSize(fileName, useStdin))
+	return &decompressedInput{ReadCloser: r, in: in}, nil
 }
 
-// FormatNull transforms null values.
-func FormatNull(v interface{}) interface{} {
-	return nil // Always returns nil for NULL type
+// decompressedInput is a decompressing reader that closes its input along
+// with the reader.
+type decompressedInput struct {
+	io.ReadCloser
+	in io.Closer
 }
 
-// FormatMap recursively transforms nested maps (objects).
-func FormatMap(v interface{}) interface{} {
-	submap := v.(map[string]interface{})
-	return TransformJSON(submap)
+// Close closes the decompressing reader and the input.
+func (d *decompressedInput) Close() error {
+	err := d.ReadCloser.Close()
+	if closeErr := d.in.Close(); err == nil {
+		err = closeErr
+	}
+	return err
 }
 
-// FormatList transforms list values (arrays).
-func FormatList(v interface{}) interface{} {
-	listValue := v.([]interface{})
-	outList := make([]interface{}, 0)
-	for _, listItem := range listValue {
-		if val, ok := listItem.(map[string]interface{}); ok {
-			outList = append(outList, TransformJSON(val))
+// readDocument reads and parses the JSON document in the named local file or
+// S3 object, or in stdin with useStdin, checking it with opts and failing
+// once ctx is done. Files and objects named .yaml or .yml are read as YAML,
+// those named .cbor as CBOR and those named .jsonc or .json5 may hold
+// comments.
+func readDocument(ctx context.Context, fileName string, useStdin bool, opts ...transform.ParseOption) (_ map[string]interface{}, err error) {
+	start := time.Now()
+	defer func() {
+		if err == nil {
+			slog.Debug("document parsed", "file", inputName(fileName, useStdin), "format", documentFormat(fileName, useStdin), "elapsed", time.Since(start))
+		}
+	}()
+
+	if useStdin || s3io.IsURL(fileName) || httpio.IsURL(fileName) {
+		rc, err := openInput(ctx, fileName, useStdin)
+		if err != nil {
+			return nil, err
 		}
+		defer rc.Close()
+		in := transform.NewContextReader(ctx, rc)
+		switch {
+		case useStdin:
+		case transform.IsYAMLFile(fileName):
+			return transform.ParseYAMLReader(in, opts...)
+		case transform.IsCBORFile(fileName):
+			return transform.ParseCBORReader(in, opts...)
+		case transform.IsJSONCFile(fileName):
+			opts = append(opts[:len(opts):len(opts)], transform.AllowComments())
+		}
+		return transform.ParseReader(in, opts...)
 	}
-	return outList
+	return transform.ParseSchemaContext(ctx, fileName, opts...)
 }
 
-// sanitizeKey trims leading and trailing whitespace from a key.
-func sanitizeKey(key string) string {
-	return strings.TrimSpace(key)
+// documentFormat names the format readDocument reads the named input as.
+func documentFormat(fileName string, useStdin bool) string {
+	switch {
+	case useStdin:
+		return "json"
+	case transform.IsYAMLFile(fileName):
+		return "yaml"
+	case transform.IsCBORFile(fileName):
+		return "cbor"
+	case transform.IsJSONCFile(fileName):
+		return "jsonc"
+	default:
+		return "json"
+	}
 }
 
-// ParseSchema reads and parses the JSON schema file.
-func ParseSchema(fileName string) (map[string]interface{}, error) {
-	// Check if the file is a JSON file
-	if !strings.Contains(fileName, ".json") {
-		return nil, errors.New("config file is not a JSON file")
+// inputName names the input read from fileName, or stdin with useStdin, in
+// diagnostics.
+func inputName(fileName string, useStdin bool) string {
+	if useStdin {
+		return "stdin"
 	}
+	return fileName
+}
 
-	// Read the contents of the file
-	fileBytes, err := os.ReadFile(fileName)
+// parseInput reads and parses the JSON document from stdin, an S3 object or
+// an HTTP(S) URL.
+func parseInput(fileName string, useStdin bool) (map[string]interface{}, error) {
+	in, err := openInput(context.Background(), fileName, useStdin)
 	if err != nil {
 		return nil, err
 	}
+	defer in.Close()
+	return transform.ParseReader(in)
+}
 
-	// Unmarshal the JSON content into a map
-	var output map[string]interface{}
-	if err := json.Unmarshal(fileBytes, &output); err != nil {
+// newS3Client returns an S3 client using the standard AWS configuration,
+// loaded within ctx.
+func newS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
 		return nil, err
 	}
+	return s3.NewFromConfig(cfg), nil
+}
 
-	return output, nil
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal.
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
 }
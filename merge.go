@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// mergeInputs reads and transforms the typed documents in the named files,
+// "-" meaning stdin, and merges the outputs in file order with strategy,
+// returning the marshaled result. Files holding arrays of documents are
+// concatenated into one array instead, and cannot be mixed with files
+// holding single documents. Failures name the file they occurred in.
+func mergeInputs(ctx context.Context, files []string, t *transform.Transformer, opts documentOptions, strategy transform.MergeStrategy) ([]byte, error) {
+	merged := make(map[string]interface{})
+	var items []interface{}
+	documents := 0
+	for _, file := range files {
+		fileOpts := opts.forDocument()
+		inputMap, err := readDocument(ctx, file, file == "-", fileOpts.parseOpts...)
+		if err != nil {
+			return nil, &fileError{file: file, err: parseError(err)}
+		}
+
+		if inputMap == nil && *fileOpts.items != nil {
+			for i, item := range *fileOpts.items {
+				output, err := transformResult(ctx, i, item, nil, t, fileOpts)
+				if err != nil {
+					return nil, &fileError{file: file, err: transformError(fmt.Errorf("item %d: %w", i, err))}
+				}
+				items = append(items, output)
+			}
+			continue
+		}
+
+		documents++
+		output, err := transformResult(ctx, 0, inputMap, nil, t, fileOpts)
+		if err != nil {
+			return nil, &fileError{file: file, err: transformError(err)}
+		}
+		if err := transform.Merge(merged, output.(map[string]interface{}), strategy); err != nil {
+			return nil, &fileError{file: file, err: transformError(err)}
+		}
+	}
+
+	var result interface{} = merged
+	if items != nil {
+		if documents > 0 {
+			return nil, parseError(errors.New("cannot merge arrays of documents with single documents"))
+		}
+		result = items
+	}
+	out, err := marshalOutput(result, opts.indent, opts.sortKeys)
+	if err != nil {
+		return nil, transformError(err)
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// serverMetrics are the Prometheus metrics the server exports on /metrics.
+type serverMetrics struct {
+	registry  *prometheus.Registry
+	documents *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+// newServerMetrics registers the server's metrics, including the value
+// counts recorded in stats, along with the Go runtime and process metrics.
+func newServerMetrics(stats *transform.Stats) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		documents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "transformer_documents_total",
+			Help: "Documents transformed, by direction: transform or reverse.",
+		}, []string{"direction"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "transformer_errors_total",
+			Help: "Failed requests, by reason: method, parse, transform or canceled, for requests timed out or abandoned by the client.",
+		}, []string{"reason"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "transformer_request_duration_seconds",
+			Help:    "Latency of HTTP requests, by handler, method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"handler", "method", "code"}),
+	}
+	m.registry.MustRegister(
+		m.documents,
+		m.errors,
+		m.duration,
+		statsCollector{stats: stats},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Descriptions of the metrics read from transform.Stats.
+var (
+	valuesDesc = prometheus.NewDesc("transformer_values_total",
+		"Typed values converted, by registered type tag, or unknown for any other tag.", []string{"type"}, nil)
+	droppedKeysDesc = prometheus.NewDesc("transformer_dropped_keys_total",
+		"Attributes dropped because their key was empty.", nil, nil)
+	invalidNumbersDesc = prometheus.NewDesc("transformer_invalid_numbers_total",
+		"N values that could not be parsed.", nil, nil)
+	dateConversionsDesc = prometheus.NewDesc("transformer_date_conversions_total",
+		"S values converted into timestamps.", nil, nil)
+)
+
+// statsCollector exports the counts in a transform.Stats, which already
+// counts every value the transformer converts, as Prometheus counters.
+type statsCollector struct {
+	stats *transform.Stats
+}
+
+// Describe sends the descriptions of the exported metrics.
+func (c statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- valuesDesc
+	ch <- droppedKeysDesc
+	ch <- invalidNumbersDesc
+	ch <- dateConversionsDesc
+}
+
+// Collect sends the current counts.
+func (c statsCollector) Collect(ch chan<- prometheus.Metric) {
+	report := c.stats.Report()
+	for typeKey, n := range report.Types {
+		ch <- prometheus.MustNewConstMetric(valuesDesc, prometheus.CounterValue, float64(n), typeKey)
+	}
+	ch <- prometheus.MustNewConstMetric(droppedKeysDesc, prometheus.CounterValue, float64(report.DroppedKeys))
+	ch <- prometheus.MustNewConstMetric(invalidNumbersDesc, prometheus.CounterValue, float64(report.InvalidNumbers))
+	ch <- prometheus.MustNewConstMetric(dateConversionsDesc, prometheus.CounterValue, float64(report.DateConversions))
+}
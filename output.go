@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/s3io"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// marshalOutput encodes output as compact JSON with the selected codec, or
+// indented when indent is non-empty, with sorted keys when sortKeys is set.
+func marshalOutput(output interface{}, indent string, sortKeys bool) ([]byte, error) {
+	var out []byte
+	var err error
+	if sortKeys {
+		out, err = transform.MarshalSorted(output)
+	} else {
+		out, err = codec.Default().Marshal(output)
+	}
+	if err != nil || indent == "" {
+		return out, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, out, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeOutput runs write against stdout, or against the file or S3 object at
+// path, which is replaced atomically once write succeeds. The output is
+// compressed with format. S3 uploads are made within ctx.
+func writeOutput(ctx context.Context, path string, format compress.Format, write func(io.Writer) error) (err error) {
+	start := time.Now()
+	defer func() {
+		if err == nil && path != "" {
+			slog.Debug("output written", "file", path, "compression", format, "elapsed", time.Since(start))
+		}
+	}()
+
+	write = compressed(format, write)
+	switch {
+	case path == "":
+		return write(os.Stdout)
+	case s3io.IsURL(path):
+		return writeS3(ctx, path, write)
+	default:
+		return writeFileAtomic(path, write)
+	}
+}
+
+// compressed wraps write so that everything it writes is compressed with
+// format before reaching the underlying writer.
+func compressed(format compress.Format, write func(io.Writer) error) func(io.Writer) error {
+	if format == compress.None {
+		return write
+	}
+	return func(w io.Writer) error {
+		cw, err := compress.NewWriter(w, format)
+		if err != nil {
+			return err
+		}
+		if err := write(cw); err != nil {
+			cw.Close()
+			return err
+		}
+		return cw.Close()
+	}
+}
+
+// writeS3 uploads everything written by write to the S3 object at rawURL.
+// Large outputs are sent as a multipart upload, which is aborted on failure
+// so no partial object is ever created.
+func writeS3(ctx context.Context, rawURL string, write func(io.Writer) error) error {
+	w, err := newS3Writer(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		w.Abort()
+		return err
+	}
+	return w.Close()
+}
+
+// newS3Writer returns a writer uploading to the S3 object at rawURL within
+// ctx.
+func newS3Writer(ctx context.Context, rawURL string) (*s3io.Writer, error) {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3io.NewWriter(ctx, client, rawURL)
+}
+
+// writeFileAtomic writes to a temporary file next to path and renames it into
+// place, so readers never observe a partially written file.
+func writeFileAtomic(path string, write func(io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Clean up the temporary file unless it was renamed into place
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(0o644); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,422 @@
+// Package avrofile writes transformed documents as records of an Avro Object
+// Container File, with a schema supplied by the user or inferred from the
+// JSON Schema schemagen builds for the documents, for ingestion into Kafka
+// and Hadoop tools.
+package avrofile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// ErrMismatch is wrapped by the errors of values that do not match the Avro
+// schema they are written with.
+var ErrMismatch = errors.New("value does not match the Avro schema")
+
+// blockSize is the number of records buffered into each block of the file.
+const blockSize = 1000
+
+// Writer writes documents to an Avro Object Container File.
+type Writer struct {
+	ocf     *goavro.OCFWriter
+	schema  interface{}
+	named   map[string]namedType
+	pending []interface{}
+}
+
+// namedType is a record, enum or fixed type declared in a schema, along with
+// the namespace its own references are resolved in.
+type namedType struct {
+	schema    map[string]interface{}
+	namespace string
+}
+
+// NewWriter returns a Writer writing records of the Avro schema, given as
+// JSON, to w. Blocks are compressed with deflate.
+func NewWriter(w io.Writer, schema string) (*Writer, error) {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{W: w, Codec: codec, CompressionName: goavro.CompressionDeflateLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	aw := &Writer{ocf: ocf, schema: parsed, named: make(map[string]namedType)}
+	aw.declare(parsed, "")
+	return aw, nil
+}
+
+// Write converts the decoded JSON document doc to the schema and adds it to
+// the file. Object keys match the record field of the same name, or of the
+// name Name returns for them, and missing fields take their default.
+// Strings are accepted for timestamp and date logical types in RFC 3339
+// form.
+func (w *Writer) Write(doc map[string]interface{}) error {
+	datum, err := w.convert(w.schema, "", doc, "")
+	if err != nil {
+		return err
+	}
+	w.pending = append(w.pending, datum)
+	if len(w.pending) >= blockSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered records to the file as one block.
+func (w *Writer) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	err := w.ocf.Append(w.pending)
+	w.pending = w.pending[:0]
+	return err
+}
+
+// Close flushes the buffered records. It does not close the underlying
+// writer.
+func (w *Writer) Close() error {
+	return w.Flush()
+}
+
+// declare records the named types declared in schema, which is in namespace
+// unless it says otherwise.
+func (w *Writer) declare(schema interface{}, namespace string) {
+	switch s := schema.(type) {
+	case []interface{}:
+		for _, branch := range s {
+			w.declare(branch, namespace)
+		}
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record", "error", "enum", "fixed":
+			name := fullName(s, namespace)
+			ns := namespaceOf(name)
+			w.named[name] = namedType{schema: s, namespace: ns}
+			if fields, ok := s["fields"].([]interface{}); ok {
+				for _, f := range fields {
+					if field, ok := f.(map[string]interface{}); ok {
+						w.declare(field["type"], ns)
+					}
+				}
+			}
+		case "array":
+			w.declare(s["items"], namespace)
+		case "map":
+			w.declare(s["values"], namespace)
+		default:
+			w.declare(s["type"], namespace)
+		}
+	}
+}
+
+// lookup returns the named type referred to by name from namespace.
+func (w *Writer) lookup(name, namespace string) (namedType, bool) {
+	if !strings.Contains(name, ".") && namespace != "" {
+		if t, ok := w.named[namespace+"."+name]; ok {
+			return t, true
+		}
+	}
+	t, ok := w.named[name]
+	return t, ok
+}
+
+// convert returns v, found at the JSON Pointer path, in the native form
+// goavro encodes with schema, which is resolved in namespace.
+func (w *Writer) convert(schema interface{}, namespace string, v interface{}, path string) (interface{}, error) {
+	switch s := schema.(type) {
+	case string:
+		if native, ok := primitive(s, v); ok {
+			return native, nil
+		}
+		if t, ok := w.lookup(s, namespace); ok {
+			return w.convert(t.schema, t.namespace, v, path)
+		}
+		return nil, mismatch(path, s, v)
+	case []interface{}:
+		return w.union(s, namespace, v, path)
+	case map[string]interface{}:
+		return w.complex(s, namespace, v, path)
+	default:
+		return nil, fmt.Errorf("%s: invalid Avro schema %v", schemautil.DescribePointer(path), schema)
+	}
+}
+
+// complex converts v to the complex or annotated type schema.
+func (w *Writer) complex(s map[string]interface{}, namespace string, v interface{}, path string) (interface{}, error) {
+	kind, _ := s["type"].(string)
+	switch kind {
+	case "record", "error":
+		doc, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, mismatch(path, "record", v)
+		}
+		ns := namespaceOf(fullName(s, namespace))
+		// Keys the schema does not name exactly are matched by their Avro name
+		byName := make(map[string]string, len(doc))
+		for key := range doc {
+			byName[Name(key)] = key
+		}
+		fields, _ := s["fields"].([]interface{})
+		record := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			field, _ := f.(map[string]interface{})
+			name, _ := field["name"].(string)
+			key := name
+			if _, ok := doc[key]; !ok {
+				if key, ok = byName[name]; !ok {
+					continue
+				}
+			}
+			value, err := w.convert(field["type"], ns, doc[key], schemautil.JoinPointer(path, key))
+			if err != nil {
+				return nil, err
+			}
+			record[name] = value
+		}
+		return record, nil
+	case "enum":
+		str, ok := v.(string)
+		if !ok {
+			return nil, mismatch(path, "enum", v)
+		}
+		symbols, _ := s["symbols"].([]interface{})
+		for _, symbol := range symbols {
+			if symbol == str {
+				return str, nil
+			}
+		}
+		return nil, fmt.Errorf("%s: %w: %q is not a symbol of the enum", schemautil.DescribePointer(path), ErrMismatch, str)
+	case "fixed":
+		str, ok := v.(string)
+		size, _ := s["size"].(float64)
+		if !ok || len(str) != int(size) {
+			return nil, mismatch(path, fmt.Sprintf("fixed of %v bytes", size), v)
+		}
+		return []byte(str), nil
+	case "array":
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, mismatch(path, "array", v)
+		}
+		out := make([]interface{}, len(list))
+		for i, elem := range list {
+			var err error
+			if out[i], err = w.convert(s["items"], namespace, elem, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case "map":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, mismatch(path, "map", v)
+		}
+		out := make(map[string]interface{}, len(m))
+		for key, elem := range m {
+			var err error
+			if out[key], err = w.convert(s["values"], namespace, elem, schemautil.JoinPointer(path, key)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	// A primitive type annotated with a logical type; timestamps and dates
+	// may be given as strings
+	if layout, ok := timeLayouts[s["logicalType"]]; ok {
+		if str, ok := v.(string); ok {
+			ts, err := time.Parse(layout, str)
+			if err != nil {
+				return nil, mismatch(path, s["logicalType"].(string), v)
+			}
+			return ts.UTC(), nil
+		}
+	}
+	return w.convert(s["type"], namespace, v, path)
+}
+
+// timeLayouts maps the logical types goavro encodes from time.Time to the
+// layout of the strings accepted for them.
+var timeLayouts = map[interface{}]string{
+	"timestamp-millis": time.RFC3339Nano,
+	"timestamp-micros": time.RFC3339Nano,
+	"date":             time.DateOnly,
+}
+
+// union converts v to the first branch of the union schema that it matches,
+// wrapped in the form goavro expects for unions.
+func (w *Writer) union(branches []interface{}, namespace string, v interface{}, path string) (interface{}, error) {
+	for _, branch := range branches {
+		if branch == "null" {
+			if v == nil {
+				return nil, nil
+			}
+			continue
+		}
+		native, err := w.convert(branch, namespace, v, path)
+		if err != nil {
+			continue
+		}
+		return goavro.Union(w.branchName(branch, namespace), native), nil
+	}
+	return nil, mismatch(path, "any type of the union", v)
+}
+
+// branchName returns the name goavro knows the union branch schema by.
+func (w *Writer) branchName(branch interface{}, namespace string) string {
+	switch b := branch.(type) {
+	case string:
+		if t, ok := w.lookup(b, namespace); ok {
+			return fullName(t.schema, t.namespace)
+		}
+		return b
+	case map[string]interface{}:
+		kind, _ := b["type"].(string)
+		switch kind {
+		case "record", "error", "enum", "fixed":
+			return fullName(b, namespace)
+		case "array", "map":
+			return kind
+		}
+		if logical, _ := b["logicalType"].(string); logical != "" {
+			return kind + "." + logical
+		}
+		return kind
+	default:
+		return ""
+	}
+}
+
+// primitive returns v in the native form of the primitive Avro type name,
+// and reports false when name is not a primitive type or v does not match
+// it.
+func primitive(name string, v interface{}) (interface{}, bool) {
+	switch name {
+	case "null":
+		return nil, v == nil
+	case "boolean":
+		b, ok := v.(bool)
+		return b, ok
+	case "string":
+		str, ok := v.(string)
+		return str, ok
+	case "bytes":
+		str, ok := v.(string)
+		return []byte(str), ok
+	case "int":
+		n, ok := integer(v)
+		if !ok || n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, false
+		}
+		return int32(n), true
+	case "long":
+		return integer(v)
+	case "float":
+		f, ok := float(v)
+		return float32(f), ok
+	case "double":
+		return float(v)
+	default:
+		return nil, false
+	}
+}
+
+// integer returns the decoded JSON number v as an int64, reporting false
+// when it is not a number or not integral.
+func integer(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case float64:
+		return int64(n), n == math.Trunc(n) && n >= math.MinInt64 && n <= math.MaxInt64
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// float returns the decoded JSON number v as a float64, reporting false when
+// it is not a number.
+func float(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// fullName returns the full name of the named type schema declared in
+// namespace.
+func fullName(schema map[string]interface{}, namespace string) string {
+	name, _ := schema["name"].(string)
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if ns, ok := schema["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// namespaceOf returns the namespace part of a full name.
+func namespaceOf(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// mismatch returns the error for the value v at path not matching the type
+// described by want.
+func mismatch(path, want string, v interface{}) error {
+	return fmt.Errorf("%s: %w: want %s, got %s", schemautil.DescribePointer(path), ErrMismatch, want, jsonType(v))
+}
+
+// jsonType names the JSON type of the decoded value v.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number, float64, int, int64:
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,158 @@
+package avrofile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/avrofile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+)
+
+// decode returns the JSON documents docs decoded as the CLI decodes
+// transformed output, with numbers as json.Number.
+func decode(t *testing.T, docs []string) []map[string]interface{} {
+	t.Helper()
+	out := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		if err := dec.Decode(&out[i]); err != nil {
+			t.Fatalf("decode %s: %v", doc, err)
+		}
+	}
+	return out
+}
+
+// infer returns the Avro schema inferred for docs.
+func infer(docs []map[string]interface{}) (string, error) {
+	inferrer := schemagen.New()
+	for _, doc := range docs {
+		inferrer.Add(doc)
+	}
+	return avrofile.Infer(inferrer.Schema(), "Record")
+}
+
+// readAll returns the records of the Avro Object Container File in data.
+func readAll(t *testing.T, data []byte) []interface{} {
+	t.Helper()
+	r, err := goavro.NewOCFReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewOCFReader = %v", err)
+	}
+	var records []interface{}
+	for r.Scan() {
+		record, err := r.Read()
+		if err != nil {
+			t.Fatalf("Read = %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestWriter checks the schema inferred for documents and the records
+// written with it.
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		docs       []string
+		wantSchema string
+		want       []interface{}
+	}{
+		{
+			name:       "field union across records",
+			docs:       []string{`{"id":1,"name":"a"}`, `{"id":2,"email":"b@c"}`},
+			wantSchema: `{"fields":[{"default":null,"name":"email","type":["null","string"]},{"name":"id","type":"long"},{"default":null,"name":"name","type":["null","string"]}],"name":"Record","type":"record"}`,
+			want: []interface{}{
+				map[string]interface{}{"email": nil, "id": int64(1), "name": map[string]interface{}{"string": "a"}},
+				map[string]interface{}{"email": map[string]interface{}{"string": "b@c"}, "id": int64(2), "name": nil},
+			},
+		},
+		{
+			name:       "nested objects as records",
+			docs:       []string{`{"id":1,"home-address":{"city":"Paris","geo":{"lat":1.5}}}`},
+			wantSchema: `{"fields":[{"name":"home_address","type":{"fields":[{"name":"city","type":"string"},{"name":"geo","type":{"fields":[{"name":"lat","type":"double"}],"name":"Geo","type":"record"}}],"name":"HomeAddress","type":"record"}},{"name":"id","type":"long"}],"name":"Record","type":"record"}`,
+			want: []interface{}{
+				map[string]interface{}{"home_address": map[string]interface{}{"city": "Paris", "geo": map[string]interface{}{"lat": 1.5}}, "id": int64(1)},
+			},
+		},
+		{
+			name:       "64-bit integers kept exact",
+			docs:       []string{`{"id":9007199254740993}`, `{"id":-9223372036854775808}`},
+			wantSchema: `{"fields":[{"name":"id","type":"long"}],"name":"Record","type":"record"}`,
+			want: []interface{}{
+				map[string]interface{}{"id": int64(9007199254740993)},
+				map[string]interface{}{"id": int64(-9223372036854775808)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := decode(t, tt.docs)
+			schema, err := infer(docs)
+			if err != nil {
+				t.Fatalf("Infer = %v", err)
+			}
+			if schema != tt.wantSchema {
+				t.Errorf("Infer =\n%s\nwant\n%s", schema, tt.wantSchema)
+			}
+			var buf bytes.Buffer
+			w, err := avrofile.NewWriter(&buf, schema)
+			if err != nil {
+				t.Fatalf("NewWriter = %v", err)
+			}
+			for _, doc := range docs {
+				if err := w.Write(doc); err != nil {
+					t.Fatalf("Write(%v) = %v", doc, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close = %v", err)
+			}
+			if got := readAll(t, buf.Bytes()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrote %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriterIntegerOverflow checks that an integer beyond 64 bits is
+// rejected for a long field rather than rounded.
+func TestWriterIntegerOverflow(t *testing.T) {
+	w, err := avrofile.NewWriter(&bytes.Buffer{}, `{"type":"record","name":"Record","fields":[{"name":"id","type":"long"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Write(decode(t, []string{`{"id":9223372036854775808}`})[0])
+	if !errors.Is(err, avrofile.ErrMismatch) || !strings.HasPrefix(err.Error(), "/id: ") {
+		t.Errorf("Write = %v, want ErrMismatch at /id", err)
+	}
+}
+
+// TestEmpty checks that no schema is inferred without properties and that a
+// writer given no documents writes a file without records.
+func TestEmpty(t *testing.T) {
+	for _, docs := range [][]string{nil, {`{}`}} {
+		if _, err := infer(decode(t, docs)); err == nil {
+			t.Errorf("Infer for documents %v succeeded, want an error", docs)
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := avrofile.NewWriter(&buf, `{"type":"record","name":"Record","fields":[{"name":"id","type":"long"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if got := readAll(t, buf.Bytes()); len(got) != 0 {
+		t.Errorf("wrote %v, want no records", got)
+	}
+}
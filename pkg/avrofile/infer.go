@@ -0,0 +1,188 @@
+package avrofile
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// avroName matches the names of Avro records and fields.
+var avroName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Infer returns the Avro schema, as JSON, of a record named name for the
+// documents described by the JSON Schema schema, such as the one schemagen
+// infers. Each object with known properties becomes a record, other objects
+// maps, integers long and other numbers double. Properties that are not
+// required, or may be null, become unions with null defaulting to null, and
+// values of several types unions of them. Fields are named after their keys
+// with Name.
+func Infer(schema map[string]interface{}, name string) (string, error) {
+	if !avroName.MatchString(name) {
+		return "", fmt.Errorf("invalid Avro record name %q", name)
+	}
+	in := &inferrer{names: make(map[string]bool)}
+	record, ok := in.record(schema, name)
+	if !ok {
+		return "", fmt.Errorf("schema does not describe an object with properties")
+	}
+	out, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Name returns the Avro field name for the JSON key, replacing characters
+// Avro names cannot hold with underscores, such as zip_code for zip-code.
+func Name(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || r == '_'):
+		case r < unicode.MaxASCII && unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// inferrer tracks the record names used in an inferred schema, which must
+// be unique.
+type inferrer struct {
+	names map[string]bool
+}
+
+// record returns the record schema named after hint for the object schema,
+// reporting false when it has no properties.
+func (in *inferrer) record(schema map[string]interface{}, hint string) (map[string]interface{}, bool) {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return nil, false
+	}
+	required := make(map[string]bool)
+	for _, key := range schemautil.StringList(schema["required"]) {
+		required[key] = true
+	}
+	name := schemautil.Unique(hint, in.names)
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fieldNames := make(map[string]bool)
+	fields := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		prop, _ := props[key].(map[string]interface{})
+		field := map[string]interface{}{
+			"name": schemautil.Unique(Name(key), fieldNames),
+			"type": in.avroType(prop, recordName(key)),
+		}
+		if !required[key] {
+			field["type"] = withNull(field["type"])
+		}
+		// Unions defaulting to null must list null first, as withNull does
+		if union, ok := field["type"].([]interface{}); (ok && union[0] == "null") || field["type"] == "null" {
+			field["default"] = nil
+		}
+		fields = append(fields, field)
+	}
+	return map[string]interface{}{"type": "record", "name": name, "fields": fields}, true
+}
+
+// avroType returns the Avro type of values described by schema, naming
+// their records after hint.
+func (in *inferrer) avroType(schema map[string]interface{}, hint string) interface{} {
+	var union []interface{}
+	for _, t := range schemautil.StringList(schema["type"]) {
+		switch t {
+		case "object":
+			if record, ok := in.record(schema, hint); ok {
+				union = append(union, record)
+			} else {
+				union = append(union, map[string]interface{}{"type": "map", "values": "string"})
+			}
+		case "array":
+			// An array that was always empty has no elements to describe
+			var items interface{} = "string"
+			if schema, ok := schema["items"].(map[string]interface{}); ok && len(schema) > 0 {
+				items = in.avroType(schema, schemautil.Singular(hint))
+			}
+			union = append(union, map[string]interface{}{"type": "array", "items": items})
+		case "string":
+			union = append(union, "string")
+		case "integer":
+			union = append(union, "long")
+		case "number":
+			union = append(union, "double")
+		case "boolean":
+			union = append(union, "boolean")
+		}
+	}
+
+	nullable := false
+	for _, t := range schemautil.StringList(schema["type"]) {
+		nullable = nullable || t == "null"
+	}
+	var avroType interface{}
+	switch len(union) {
+	case 0:
+		return "null"
+	case 1:
+		avroType = union[0]
+	default:
+		avroType = union
+	}
+	if nullable {
+		return withNull(avroType)
+	}
+	return avroType
+}
+
+// withNull returns the union of null and avroType, with null first.
+func withNull(avroType interface{}) interface{} {
+	if avroType == "null" {
+		return avroType
+	}
+	union, ok := avroType.([]interface{})
+	if !ok {
+		return []interface{}{"null", avroType}
+	}
+	if union[0] == "null" {
+		return union
+	}
+	return append([]interface{}{"null"}, union...)
+}
+
+// recordName returns a record name for values found at the JSON key, such
+// as UserAddress for user_address.
+func recordName(key string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(Name(key), "_") {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	name := b.String()
+	if name == "" {
+		return "Record"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "R" + name
+	}
+	return name
+}
@@ -0,0 +1,78 @@
+// Package cborfile writes transformed documents as CBOR (RFC 8949), the
+// compact binary encoding of the JSON data model used by constrained and
+// IoT devices.
+package cborfile
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Writer writes documents as a sequence of CBOR maps, one after another.
+type Writer struct {
+	enc *cbor.Encoder
+}
+
+// NewWriter returns a Writer writing to w with the core deterministic
+// encoding of RFC 8949: sorted map keys and the shortest form of every
+// value, so the same document always encodes to the same bytes.
+func NewWriter(w io.Writer) (*Writer, error) {
+	em, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{enc: em.NewEncoder(w)}, nil
+}
+
+// Write writes the decoded JSON document doc as a CBOR map. Integers are
+// written as CBOR integers, or bignums beyond 64 bits, and other numbers as
+// floats.
+func (w *Writer) Write(doc map[string]interface{}) error {
+	return w.enc.Encode(convert(doc))
+}
+
+// Close does nothing, as every document is written at once. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	return nil
+}
+
+// convert returns the decoded JSON value v with its numbers in the form
+// they are encoded in.
+func convert(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		if !strings.ContainsAny(v.String(), ".eE") {
+			if n, ok := new(big.Int).SetString(v.String(), 10); ok {
+				return n
+			}
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[key] = convert(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = convert(elem)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,140 @@
+// Package celrules compiles transformation rules written as Common
+// Expression Language (CEL) expressions, so rule behaviour can change
+// without recompiling. Each expression sees the raw value wrapped by its
+// type tag as the variable value, for example
+//
+//	value.matches('^\\d+$') ? int(value) : value
+package celrules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Load reads a JSON object mapping type tags to CEL expressions from the file
+// at path and compiles it with Compile.
+func Load(path string) (map[string]transform.TransformationRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var exprs map[string]string
+	if err := json.Unmarshal(data, &exprs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return Compile(exprs)
+}
+
+// Compile compiles each expression in exprs, keyed by type tag, into a
+// transformation rule. Every expression is checked up front so mistakes are
+// reported at startup rather than on the first matching value.
+func Compile(exprs map[string]string) (map[string]transform.TransformationRule, error) {
+	// The extension libraries add string helpers such as lowerAscii and
+	// split, and base64 encoding
+	env, err := cel.NewEnv(
+		cel.Variable("value", cel.DynType),
+		ext.Strings(),
+		ext.Encoders(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compile in a fixed order so the first error reported is stable
+	typeKeys := make([]string, 0, len(exprs))
+	for typeKey := range exprs {
+		typeKeys = append(typeKeys, typeKey)
+	}
+	sort.Strings(typeKeys)
+
+	rules := make(map[string]transform.TransformationRule, len(exprs))
+	for _, typeKey := range typeKeys {
+		ast, issues := env.Compile(exprs[typeKey])
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("rule %q: %w", typeKey, issues.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", typeKey, err)
+		}
+		rules[typeKey] = rule(typeKey, prg)
+	}
+	return rules, nil
+}
+
+// rule returns a TransformationRule evaluating prg. Evaluation errors, such
+// as a failed int() conversion, are returned so the value is omitted.
+func rule(typeKey string, prg cel.Program) transform.TransformationRule {
+	return func(v interface{}) interface{} {
+		out, _, err := prg.Eval(map[string]interface{}{"value": v})
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", typeKey, err)
+		}
+		result, err := native(out)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", typeKey, err)
+		}
+		return result
+	}
+}
+
+// native converts a CEL value into the plain Go values encoding/json writes.
+func native(val ref.Val) (interface{}, error) {
+	switch v := val.(type) {
+	case types.Null:
+		return nil, nil
+	case types.Bool:
+		return bool(v), nil
+	case types.Int:
+		return int64(v), nil
+	case types.Uint:
+		return uint64(v), nil
+	case types.Double:
+		return float64(v), nil
+	case types.String:
+		return string(v), nil
+	case types.Bytes:
+		return []byte(v), nil
+	case *types.Err:
+		return nil, v
+	case traits.Mapper:
+		out := make(map[string]interface{})
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+			k, ok := key.(types.String)
+			if !ok {
+				return nil, fmt.Errorf("map key %v is not a string", key)
+			}
+			elem, err := native(v.Get(key))
+			if err != nil {
+				return nil, err
+			}
+			out[string(k)] = elem
+		}
+		return out, nil
+	case traits.Lister:
+		out := make([]interface{}, 0)
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			elem, err := native(it.Next())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, elem)
+		}
+		return out, nil
+	default:
+		// Anything else, such as timestamps, is passed through as CEL
+		// represents it natively
+		return val.Value(), nil
+	}
+}
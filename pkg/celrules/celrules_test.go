@@ -0,0 +1,177 @@
+package celrules_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/celrules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// transformer returns a Transformer with the rules compiled from exprs.
+func transformer(t *testing.T, exprs map[string]string, opts ...transform.Option) *transform.Transformer {
+	t.Helper()
+	rules, err := celrules.Compile(exprs)
+	if err != nil {
+		t.Fatalf("Compile = %v", err)
+	}
+	for typeKey, rule := range rules {
+		opts = append(opts, transform.WithRule(typeKey, rule))
+	}
+	return transform.New(opts...)
+}
+
+// decode returns the JSON object in s.
+func decode(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// TestCompileErrors checks that invalid expressions are reported at compile
+// time, naming their rule.
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		exprs map[string]string
+		want  string
+	}{
+		{name: "syntax error", exprs: map[string]string{"UUID": "value +"}, want: `rule "UUID": `},
+		{name: "undeclared variable", exprs: map[string]string{"UUID": "val"}, want: `rule "UUID": `},
+		{name: "first rule in key order", exprs: map[string]string{"B": "(", "A": ")"}, want: `rule "A": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := celrules.Compile(tt.exprs)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Compile = %v, want an error starting %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad checks that rules are read from a JSON file.
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	if err := os.WriteFile(path, []byte(`{"UUID": "value.lowerAscii()"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := celrules.Load(path)
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	if got := rules["UUID"]("AB-CD"); got != "ab-cd" {
+		t.Errorf("rule(AB-CD) = %v, want ab-cd", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`["UUID"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := celrules.Load(path); err == nil || !strings.HasPrefix(err.Error(), path+": ") {
+		t.Errorf("Load of a JSON array = %v, want an error naming the file", err)
+	}
+}
+
+// TestRules checks the values rules produce, and that evaluation errors
+// make values invalid.
+func TestRules(t *testing.T) {
+	exprs := map[string]string{
+		"ID":   `value.matches('^\\d+$') ? int(value) : value`,
+		"INT":  `int(value)`,
+		"TAGS": `value.split(',')`,
+		"PAIR": `{'key': value, 'size': size(value)}`,
+	}
+	input := decode(t, `{"a":{"ID":"42"},"b":{"ID":"x1"},"c":{"INT":"abc"},"d":{"TAGS":"x,y"},"e":{"PAIR":"ab"}}`)
+	want := map[string]interface{}{
+		"a": int64(42),
+		"b": "x1",
+		"d": []interface{}{"x", "y"},
+		"e": map[string]interface{}{"key": "ab", "size": int64(2)},
+	}
+	if got := transformer(t, exprs).Transform(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform = %#v, want %#v", got, want)
+	}
+
+	_, err := transformer(t, exprs, transform.WithOnError(transform.OnErrorFail)).TransformChecked(input)
+	var pathErr *transform.PathError
+	if !errors.As(err, &pathErr) || pathErr.Pointer != "/c" || !strings.Contains(err.Error(), `rule "INT": `) {
+		t.Errorf("TransformChecked = %v, want the INT rule failing at /c", err)
+	}
+}
+
+// TestRulesContextCancelled checks that a stream transformed with the rules
+// stops once its context is cancelled.
+func TestRulesContextCancelled(t *testing.T) {
+	rules, err := celrules.Compile(map[string]string{"UUID": "value.lowerAscii()"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel while the first record is being transformed
+	tr := transform.New(transform.WithRule("UUID", func(v interface{}) interface{} {
+		cancel()
+		return rules["UUID"](v)
+	}))
+
+	in := strings.Repeat(`{"id":{"UUID":"AB"}}`+"\n", 3)
+	var out bytes.Buffer
+	err = tr.TransformNDJSONContext(ctx, strings.NewReader(in), &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("TransformNDJSONContext = %v, want context.Canceled", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("wrote %q, want nothing", out.String())
+	}
+}
+
+// TestFilter checks which records a filter matches.
+func TestFilter(t *testing.T) {
+	filter, err := celrules.CompileFilter(`status == "ACTIVE" && amount > 100 && record["created-at"] != ""`)
+	if err != nil {
+		t.Fatalf("CompileFilter = %v", err)
+	}
+	tests := []struct {
+		record string
+		want   bool
+	}{
+		{record: `{"status":"ACTIVE","amount":150,"created-at":"2024"}`, want: true},
+		{record: `{"status":"ACTIVE","amount":100,"created-at":"2024"}`, want: false},
+		{record: `{"status":"ACTIVE","amount":150}`, want: false},
+		{record: `{"amount":150,"created-at":"2024"}`, want: false},
+	}
+	for _, tt := range tests {
+		dec := json.NewDecoder(strings.NewReader(tt.record))
+		dec.UseNumber()
+		var record map[string]interface{}
+		if err := dec.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		match, err := filter(record)
+		if err != nil || match != tt.want {
+			t.Errorf("filter(%s) = %v, %v, want %v", tt.record, match, err, tt.want)
+		}
+	}
+
+	if _, err := celrules.CompileFilter(`status ==`); err == nil {
+		t.Error("CompileFilter of an incomplete expression succeeded, want an error")
+	}
+	filter, err = celrules.CompileFilter(`amount + 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := filter(map[string]interface{}{"amount": json.Number("1")}); err == nil {
+		t.Error("filter returning a number succeeded, want an error")
+	}
+}
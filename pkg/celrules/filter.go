@@ -0,0 +1,88 @@
+package celrules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// CompileFilter compiles expr into a record filter, such as
+//
+//	status == "ACTIVE" && amount > 100
+//
+// The top-level fields of each plain record are variables of the
+// expression, and the whole record is also available as record, for fields
+// whose names are not identifiers: record["created-at"]. Records the
+// expression cannot be evaluated for, such as ones missing a field it uses,
+// do not match; an expression evaluating to anything but a boolean is an
+// error.
+func CompileFilter(expr string) (transform.RecordFilter, error) {
+	env, err := cel.NewEnv(ext.Strings(), ext.Encoders())
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields differ between records, so the expression is only parsed and
+	// its identifiers are resolved against each record when evaluated
+	ast, issues := env.Parse(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("filter: %w", issues.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+
+	return func(record map[string]interface{}) (bool, error) {
+		fields := celValue(record).(map[string]interface{})
+		vars := make(map[string]interface{}, len(fields)+1)
+		for key, value := range fields {
+			vars[key] = value
+		}
+		vars["record"] = fields
+
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			return false, nil
+		}
+		match, ok := out.(types.Bool)
+		if !ok {
+			return false, fmt.Errorf("filter %q returned %s, want a boolean", expr, out.Type().TypeName())
+		}
+		return bool(match), nil
+	}, nil
+}
+
+// celValue converts the numbers of a transformed value, which are
+// json.Number for integers, into values CEL compares numerically.
+func celValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[key] = celValue(elem)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = celValue(elem)
+		}
+		return out
+	default:
+		return v
+	}
+}
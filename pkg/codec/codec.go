@@ -0,0 +1,113 @@
+// Package codec abstracts the JSON encoder and decoder behind an interface so
+// a faster implementation than encoding/json can be selected, either at build
+// time with the codec_goccy or codec_jsoniter build tags, or at runtime with
+// Use. Every codec decodes numbers as json.Number, sorts map keys and writes
+// <, > and & as they are unless SetEscapeHTML turns escaping on, so outputs
+// are identical whichever is selected.
+package codec
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Decoder reads successive JSON values from a stream.
+type Decoder interface {
+	// Decode reads the next value into v, returning io.EOF at the end of
+	// the stream.
+	Decode(v interface{}) error
+}
+
+// Encoder writes JSON values to a stream, each followed by a newline.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Codec is a JSON implementation.
+type Codec interface {
+	// Name returns the name the codec is selected by.
+	Name() string
+	// Marshal returns the compact encoding of v.
+	Marshal(v interface{}) ([]byte, error)
+	// NewDecoder returns a Decoder reading from r that keeps numbers as
+	// json.Number.
+	NewDecoder(r io.Reader) Decoder
+	// NewEncoder returns an Encoder writing to w.
+	NewEncoder(w io.Writer) Encoder
+}
+
+// codecs holds every available codec by name.
+var codecs = map[string]Codec{}
+
+func init() {
+	for _, c := range []Codec{Std, Goccy, Jsoniter} {
+		codecs[c.Name()] = c
+	}
+}
+
+// current holds the codec returned by Default, wrapped in a holder since
+// atomic.Value needs every stored value to have the same concrete type.
+var current atomic.Value
+
+// holder wraps a Codec for storage in current.
+type holder struct {
+	Codec
+}
+
+func init() {
+	current.Store(holder{codecs[buildDefault]})
+}
+
+// Default returns the codec in use, which is std unless changed by a build
+// tag or by Use.
+func Default() Codec {
+	return current.Load().(holder).Codec
+}
+
+// Lookup returns the codec registered under name.
+func Lookup(name string) (Codec, error) {
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown JSON codec %q, want one of %s", name, strings.Join(Names(), ", "))
+	}
+	return c, nil
+}
+
+// Use makes the codec registered under name the Default.
+func Use(name string) error {
+	c, err := Lookup(name)
+	if err != nil {
+		return err
+	}
+	current.Store(holder{c})
+	return nil
+}
+
+// escapeHTML is set by SetEscapeHTML.
+var escapeHTML atomic.Bool
+
+// SetEscapeHTML makes every codec escape <, > and & in strings as \u003c,
+// \u003e and \u0026, as encoding/json does by default, when on is set. By
+// default they are written as they are, so URLs and markup reach consumers
+// unchanged.
+func SetEscapeHTML(on bool) {
+	escapeHTML.Store(on)
+}
+
+// EscapeHTML reports whether SetEscapeHTML turned HTML escaping on.
+func EscapeHTML() bool {
+	return escapeHTML.Load()
+}
+
+// Names returns the names of the available codecs in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,6 @@
+//go:build !codec_goccy && !codec_jsoniter
+
+package codec
+
+// buildDefault names the codec used unless Use selects another.
+const buildDefault = "std"
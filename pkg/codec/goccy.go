@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"io"
+
+	gojson "github.com/goccy/go-json"
+)
+
+// Goccy is the github.com/goccy/go-json codec.
+var Goccy Codec = goccyCodec{}
+
+// goccyCodec implements Codec with github.com/goccy/go-json.
+type goccyCodec struct{}
+
+// Name returns "goccy".
+func (goccyCodec) Name() string {
+	return "goccy"
+}
+
+// Marshal returns the compact encoding of v.
+func (goccyCodec) Marshal(v interface{}) ([]byte, error) {
+	if EscapeHTML() {
+		return gojson.Marshal(v)
+	}
+	return gojson.MarshalWithOption(v, gojson.DisableHTMLEscape())
+}
+
+// NewDecoder returns a go-json decoder reading numbers as json.Number.
+func (goccyCodec) NewDecoder(r io.Reader) Decoder {
+	dec := gojson.NewDecoder(r)
+	dec.UseNumber()
+	return dec
+}
+
+// NewEncoder returns a go-json encoder writing to w.
+func (goccyCodec) NewEncoder(w io.Writer) Encoder {
+	enc := gojson.NewEncoder(w)
+	enc.SetEscapeHTML(EscapeHTML())
+	return enc
+}
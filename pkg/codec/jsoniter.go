@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Jsoniter is the github.com/json-iterator/go codec, configured to match
+// encoding/json output.
+var Jsoniter Codec = jsoniterCodec{
+	escaping: jsoniter.Config{EscapeHTML: true, SortMapKeys: true, UseNumber: true}.Froze(),
+	plain:    jsoniter.Config{EscapeHTML: false, SortMapKeys: true, UseNumber: true}.Froze(),
+}
+
+// jsoniterCodec implements Codec with github.com/json-iterator/go, with one
+// configuration escaping HTML and one leaving it as it is.
+type jsoniterCodec struct {
+	escaping, plain jsoniter.API
+}
+
+// api returns the configuration selected by SetEscapeHTML.
+func (c jsoniterCodec) api() jsoniter.API {
+	if EscapeHTML() {
+		return c.escaping
+	}
+	return c.plain
+}
+
+// Name returns "jsoniter".
+func (jsoniterCodec) Name() string {
+	return "jsoniter"
+}
+
+// Marshal returns the compact encoding of v.
+func (c jsoniterCodec) Marshal(v interface{}) ([]byte, error) {
+	return c.api().Marshal(v)
+}
+
+// NewDecoder returns a jsoniter decoder reading numbers as json.Number.
+func (c jsoniterCodec) NewDecoder(r io.Reader) Decoder {
+	return jsoniterDecoder{c.api().NewDecoder(r)}
+}
+
+// NewEncoder returns a jsoniter encoder writing to w.
+func (c jsoniterCodec) NewEncoder(w io.Writer) Encoder {
+	return c.api().NewEncoder(w)
+}
+
+// jsoniterDecoder adapts a jsoniter decoder, which reports a syntax error
+// rather than io.EOF when only whitespace remains, to Decoder.
+type jsoniterDecoder struct {
+	dec *jsoniter.Decoder
+}
+
+// Decode reads the next value into v, returning io.EOF at the end of the stream.
+func (d jsoniterDecoder) Decode(v interface{}) error {
+	if !d.dec.More() {
+		return io.EOF
+	}
+	return d.dec.Decode(v)
+}
@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// Std is the encoding/json codec.
+var Std Codec = stdCodec{}
+
+// stdCodec implements Codec with encoding/json.
+type stdCodec struct{}
+
+// Name returns "std".
+func (stdCodec) Name() string {
+	return "std"
+}
+
+// Marshal returns the compact encoding of v.
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	if EscapeHTML() {
+		return json.Marshal(v)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	// Encode terminates the value with a newline Marshal does not write
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// NewDecoder returns a json.Decoder reading numbers as json.Number.
+func (stdCodec) NewDecoder(r io.Reader) Decoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return dec
+}
+
+// NewEncoder returns a json.Encoder writing to w.
+func (stdCodec) NewEncoder(w io.Writer) Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(EscapeHTML())
+	return enc
+}
@@ -0,0 +1,135 @@
+// Package compress transparently decompresses gzip and zstd input and
+// compresses output streams.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Format is a compression format.
+type Format int
+
+const (
+	// None leaves the stream uncompressed.
+	None Format = iota
+	// Gzip is the gzip format, as used by DynamoDB export to S3.
+	Gzip
+	// Zstd is the Zstandard format.
+	Zstd
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ParseFormat parses the name of a Format: none, gzip or zstd. The empty
+// string is treated as none.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "", "none":
+		return None, nil
+	case "gzip", "gz":
+		return Gzip, nil
+	case "zstd", "zst":
+		return Zstd, nil
+	default:
+		return 0, fmt.Errorf("unknown compression format %q", name)
+	}
+}
+
+// String returns the name of f as accepted by ParseFormat.
+func (f Format) String() string {
+	switch f {
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	default:
+		return "none"
+	}
+}
+
+// FormatOf returns the Format implied by the extension of name, such as
+// .gz, .tgz or .zst, or None when the extension is not a compressed one.
+func FormatOf(name string) Format {
+	switch {
+	case strings.HasSuffix(name, ".gz"), strings.HasSuffix(name, ".tgz"):
+		return Gzip
+	case strings.HasSuffix(name, ".zst"):
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// NewReader returns a reader decompressing r. The format is taken from the
+// extension of name when it has one, otherwise it is detected from the magic
+// bytes at the start of the stream; uncompressed input is passed through.
+// Closing the returned reader does not close r.
+func NewReader(name string, r io.Reader) (io.ReadCloser, error) {
+	format := FormatOf(name)
+	if format == None {
+		br := bufio.NewReader(r)
+		format = sniff(br)
+		r = br
+	}
+
+	switch format {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+// sniff detects the Format of br from its leading magic bytes without
+// consuming them.
+func sniff(br *bufio.Reader) Format {
+	head, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(head, zstdMagic):
+		return Zstd
+	default:
+		return None
+	}
+}
+
+// NewWriter returns a writer compressing into w with format. The returned
+// writer must be closed to flush the compressed stream; closing it does not
+// close w.
+func NewWriter(w io.Writer, format Format) (io.WriteCloser, error) {
+	switch format {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+// nopWriteCloser is an io.WriteCloser whose Close does nothing.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing.
+func (nopWriteCloser) Close() error {
+	return nil
+}
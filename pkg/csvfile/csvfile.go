@@ -0,0 +1,136 @@
+// Package csvfile writes transformed documents as the rows of a CSV file,
+// flattening nested objects into columns named by their dotted key paths,
+// such as address.city.
+package csvfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// Writer writes documents as CSV rows below a header row.
+type Writer struct {
+	cw      *csv.Writer
+	columns [][]string
+	record  []string
+}
+
+// NewWriter returns a Writer writing to w the rows of the documents
+// described by the JSON Schema schema, such as the one schemagen infers from
+// them, and writes the header row. Every value that is not an object with
+// known properties gets a column, in the order of the dotted key paths, so
+// the columns are the union of those of every document.
+func NewWriter(w io.Writer, schema map[string]interface{}) (*Writer, error) {
+	columns := leafPaths(schema, nil)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("schema does not describe an object with properties")
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return strings.Join(columns[i], ".") < strings.Join(columns[j], ".")
+	})
+
+	header := make([]string, len(columns))
+	for i, path := range columns {
+		header[i] = strings.Join(path, ".")
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &Writer{cw: cw, columns: columns, record: make([]string, len(columns))}, nil
+}
+
+// Write adds the decoded JSON document doc as a row. Missing values and null
+// are empty cells, and arrays and objects without columns of their own are
+// written as JSON.
+func (w *Writer) Write(doc map[string]interface{}) error {
+	for i, path := range w.columns {
+		cell, err := format(lookup(doc, path))
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(path, "."), err)
+		}
+		w.record[i] = cell
+	}
+	return w.cw.Write(w.record)
+}
+
+// Close writes any buffered rows. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+// leafPaths returns the key paths, below prefix, of the values in the object
+// schema that are not themselves objects with known properties.
+func leafPaths(schema map[string]interface{}, prefix []string) [][]string {
+	var paths [][]string
+	props, _ := schema["properties"].(map[string]interface{})
+	for key, p := range props {
+		prop, _ := p.(map[string]interface{})
+		path := append(append([]string(nil), prefix...), key)
+		if isObject(prop) {
+			if nested := leafPaths(prop, path); len(nested) > 0 {
+				paths = append(paths, nested...)
+				continue
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// isObject reports whether schema only describes objects, or null, so that
+// its values can be flattened.
+func isObject(schema map[string]interface{}) bool {
+	found := false
+	for _, t := range schemautil.StringList(schema["type"]) {
+		if t != "object" && t != "null" {
+			return false
+		}
+		found = found || t == "object"
+	}
+	return found
+}
+
+// lookup returns the value at path in doc, or nil when there is none.
+func lookup(doc map[string]interface{}, path []string) interface{} {
+	var v interface{} = doc
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// format returns the cell text of the decoded JSON value v.
+func format(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		out, err := codec.Default().Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+}
@@ -0,0 +1,112 @@
+package csvfile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/csvfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+)
+
+// decode returns the JSON documents docs decoded as the CLI decodes
+// transformed output, with numbers as json.Number.
+func decode(t *testing.T, docs []string) []map[string]interface{} {
+	t.Helper()
+	out := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		if err := dec.Decode(&out[i]); err != nil {
+			t.Fatalf("decode %s: %v", doc, err)
+		}
+	}
+	return out
+}
+
+// TestWriter checks the header and rows written for documents of the
+// schema inferred from them.
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []string
+		want string
+	}{
+		{
+			name: "column union across records",
+			docs: []string{`{"id":1,"name":"a"}`, `{"id":2,"email":"b@c"}`},
+			want: "email,id,name\n,1,a\nb@c,2,\n",
+		},
+		{
+			name: "nested objects flattened",
+			docs: []string{`{"id":1,"address":{"city":"Paris","geo":{"lat":1.5}}}`, `{"id":2,"address":null}`},
+			want: "address.city,address.geo.lat,id\nParis,1.5,1\n,,2\n",
+		},
+		{
+			name: "big integers kept exact",
+			docs: []string{`{"id":9007199254740993,"big":123456789012345678901234567890}`},
+			want: "big,id\n123456789012345678901234567890,9007199254740993\n",
+		},
+		{
+			name: "arrays, booleans and mixed values as JSON",
+			docs: []string{`{"tags":["a","b"],"ok":true,"v":{"x":1}}`, `{"tags":[],"ok":false,"v":"s"}`},
+			want: "ok,tags,v\ntrue,\"[\"\"a\"\",\"\"b\"\"]\",\"{\"\"x\"\":1}\"\nfalse,[],s\n",
+		},
+		{
+			name: "quoted cells",
+			docs: []string{`{"note":"a, \"b\"\nc"}`},
+			want: "note\n\"a, \"\"b\"\"\nc\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := decode(t, tt.docs)
+			inferrer := schemagen.New()
+			for _, doc := range docs {
+				inferrer.Add(doc)
+			}
+			var buf bytes.Buffer
+			w, err := csvfile.NewWriter(&buf, inferrer.Schema())
+			if err != nil {
+				t.Fatalf("NewWriter = %v", err)
+			}
+			for _, doc := range docs {
+				if err := w.Write(doc); err != nil {
+					t.Fatalf("Write(%v) = %v", doc, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("wrote\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestWriterEmpty checks that a schema without properties is rejected and
+// that a writer given no documents writes only the header.
+func TestWriterEmpty(t *testing.T) {
+	for _, docs := range [][]string{nil, {`{}`, `{}`}} {
+		inferrer := schemagen.New()
+		for _, doc := range decode(t, docs) {
+			inferrer.Add(doc)
+		}
+		if _, err := csvfile.NewWriter(&bytes.Buffer{}, inferrer.Schema()); err == nil {
+			t.Errorf("NewWriter for documents %v succeeded, want an error", docs)
+		}
+	}
+
+	inferrer := schemagen.New()
+	inferrer.Add(decode(t, []string{`{"b":1,"a":2}`})[0])
+	var buf bytes.Buffer
+	w, err := csvfile.NewWriter(&buf, inferrer.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil || buf.String() != "a,b\n" {
+		t.Errorf("wrote %q, %v, want only the header", buf.String(), err)
+	}
+}
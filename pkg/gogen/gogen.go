@@ -0,0 +1,215 @@
+// Package gogen generates Go struct definitions with json tags from a JSON
+// Schema describing plain JSON documents, such as the one schemagen infers
+// from transformed output, so consumers can unmarshal the documents into
+// typed values instead of map[string]interface{}.
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// Generate returns the gofmt-formatted source of package pkg declaring the
+// struct typeName for documents described by schema, along with a struct
+// for every nested object with known properties. Required properties become
+// plain fields; optional and nullable ones become pointers, or keep their
+// zero value for slices, maps and interfaces, and are tagged omitempty.
+// Strings in the date-time format become time.Time.
+func Generate(schema map[string]interface{}, pkg, typeName string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	if !token.IsIdentifier(typeName) {
+		return nil, fmt.Errorf("invalid type name %q", typeName)
+	}
+
+	g := &generator{names: make(map[string]bool)}
+	if _, ok := g.object(schema, typeName, "is a transformed document"); !ok {
+		return nil, fmt.Errorf("schema does not describe an object with properties")
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if g.usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+	for _, st := range g.structs {
+		fmt.Fprintf(&b, "// %s %s.\n", st.name, st.doc)
+		fmt.Fprintf(&b, "type %s struct {\n", st.name)
+		for _, f := range st.fields {
+			fmt.Fprintf(&b, "\t%s %s `json:%s`\n", f.name, f.goType, strconv.Quote(f.tag))
+		}
+		b.WriteString("}\n\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// generator collects the structs declared for a schema.
+type generator struct {
+	structs  []*structType
+	names    map[string]bool
+	usesTime bool
+}
+
+// structType is a struct declaration.
+type structType struct {
+	name   string
+	doc    string
+	fields []structField
+}
+
+// structField is a field of a struct declaration.
+type structField struct {
+	name, goType, tag string
+}
+
+// object declares a struct for the object schema, named after hint and
+// documented as doc, and returns its name. It reports false when schema has
+// no properties, which leaves nothing to declare.
+func (g *generator) object(schema map[string]interface{}, hint, doc string) (string, bool) {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return "", false
+	}
+	required := make(map[string]bool)
+	for _, key := range schemautil.StringList(schema["required"]) {
+		required[key] = true
+	}
+
+	st := &structType{name: g.typeName(hint), doc: doc}
+	g.structs = append(g.structs, st)
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fieldNames := make(map[string]bool)
+	for _, key := range keys {
+		prop, _ := props[key].(map[string]interface{})
+		name := schemautil.Unique(fieldName(key), fieldNames)
+		goType, nilable := g.goType(prop, name, fmt.Sprintf("is the value of %q", key))
+		tag := key
+		if !required[key] || schemautil.Nullable(prop) {
+			tag += ",omitempty"
+			if !nilable {
+				goType = "*" + goType
+			}
+		}
+		st.fields = append(st.fields, structField{name: name, goType: goType, tag: tag})
+	}
+	return st.name, true
+}
+
+// goType returns the Go type of values described by schema, and reports
+// whether the type already has a nil value. Structs declared for the values
+// are named after hint and documented as doc.
+func (g *generator) goType(schema map[string]interface{}, hint, doc string) (string, bool) {
+	var types []string
+	for _, t := range schemautil.StringList(schema["type"]) {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		return "interface{}", true
+	}
+
+	switch types[0] {
+	case "object":
+		if name, ok := g.object(schema, hint, doc); ok {
+			return name, false
+		}
+		return "map[string]interface{}", true
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		elem, _ := g.goType(items, schemautil.Singular(hint), strings.Replace(doc, "is the value of", "is an element of", 1))
+		return "[]" + elem, true
+	case "string":
+		if schema["format"] == "date-time" {
+			g.usesTime = true
+			return "time.Time", false
+		}
+		return "string", false
+	case "integer":
+		return "int64", false
+	case "number":
+		return "float64", false
+	case "boolean":
+		return "bool", false
+	default:
+		return "interface{}", true
+	}
+}
+
+// typeName returns hint as an unused type name.
+func (g *generator) typeName(hint string) string {
+	return schemautil.Unique(hint, g.names)
+}
+
+// initialisms are words written in upper case in Go identifiers.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "TTL": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// fieldName returns an exported Go identifier for the JSON key, such as
+// UserID for user_id.
+func fieldName(key string) string {
+	var b strings.Builder
+	for _, word := range words(key) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if first := []rune(name)[0]; !unicode.IsUpper(first) {
+		// Digits and caseless letters cannot start an exported name
+		name = "X" + name
+	}
+	return name
+}
+
+// words splits key into words at characters that cannot appear in an
+// identifier and where a lower-case letter is followed by an upper-case one.
+func words(key string) []string {
+	var (
+		list []string
+		word []rune
+	)
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				list = append(list, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 && unicode.IsLower(word[len(word)-1]) {
+			list = append(list, string(word))
+			word = nil
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		list = append(list, string(word))
+	}
+	return list
+}
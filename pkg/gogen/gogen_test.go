@@ -0,0 +1,104 @@
+package gogen_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/gogen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+)
+
+// update rewrites the golden files with the generated output.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// infer returns the schema schemagen infers for the JSON documents docs.
+func infer(t *testing.T, docs ...string) map[string]interface{} {
+	t.Helper()
+	inferrer := schemagen.New()
+	for _, doc := range docs {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+		inferrer.Add(v)
+	}
+	return inferrer.Schema()
+}
+
+// TestGenerate compares the source generated for inferred schemas with the
+// golden files in testdata.
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []string
+	}{
+		{
+			name: "identifiers",
+			docs: []string{`{"user_id":1,"first_name":"a","apiURL":"u","html-body":"b","2fa":true,"ip":"x","ID":2,"_":"z"}`},
+		},
+		{
+			name: "optional and nullable",
+			docs: []string{
+				`{"id":1,"name":"a","tags":["x"],"meta":{},"score":1.5,"note":null,"created_at":"2024-01-02T03:04:05Z"}`,
+				`{"id":2,"tags":[],"meta":{},"score":2,"note":"n","created_at":"2024-01-03T00:00:00Z","any":[1,"x"]}`,
+			},
+		},
+		{
+			name: "nested",
+			docs: []string{
+				`{"address":{"city":"Paris","geo":{"lat":1.5}},"addresses":[{"city":"Lyon"}],"items":[{"sku":"a"}]}`,
+				`{"address":{"city":"Nice","geo":{"lat":2}},"addresses":[],"entries":[{"sku":1}]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gogen.Generate(infer(t, tt.docs...), "model", "Record")
+			if err != nil {
+				t.Fatalf("Generate = %v", err)
+			}
+			golden := filepath.Join("testdata", strings.ReplaceAll(tt.name, " ", "_")+".go.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Generate =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestGenerateErrors checks the errors for invalid names and schemas
+// without properties.
+func TestGenerateErrors(t *testing.T) {
+	schema := infer(t, `{"id":1}`)
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		pkg, typ string
+		want     string
+	}{
+		{name: "package", schema: schema, pkg: "my-model", typ: "Record", want: `invalid package name "my-model"`},
+		{name: "type", schema: schema, pkg: "model", typ: "1Record", want: `invalid type name "1Record"`},
+		{name: "no properties", schema: infer(t, `{}`), pkg: "model", typ: "Record", want: "schema does not describe an object with properties"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gogen.Generate(tt.schema, tt.pkg, tt.typ); err == nil || err.Error() != tt.want {
+				t.Errorf("Generate = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package goplugin loads transformation rules from shared objects built with
+// Go's plugin package (go build -buildmode=plugin). Each plugin must export
+//
+//	func Rules() map[string]transform.TransformationRule
+//
+// and be built with the same Go version and dependency versions as the
+// program loading it.
+package goplugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Load opens the plugin at path and returns the rules exported by its Rules
+// function.
+func Load(path string) (map[string]transform.TransformationRule, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup("Rules")
+	if err != nil {
+		return nil, err
+	}
+	rules, ok := sym.(func() map[string]transform.TransformationRule)
+	if !ok {
+		return nil, fmt.Errorf("%s: Rules has type %T, want func() map[string]transform.TransformationRule", path, sym)
+	}
+	return rules(), nil
+}
+
+// LoadDir loads every .so file in dir in name order and merges their rules.
+// Two plugins defining a rule for the same type tag is an error, since which
+// one should win is ambiguous.
+func LoadDir(dir string) (map[string]transform.TransformationRule, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	merged := make(map[string]transform.TransformationRule)
+	origin := make(map[string]string)
+	for _, path := range paths {
+		rules, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		for typeKey, rule := range rules {
+			if prev, ok := origin[typeKey]; ok {
+				return nil, fmt.Errorf("rule %q is defined by both %s and %s", typeKey, prev, path)
+			}
+			merged[typeKey] = rule
+			origin[typeKey] = path
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,155 @@
+// Package httpio reads documents addressed by http:// and https:// URLs,
+// retrying failed requests with exponential backoff.
+package httpio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults used for the zero fields of Options.
+const (
+	DefaultBackoff    = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Options configure how documents are fetched.
+type Options struct {
+	// Timeout bounds each attempt, including reading the response body; zero
+	// sets no bound
+	Timeout time.Duration
+	// Retries is the number of times a failed request is retried
+	Retries int
+	// Backoff is the delay before the first retry, doubled for each retry
+	// after it up to DefaultMaxBackoff; zero means DefaultBackoff
+	Backoff time.Duration
+	// Token, if set, is sent as a bearer token in the Authorization header
+	Token string
+	// Client sends the requests; nil means http.DefaultClient
+	Client *http.Client
+}
+
+// IsURL reports whether name is an http:// or https:// URL.
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// StatusError is returned for a response with a status other than 2xx.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+// Error describes the response.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %s", e.URL, e.Status)
+}
+
+// Open fetches the document at rawURL and returns its body, which the
+// caller must close. Connection failures, timeouts and responses with status
+// 408, 429 or 5xx are retried as opts allow, honouring any Retry-After
+// header; other failures and the last one are returned.
+func Open(ctx context.Context, rawURL string, opts Options) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, retryAfter, err := get(ctx, client, req, opts.Timeout)
+		if err == nil {
+			return body, nil
+		}
+		if attempt >= opts.Retries || !retryable(ctx, err) {
+			return nil, err
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = min(backoff<<attempt, DefaultMaxBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// get makes one attempt at req within timeout, returning the body of a 2xx
+// response or the delay a 429 or 503 response asks for before retrying.
+func get(ctx context.Context, client *http.Client, req *http.Request, timeout time.Duration) (io.ReadCloser, time.Duration, error) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	resp, err := client.Do(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, 0, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		cancel()
+		return nil, retryAfter(resp.Header.Get("Retry-After")), &StatusError{URL: req.URL.Redacted(), StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return &body{ReadCloser: resp.Body, cancel: cancel}, 0, nil
+}
+
+// retryable reports whether the failed attempt with err is worth retrying,
+// which it is not once ctx is done.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+	}
+	// Transport failures, including an attempt timing out
+	return true
+}
+
+// retryAfter returns the delay given by a Retry-After header in seconds, or
+// 0 when there is none or it is an HTTP date.
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return min(time.Duration(seconds)*time.Second, DefaultMaxBackoff)
+}
+
+// body is a response body releasing the timeout of its attempt when closed.
+type body struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body.
+func (b *body) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
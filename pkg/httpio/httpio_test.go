@@ -0,0 +1,165 @@
+package httpio_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/httpio"
+)
+
+// read returns the body fetched from url with opts.
+func read(url string, opts httpio.Options) (string, error) {
+	body, err := httpio.Open(context.Background(), url, opts)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	return string(data), err
+}
+
+// TestOpen checks that a document is fetched with the bearer token.
+func TestOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			http.Error(w, "missing token "+got, http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"a":{"S":"x"}}`)
+	}))
+	defer srv.Close()
+
+	got, err := read(srv.URL+"/doc.json", httpio.Options{Token: "secret", Timeout: time.Second})
+	if err != nil || got != `{"a":{"S":"x"}}` {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+	if !httpio.IsURL(srv.URL) || httpio.IsURL("doc.json") {
+		t.Error("IsURL misclassifies URLs")
+	}
+}
+
+// TestOpenRetries checks which failures are retried and how often.
+func TestOpenRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		want     string
+		wantCode int
+		attempts int32
+	}{
+		{name: "server errors retried", statuses: []int{503, 500, 200}, retries: 2, want: "ok", attempts: 3},
+		{name: "rate limit and timeout retried", statuses: []int{429, 408, 200}, retries: 3, want: "ok", attempts: 3},
+		{name: "retries exhausted", statuses: []int{502, 503, 504}, retries: 1, wantCode: 503, attempts: 2},
+		{name: "client errors not retried", statuses: []int{404, 200}, retries: 3, wantCode: 404, attempts: 1},
+		{name: "no retries", statuses: []int{500, 200}, wantCode: 500, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[attempts.Add(1)-1]
+				if status != http.StatusOK {
+					// Ask for no delay beyond the backoff
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(status)
+					return
+				}
+				io.WriteString(w, "ok")
+			}))
+			defer srv.Close()
+
+			got, err := read(srv.URL, httpio.Options{Retries: tt.retries, Backoff: time.Millisecond})
+			var statusErr *httpio.StatusError
+			switch {
+			case tt.wantCode != 0:
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantCode {
+					t.Errorf("Open = %v, want status %d", err, tt.wantCode)
+				}
+			case err != nil || got != tt.want:
+				t.Errorf("Open = %q, %v, want %q", got, err, tt.want)
+			}
+			if n := attempts.Load(); n != tt.attempts {
+				t.Errorf("%d attempts, want %d", n, tt.attempts)
+			}
+		})
+	}
+}
+
+// TestOpenStatusErrorRedacted checks that the password of a URL is not
+// part of the error.
+func TestOpenStatusErrorRedacted(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	url := strings.Replace(srv.URL, "http://", "http://user:hunter2@", 1) + "/doc.json"
+	_, err := read(url, httpio.Options{})
+	if err == nil || strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Open = %v, want a 404 error without the password", err)
+	}
+}
+
+// TestOpenTimeout checks that an attempt exceeding the timeout, while
+// waiting for the response or reading its body, fails and is retried.
+func TestOpenTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	got, err := read(srv.URL, httpio.Options{Timeout: 50 * time.Millisecond, Retries: 1, Backoff: time.Millisecond})
+	if err != nil || got != "ok" {
+		t.Errorf("Open = %q, %v, want the second attempt", got, err)
+	}
+
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "partial")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer stalled.Close()
+	if _, err := read(stalled.URL, httpio.Options{Timeout: 50 * time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("reading a stalled body = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestOpenCancelled checks that a cancelled context stops retrying.
+func TestOpenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	_, err := httpio.Open(ctx, srv.URL, httpio.Options{Retries: 5, Backoff: time.Hour})
+	// The cancellation may cut the response short or follow it
+	if !errors.Is(err, context.Canceled) && !errors.As(err, new(*httpio.StatusError)) {
+		t.Errorf("Open = %v, want the context or status error", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("Open waited out the backoff after the context was cancelled")
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("%d attempts, want 1", n)
+	}
+}
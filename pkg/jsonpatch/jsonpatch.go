@@ -0,0 +1,145 @@
+// Package jsonpatch computes the differences between two decoded JSON
+// documents as a JSON Patch (RFC 6902), so changes can be applied
+// incrementally downstream.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// Operation is one operation of a JSON Patch: add, remove or replace the
+// value at the JSON Pointer Path.
+type Operation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// MarshalJSON encodes the operation as a JSON Patch object, with a value
+// unless it is a removal, so null values are kept.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return codec.Default().Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return codec.Default().Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// Diff returns the patch turning the decoded JSON value a into b. Objects
+// are compared key by key, in sorted order, and arrays index by index, with
+// elements beyond the shorter array added or removed at its end; any other
+// change replaces the value. Equal values give an empty, non-nil patch, so
+// it encodes as [] rather than null.
+func Diff(a, b interface{}) []Operation {
+	ops := []Operation{}
+	diff("", a, b, &ops)
+	return ops
+}
+
+// diff appends the operations turning a into b, found at the JSON Pointer
+// path, to ops.
+func diff(path string, a, b interface{}, ops *[]Operation) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			for _, key := range sortedKeys(av) {
+				keyPath := path + "/" + escaper.Replace(key)
+				if other, ok := bv[key]; ok {
+					diff(keyPath, av[key], other, ops)
+				} else {
+					*ops = append(*ops, Operation{Op: "remove", Path: keyPath})
+				}
+			}
+			for _, key := range sortedKeys(bv) {
+				if _, ok := av[key]; !ok {
+					*ops = append(*ops, Operation{Op: "add", Path: path + "/" + escaper.Replace(key), Value: bv[key]})
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) && i < len(bv); i++ {
+				diff(path+"/"+strconv.Itoa(i), av[i], bv[i], ops)
+			}
+			// Remove from the end, so the indices of the others stay valid
+			for i := len(av) - 1; i >= len(bv); i-- {
+				*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+			}
+			for i := len(av); i < len(bv); i++ {
+				*ops = append(*ops, Operation{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: bv[i]})
+			}
+			return
+		}
+	}
+	if !equal(a, b) {
+		*ops = append(*ops, Operation{Op: "replace", Path: path, Value: b})
+	}
+}
+
+// equal reports whether a and b are the same JSON value, comparing numbers
+// by value whether they are json.Number or Go numbers.
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	an, aok := number(a)
+	bn, bok := number(b)
+	return aok && bok && an == bn
+}
+
+// number returns the numeric value v as text comparing equal for equal
+// numbers, integers exactly, reporting whether v is a number.
+func number(v interface{}) (string, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return strconv.FormatInt(i, 10), true
+		}
+		if !strings.ContainsAny(n.String(), ".eE") {
+			return n.String(), true
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return n.String(), true
+		}
+		return number(f)
+	case float64:
+		if n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			return strconv.FormatInt(int64(n), 10), true
+		}
+		return strconv.FormatFloat(n, 'g', -1, 64), true
+	case int64:
+		return strconv.FormatInt(n, 10), true
+	case int:
+		return strconv.Itoa(n), true
+	default:
+		return "", false
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escaper escapes a key for use as a JSON Pointer reference token.
+var escaper = strings.NewReplacer("~", "~0", "/", "~1")
@@ -0,0 +1,171 @@
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/jsonpatch"
+)
+
+// decode returns the JSON value in s, with numbers as json.Number.
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+// TestDiff compares the patches between pairs of documents with the
+// expected patches, and checks that applying them turns the first document
+// into the second.
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "equal documents",
+			a:    `{"a":1,"b":[1,2]}`,
+			b:    `{"b":[1,2],"a":1}`,
+			want: `[]`,
+		},
+		{
+			name: "keys added, removed and replaced in sorted order",
+			a:    `{"z":1,"b":"x","c":true}`,
+			b:    `{"a":null,"b":"y","c":true,"y":{"k":1}}`,
+			want: `[{"op":"replace","path":"/b","value":"y"},{"op":"remove","path":"/z"},{"op":"add","path":"/a","value":null},{"op":"add","path":"/y","value":{"k":1}}]`,
+		},
+		{
+			name: "nested objects",
+			a:    `{"user":{"name":"a","address":{"city":"Paris"}}}`,
+			b:    `{"user":{"name":"a","address":{"city":"Lyon","zip":"69001"}}}`,
+			want: `[{"op":"replace","path":"/user/address/city","value":"Lyon"},{"op":"add","path":"/user/address/zip","value":"69001"}]`,
+		},
+		{
+			name: "arrays shrinking from the end",
+			a:    `{"l":[1,2,3,4]}`,
+			b:    `{"l":[1,5]}`,
+			want: `[{"op":"replace","path":"/l/1","value":5},{"op":"remove","path":"/l/3"},{"op":"remove","path":"/l/2"}]`,
+		},
+		{
+			name: "arrays growing",
+			a:    `{"l":[]}`,
+			b:    `{"l":[{"a":1},"x"]}`,
+			want: `[{"op":"add","path":"/l/0","value":{"a":1}},{"op":"add","path":"/l/1","value":"x"}]`,
+		},
+		{
+			name: "keys escaped",
+			a:    `{"a/b":1,"c~d":1}`,
+			b:    `{"a/b":2}`,
+			want: `[{"op":"replace","path":"/a~1b","value":2},{"op":"remove","path":"/c~0d"}]`,
+		},
+		{
+			name: "types changed",
+			a:    `{"a":{"x":1},"b":[1],"c":"1"}`,
+			b:    `{"a":[1],"b":{"x":1},"c":1}`,
+			want: `[{"op":"replace","path":"/a","value":[1]},{"op":"replace","path":"/b","value":{"x":1}},{"op":"replace","path":"/c","value":1}]`,
+		},
+		{
+			name: "numbers compared by value",
+			a:    `{"a":1,"b":1.50,"c":1e2,"d":12345678901234567890}`,
+			b:    `{"a":1.0,"b":1.5,"c":100,"d":12345678901234567891}`,
+			want: `[{"op":"replace","path":"/d","value":12345678901234567891}]`,
+		},
+		{
+			name: "whole document replaced",
+			a:    `{"a":1}`,
+			b:    `[1]`,
+			want: `[{"op":"replace","path":"","value":[1]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := decode(t, tt.a), decode(t, tt.b)
+			patch := jsonpatch.Diff(a, b)
+			got, err := json.Marshal(patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Diff =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			applied := apply(t, decode(t, tt.a), patch)
+			if again := jsonpatch.Diff(applied, b); len(again) != 0 {
+				t.Errorf("applying the patch to %s gives a document differing from %s by %v", tt.a, tt.b, again)
+			}
+		})
+	}
+}
+
+// TestDiffFloat64 checks that documents decoded without UseNumber compare
+// numbers the same way.
+func TestDiffFloat64(t *testing.T) {
+	a := map[string]interface{}{"a": float64(2), "b": json.Number("2")}
+	b := map[string]interface{}{"a": json.Number("2.0"), "b": int64(2)}
+	if patch := jsonpatch.Diff(a, b); !reflect.DeepEqual(patch, []jsonpatch.Operation{}) {
+		t.Errorf("Diff = %v, want no operations", patch)
+	}
+}
+
+// apply returns doc with the operations of patch applied.
+func apply(t *testing.T, doc interface{}, patch []jsonpatch.Operation) interface{} {
+	t.Helper()
+	for _, op := range patch {
+		if op.Path == "" {
+			doc = op.Value
+			continue
+		}
+		tokens := strings.Split(op.Path[1:], "/")
+		for i, token := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		}
+		doc = applyAt(t, doc, tokens, op)
+	}
+	return doc
+}
+
+// applyAt returns the value v with op applied at the reference tokens
+// leading to its target from v.
+func applyAt(t *testing.T, v interface{}, tokens []string, op jsonpatch.Operation) interface{} {
+	t.Helper()
+	token := tokens[0]
+	switch container := v.(type) {
+	case map[string]interface{}:
+		if len(tokens) > 1 {
+			container[token] = applyAt(t, container[token], tokens[1:], op)
+		} else if op.Op == "remove" {
+			delete(container, token)
+		} else {
+			container[token] = op.Value
+		}
+		return container
+	case []interface{}:
+		i, err := strconv.Atoi(token)
+		if err != nil || i > len(container) {
+			t.Fatalf("%s: index %q out of range", op.Path, token)
+		}
+		switch {
+		case len(tokens) > 1:
+			container[i] = applyAt(t, container[i], tokens[1:], op)
+		case op.Op == "remove":
+			container = append(container[:i], container[i+1:]...)
+		case op.Op == "add":
+			container = append(container[:i], append([]interface{}{op.Value}, container[i:]...)...)
+		default:
+			container[i] = op.Value
+		}
+		return container
+	default:
+		t.Fatalf("%s: no container at %q", op.Path, token)
+		return nil
+	}
+}
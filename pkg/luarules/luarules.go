@@ -0,0 +1,219 @@
+// Package luarules loads transformation rules written in Lua, for teams that
+// cannot ship a custom Go build. A script defines functions in two global
+// tables:
+//
+//	-- rules replace the rule for a type tag and receive the raw value
+//	rules = {}
+//	function rules.UUID(value) return string.lower(value) end
+//
+//	-- paths override the transformation of the value at a dotted key path
+//	-- and receive the raw value and its type tag
+//	paths = {}
+//	paths["user.email"] = function(value, type) return string.lower(value) end
+//
+// Lua tables become JSON objects, or arrays when their keys are 1..n.
+// Raising an error with error() omits the value from the output.
+package luarules
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Script is a loaded Lua rules script. Lua states are single-threaded, so
+// calls into the script are serialized.
+type Script struct {
+	mu    sync.Mutex
+	state *lua.LState
+	rules map[string]*lua.LFunction
+	paths map[string]*lua.LFunction
+}
+
+// Load runs the Lua script at path and collects the functions in its rules
+// and paths tables.
+func Load(path string) (*Script, error) {
+	state := lua.NewState()
+	if err := state.DoFile(path); err != nil {
+		state.Close()
+		return nil, err
+	}
+
+	s := &Script{state: state}
+	var err error
+	if s.rules, err = functions(state, "rules"); err != nil {
+		state.Close()
+		return nil, err
+	}
+	if s.paths, err = functions(state, "paths"); err != nil {
+		state.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// functions returns the functions in the global table name, keyed by their
+// string keys. A missing table yields no functions.
+func functions(state *lua.LState, name string) (map[string]*lua.LFunction, error) {
+	fns := make(map[string]*lua.LFunction)
+	global := state.GetGlobal(name)
+	if global == lua.LNil {
+		return fns, nil
+	}
+	table, ok := global.(*lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a table, not %s", name, global.Type())
+	}
+
+	var err error
+	table.ForEach(func(key, value lua.LValue) {
+		fn, ok := value.(*lua.LFunction)
+		if !ok && err == nil {
+			err = fmt.Errorf("%s[%q] must be a function, not %s", name, key.String(), value.Type())
+		}
+		fns[key.String()] = fn
+	})
+	return fns, err
+}
+
+// Close releases the Lua state.
+func (s *Script) Close() {
+	s.state.Close()
+}
+
+// Options returns the options registering the script's type rules and its
+// path overrides, which run as middleware.
+func (s *Script) Options() []transform.Option {
+	opts := make([]transform.Option, 0, len(s.rules)+1)
+	for typeKey, fn := range s.rules {
+		fn := fn
+		opts = append(opts, transform.WithRule(typeKey, func(v interface{}) interface{} {
+			result, err := s.call(fn, v)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", typeKey, err)
+			}
+			return result
+		}))
+	}
+
+	if len(s.paths) > 0 {
+		opts = append(opts, transform.WithMiddleware(s.pathMiddleware))
+	}
+	return opts
+}
+
+// pathMiddleware calls the path function matching an attribute's key path
+// in place of its rule.
+func (s *Script) pathMiddleware(next transform.TransformFunc) transform.TransformFunc {
+	return func(a transform.Attribute) (interface{}, bool) {
+		fn, ok := s.paths[strings.Join(a.Path, ".")]
+		if !ok {
+			return next(a)
+		}
+		result, err := s.call(fn, a.Value, a.Type)
+		if err != nil {
+			return nil, false
+		}
+		return result, true
+	}
+}
+
+// call invokes fn with args converted to Lua and converts its single result
+// back into a plain Go value.
+func (s *Script) call(fn *lua.LFunction, args ...interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	largs := make([]lua.LValue, len(args))
+	for i, arg := range args {
+		largs[i] = toLua(s.state, arg)
+	}
+	if err := s.state.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, largs...); err != nil {
+		return nil, err
+	}
+	result := s.state.Get(-1)
+	s.state.Pop(1)
+	return fromLua(result), nil
+}
+
+// toLua converts a decoded JSON value into a Lua value.
+func toLua(state *lua.LState, v interface{}) lua.LValue {
+	switch val := v.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(val)
+	case string:
+		return lua.LString(val)
+	case float64:
+		return lua.LNumber(val)
+	case int64:
+		return lua.LNumber(val)
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return lua.LString(val.String())
+		}
+		return lua.LNumber(f)
+	case []interface{}:
+		table := state.NewTable()
+		for _, elem := range val {
+			table.Append(toLua(state, elem))
+		}
+		return table
+	case map[string]interface{}:
+		table := state.NewTable()
+		for k, elem := range val {
+			table.RawSetString(k, toLua(state, elem))
+		}
+		return table
+	default:
+		return lua.LString(fmt.Sprint(val))
+	}
+}
+
+// fromLua converts a Lua value into a plain Go value. Integral numbers become
+// int64 so they are written without a fractional part.
+func fromLua(v lua.LValue) interface{} {
+	switch val := v.(type) {
+	case lua.LBool:
+		return bool(val)
+	case lua.LString:
+		return string(val)
+	case lua.LNumber:
+		if f := float64(val); f == float64(int64(f)) {
+			return int64(f)
+		}
+		return float64(val)
+	case *lua.LTable:
+		// Sequences become arrays, anything else an object
+		if n := val.Len(); n > 0 && countKeys(val) == n {
+			out := make([]interface{}, 0, n)
+			for i := 1; i <= n; i++ {
+				out = append(out, fromLua(val.RawGetInt(i)))
+			}
+			return out
+		}
+		out := make(map[string]interface{})
+		val.ForEach(func(key, value lua.LValue) {
+			out[key.String()] = fromLua(value)
+		})
+		return out
+	default:
+		return nil
+	}
+}
+
+// countKeys returns the number of keys in table.
+func countKeys(table *lua.LTable) int {
+	n := 0
+	table.ForEach(func(lua.LValue, lua.LValue) {
+		n++
+	})
+	return n
+}
@@ -0,0 +1,143 @@
+package luarules_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/luarules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// load writes the Lua script src to a temporary file and loads it.
+func load(t *testing.T, src string) (*luarules.Script, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.lua")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script, err := luarules.Load(path)
+	if err == nil {
+		t.Cleanup(script.Close)
+	}
+	return script, err
+}
+
+// decode returns the JSON object in s.
+func decode(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// TestLoadErrors checks that scripts that fail to run or define rules of the
+// wrong type are rejected when loaded.
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "syntax error", src: "rules = {", want: "rules.lua"},
+		{name: "runtime error", src: `error("boom")`, want: "boom"},
+		{name: "rules not a table", src: "rules = 5", want: "rules must be a table, not number"},
+		{name: "path not a function", src: `paths = {["a.b"] = "x"}`, want: `paths["a.b"] must be a function, not string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// script is the rules script the transformation tests load.
+const script = `
+rules = {}
+function rules.UUID(value) return string.lower(value) end
+function rules.SPLIT(value)
+  local out = {}
+  for part in string.gmatch(value, "[^,]+") do table.insert(out, part) end
+  return out
+end
+function rules.PAIR(value) return {key = value, size = #value} end
+function rules.HALF(value) return tonumber(value) / 2 end
+function rules.BAD(value) error("bad value " .. value) end
+
+paths = {}
+paths["user.email"] = function(value, type) return type .. ":" .. string.lower(value) end
+paths["user.secret"] = function(value, type) error("redacted") end
+`
+
+// TestRules checks the values rules and path functions produce, and that
+// raised errors make values invalid.
+func TestRules(t *testing.T) {
+	s, err := load(t, script)
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	input := decode(t, `{
+		"id": {"UUID": "AB-CD"},
+		"tags": {"SPLIT": "x,y"},
+		"pair": {"PAIR": "ab"},
+		"half": {"HALF": "3"},
+		"bad": {"BAD": "v"},
+		"user": {"M": {"email": {"S": "A@B.C"}, "secret": {"S": "s"}, "name": {"S": "Ann"}}}
+	}`)
+	want := map[string]interface{}{
+		"id":   "ab-cd",
+		"tags": []interface{}{"x", "y"},
+		"pair": map[string]interface{}{"key": "ab", "size": int64(2)},
+		"half": 1.5,
+		"user": map[string]interface{}{"email": "S:a@b.c", "name": "Ann"},
+	}
+	if got := transform.New(s.Options()...).Transform(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform = %#v, want %#v", got, want)
+	}
+
+	opts := append(s.Options(), transform.WithOnError(transform.OnErrorFail))
+	_, err = transform.New(opts...).TransformChecked(input)
+	var pathErr *transform.PathError
+	if !errors.As(err, &pathErr) || pathErr.Pointer != "/bad" || !strings.Contains(err.Error(), "bad value v") {
+		t.Errorf("TransformChecked = %v, want the BAD rule failing at /bad", err)
+	}
+}
+
+// TestRulesContextCancelled checks that a stream transformed with the rules
+// stops once its context is cancelled.
+func TestRulesContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := load(t, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cancel while the first record is being transformed
+	opts := append(s.Options(), transform.WithMiddleware(func(next transform.TransformFunc) transform.TransformFunc {
+		return func(a transform.Attribute) (interface{}, bool) {
+			cancel()
+			return next(a)
+		}
+	}))
+
+	in := strings.Repeat(`{"id":{"UUID":"AB"}}`+"\n", 3)
+	var out bytes.Buffer
+	err = transform.New(opts...).TransformNDJSONContext(ctx, strings.NewReader(in), &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("TransformNDJSONContext = %v, want context.Canceled", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("wrote %q, want nothing", out.String())
+	}
+}
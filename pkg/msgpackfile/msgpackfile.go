@@ -0,0 +1,71 @@
+// Package msgpackfile writes transformed documents as MessagePack, a compact
+// binary encoding of the JSON data model, for services receiving results
+// over the network.
+package msgpackfile
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Writer writes documents as a stream of MessagePack maps, one after
+// another, which MessagePack decoders read one value at a time.
+type Writer struct {
+	enc *msgpack.Encoder
+}
+
+// NewWriter returns a Writer writing to w. Map keys are written sorted, so
+// the same document always encodes to the same bytes, and numbers in the
+// smallest encoding holding them exactly.
+func NewWriter(w io.Writer) *Writer {
+	enc := msgpack.NewEncoder(w)
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	enc.UseCompactFloats(true)
+	return &Writer{enc: enc}
+}
+
+// Write writes the decoded JSON document doc as a MessagePack map. Integers
+// are written as MessagePack integers and other numbers as floats.
+func (w *Writer) Write(doc map[string]interface{}) error {
+	return w.enc.Encode(convert(doc))
+}
+
+// Close does nothing, as every document is written at once. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	return nil
+}
+
+// convert returns the decoded JSON value v with its numbers in the form
+// they are encoded in.
+func convert(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[key] = convert(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = convert(elem)
+		}
+		return out
+	default:
+		return v
+	}
+}
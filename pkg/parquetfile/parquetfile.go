@@ -0,0 +1,243 @@
+// Package parquetfile writes transformed documents as rows of a Parquet file,
+// with columns derived from the JSON Schema schemagen infers for the
+// documents, so exports can be loaded straight into analytics storage.
+package parquetfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/compress/gzip"
+	"github.com/parquet-go/parquet-go/compress/lz4"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"github.com/parquet-go/parquet-go/compress/uncompressed"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// codecs maps the compression names accepted by ParseCompression to their
+// codecs.
+var codecs = map[string]compress.Codec{
+	"none":   &uncompressed.Codec{},
+	"snappy": &snappy.Codec{},
+	"gzip":   &gzip.Codec{},
+	"zstd":   &zstd.Codec{},
+	"lz4":    &lz4.Codec{},
+}
+
+// CompressionNames lists the names accepted by ParseCompression.
+func CompressionNames() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseCompression returns the page compression codec called name: none,
+// snappy, gzip, zstd or lz4.
+func ParseCompression(name string) (compress.Codec, error) {
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown Parquet compression %q, want %s", name, strings.Join(CompressionNames(), ", "))
+	}
+	return codec, nil
+}
+
+// Writer writes documents as the rows of a Parquet file.
+type Writer struct {
+	pw   *parquet.Writer
+	root column
+}
+
+// NewWriter returns a Writer writing rows to w with the columns of the
+// documents described by the JSON Schema schema, compressing pages with
+// codec. Objects with known properties become groups, arrays lists,
+// integers int64 and other numbers double columns. Values of several types,
+// objects without known properties and lists of nullable elements are
+// stored as JSON text. Properties that are not required, or may be null,
+// are optional.
+func NewWriter(w io.Writer, schema map[string]interface{}, codec compress.Codec) (*Writer, error) {
+	root := columnOf(schema)
+	if root.kind != groupColumn {
+		return nil, fmt.Errorf("schema does not describe an object with properties")
+	}
+	pw := parquet.NewWriter(w, parquet.NewSchema("Document", root.node), parquet.Compression(codec))
+	return &Writer{pw: pw, root: root}, nil
+}
+
+// Write adds the decoded JSON document doc as a row.
+func (w *Writer) Write(doc map[string]interface{}) error {
+	row, err := w.root.convert(doc, "")
+	if err != nil {
+		return err
+	}
+	return w.pw.Write(row)
+}
+
+// Close writes the buffered rows and the footer of the file. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	return w.pw.Close()
+}
+
+// columnKind is how a column stores its values.
+type columnKind int
+
+const (
+	int64Column columnKind = iota
+	doubleColumn
+	booleanColumn
+	stringColumn
+	jsonColumn
+	groupColumn
+	listColumn
+)
+
+// column is the Parquet node of the values described by a JSON Schema, along
+// with how to convert the values for it.
+type column struct {
+	kind columnKind
+	node parquet.Node
+
+	// fields are the columns of a group and elem the column of the elements
+	// of a list
+	fields map[string]column
+	elem   *column
+}
+
+// columnOf returns the column of values described by schema, which the
+// enclosing group makes optional when they may be missing or null.
+func columnOf(schema map[string]interface{}) column {
+	var types []string
+	for _, t := range schemautil.StringList(schema["type"]) {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		return column{kind: jsonColumn, node: parquet.JSON()}
+	}
+
+	switch types[0] {
+	case "object":
+		props, _ := schema["properties"].(map[string]interface{})
+		if len(props) == 0 {
+			return column{kind: jsonColumn, node: parquet.JSON()}
+		}
+		required := make(map[string]bool)
+		for _, key := range schemautil.StringList(schema["required"]) {
+			required[key] = true
+		}
+		group := parquet.Group{}
+		fields := make(map[string]column, len(props))
+		for key, p := range props {
+			prop, _ := p.(map[string]interface{})
+			field := columnOf(prop)
+			node := field.node
+			if !required[key] || schemautil.Nullable(prop) {
+				node = parquet.Optional(node)
+			}
+			group[key] = node
+			fields[key] = field
+		}
+		return column{kind: groupColumn, node: group, fields: fields}
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		elem := columnOf(items)
+		if schemautil.Nullable(items) || len(items) == 0 {
+			// Elements that may be null are kept as JSON text
+			elem = column{kind: jsonColumn, node: parquet.JSON()}
+		}
+		return column{kind: listColumn, node: parquet.List(elem.node), elem: &elem}
+	case "integer":
+		return column{kind: int64Column, node: parquet.Int(64)}
+	case "number":
+		return column{kind: doubleColumn, node: parquet.Leaf(parquet.DoubleType)}
+	case "boolean":
+		return column{kind: booleanColumn, node: parquet.Leaf(parquet.BooleanType)}
+	case "string":
+		return column{kind: stringColumn, node: parquet.String()}
+	default:
+		return column{kind: jsonColumn, node: parquet.JSON()}
+	}
+}
+
+// convert returns v, found at the JSON Pointer path, in the form the Parquet
+// writer expects for c. Null stays nil.
+func (c column) convert(v interface{}, path string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch c.kind {
+	case int64Column:
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+		}
+	case doubleColumn:
+		if n, ok := v.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case booleanColumn:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case stringColumn:
+		if str, ok := v.(string); ok {
+			return str, nil
+		}
+	case jsonColumn:
+		out, err := codec.Default().Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", schemautil.DescribePointer(path), err)
+		}
+		return string(out), nil
+	case groupColumn:
+		doc, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		row := make(map[string]interface{}, len(doc))
+		for key, field := range c.fields {
+			value, err := field.convert(doc[key], schemautil.JoinPointer(path, key))
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				row[key] = value
+			}
+		}
+		return row, nil
+	case listColumn:
+		list, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		out := make([]interface{}, len(list))
+		for i, elem := range list {
+			if elem == nil {
+				// List elements are required, so null is kept as JSON text
+				out[i] = "null"
+				continue
+			}
+			var err error
+			if out[i], err = c.elem.convert(elem, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%s: value %v does not match its inferred Parquet column", schemautil.DescribePointer(path), v)
+}
@@ -0,0 +1,211 @@
+package parquetfile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/parquetfile"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+)
+
+// decode returns the JSON documents docs decoded as the CLI decodes
+// transformed output, with numbers as json.Number.
+func decode(t *testing.T, docs []string) []map[string]interface{} {
+	t.Helper()
+	out := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		if err := dec.Decode(&out[i]); err != nil {
+			t.Fatalf("decode %s: %v", doc, err)
+		}
+	}
+	return out
+}
+
+// infer returns the JSON Schema schemagen infers for docs.
+func infer(docs []map[string]interface{}) map[string]interface{} {
+	inferrer := schemagen.New()
+	for _, doc := range docs {
+		inferrer.Add(doc)
+	}
+	return inferrer.Schema()
+}
+
+// readAll returns the schema and rows of the Parquet file in data.
+func readAll(t *testing.T, data []byte) (string, []map[string]interface{}) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenFile = %v", err)
+	}
+	r := parquet.NewReader(f)
+	var rows []map[string]interface{}
+	for {
+		row := make(map[string]interface{})
+		err := r.Read(&row)
+		if errors.Is(err, io.EOF) {
+			return f.Schema().String(), rows
+		}
+		if err != nil {
+			t.Fatalf("Read = %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// write returns the Parquet file of docs with the columns of schema and
+// the compression called compression.
+func write(t *testing.T, schema map[string]interface{}, compression string, docs []map[string]interface{}) []byte {
+	t.Helper()
+	codec, err := parquetfile.ParseCompression(compression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w, err := parquetfile.NewWriter(&buf, schema, codec)
+	if err != nil {
+		t.Fatalf("NewWriter = %v", err)
+	}
+	for _, doc := range docs {
+		if err := w.Write(doc); err != nil {
+			t.Fatalf("Write(%v) = %v", doc, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestWriter checks the columns and rows written for documents with the
+// schema inferred from them.
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		docs       []string
+		wantSchema string
+		want       []map[string]interface{}
+	}{
+		{
+			name: "column union across records",
+			docs: []string{`{"id":1,"name":"a"}`, `{"id":2,"email":"b@c"}`},
+			wantSchema: `message Document {
+	optional binary email (STRING);
+	required int64 id (INT(64,true));
+	optional binary name (STRING);
+}`,
+			want: []map[string]interface{}{
+				{"email": nil, "id": int64(1), "name": "a"},
+				{"email": "b@c", "id": int64(2), "name": nil},
+			},
+		},
+		{
+			name: "nested objects as groups",
+			docs: []string{`{"address":{"city":"Paris","geo":{"lat":1.5}},"tags":["x","y"]}`},
+			wantSchema: `message Document {
+	required group address {
+		required binary city (STRING);
+		required group geo {
+			required double lat;
+		}
+	}
+	required group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+}`,
+			want: []map[string]interface{}{
+				{"address": map[string]interface{}{"city": "Paris", "geo": map[string]interface{}{"lat": 1.5}}, "tags": []interface{}{"x", "y"}},
+			},
+		},
+		{
+			name: "64-bit integers kept exact",
+			docs: []string{`{"id":9007199254740993}`, `{"id":-9223372036854775808}`},
+			wantSchema: `message Document {
+	required int64 id (INT(64,true));
+}`,
+			want: []map[string]interface{}{
+				{"id": int64(9007199254740993)},
+				{"id": int64(-9223372036854775808)},
+			},
+		},
+		{
+			name: "values of several types as JSON, which the reader decodes",
+			docs: []string{`{"v":{"a":1}}`, `{"v":"s"}`},
+			wantSchema: `message Document {
+	required binary v (JSON);
+}`,
+			want: []map[string]interface{}{
+				{"v": map[string]interface{}{"a": float64(1)}},
+				{"v": "s"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := decode(t, tt.docs)
+			schema, rows := readAll(t, write(t, infer(docs), "snappy", docs))
+			if schema != tt.wantSchema {
+				t.Errorf("schema\n%s\nwant\n%s", schema, tt.wantSchema)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows %#v, want %#v", rows, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriterIntegerOverflow checks that an integer beyond 64 bits is
+// rejected for an int64 column rather than rounded.
+func TestWriterIntegerOverflow(t *testing.T) {
+	codec, err := parquetfile.ParseCompression("none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := parquetfile.NewWriter(io.Discard, infer(decode(t, []string{`{"a":{"id":1}}`})), codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Write(decode(t, []string{`{"a":{"id":9223372036854775808}}`})[0])
+	if err == nil || !strings.HasPrefix(err.Error(), "/a/id: ") {
+		t.Errorf("Write = %v, want an error at /a/id", err)
+	}
+}
+
+// TestEmpty checks that a schema without properties is rejected and that a
+// writer given no documents writes a file without rows.
+func TestEmpty(t *testing.T) {
+	for _, docs := range [][]string{nil, {`{}`}} {
+		if _, err := parquetfile.NewWriter(io.Discard, infer(decode(t, docs)), nil); err == nil {
+			t.Errorf("NewWriter for documents %v succeeded, want an error", docs)
+		}
+	}
+
+	schema := infer(decode(t, []string{`{"id":1}`}))
+	if _, rows := readAll(t, write(t, schema, "none", nil)); len(rows) != 0 {
+		t.Errorf("wrote %v, want no rows", rows)
+	}
+}
+
+// TestCompression checks that files written with each compression read
+// back, and that unknown names are rejected.
+func TestCompression(t *testing.T) {
+	docs := decode(t, []string{`{"id":1,"name":"a"}`})
+	for _, name := range parquetfile.CompressionNames() {
+		if _, rows := readAll(t, write(t, infer(docs), name, docs)); len(rows) != 1 {
+			t.Errorf("%s: read %v, want one row", name, rows)
+		}
+	}
+	if _, err := parquetfile.ParseCompression("brotli"); err == nil {
+		t.Error("ParseCompression(brotli) succeeded, want an error")
+	}
+}
@@ -0,0 +1,322 @@
+// Package protogen generates a proto3 file from a JSON Schema describing
+// plain JSON documents, such as the one schemagen infers from transformed
+// output, modelling the documents as messages for gRPC pipelines.
+package protogen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// Well-known types used for values that have no scalar or message of their
+// own.
+const (
+	timestampType = "google.protobuf.Timestamp"
+	structType    = "google.protobuf.Struct"
+	valueType     = "google.protobuf.Value"
+	listType      = "google.protobuf.ListValue"
+)
+
+// wellKnownImports maps the well-known types to the files declaring them.
+var wellKnownImports = map[string]string{
+	timestampType: "google/protobuf/timestamp.proto",
+	structType:    "google/protobuf/struct.proto",
+	valueType:     "google/protobuf/struct.proto",
+	listType:      "google/protobuf/struct.proto",
+}
+
+// Options are the file-level settings of the generated file.
+type Options struct {
+	// Package is the proto package, such as "exports.v1".
+	Package string
+
+	// GoPackage sets the go_package option when not empty.
+	GoPackage string
+}
+
+// Generate returns a proto3 file declaring the message typeName for
+// documents described by schema, along with a message for every nested
+// object with known properties. Fields are numbered in the order of their
+// sorted JSON keys. Integers map to int64, other numbers to double, arrays
+// to repeated fields, strings in the date-time format to
+// google.protobuf.Timestamp, and objects without known properties, values of
+// several types and nested arrays to the google.protobuf.Struct family.
+// Optional and nullable scalars are declared optional to keep their
+// presence.
+func Generate(schema map[string]interface{}, typeName string, opts Options) ([]byte, error) {
+	if !packageName.MatchString(opts.Package) {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+	if !identifier.MatchString(typeName) {
+		return nil, fmt.Errorf("invalid message name %q", typeName)
+	}
+
+	g := &generator{names: make(map[string]bool), imports: make(map[string]bool)}
+	if _, ok := g.object(schema, typeName, "is a transformed document"); !ok {
+		return nil, fmt.Errorf("schema does not describe an object with properties")
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen proto; DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", opts.Package)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for file := range g.imports {
+			imports = append(imports, file)
+		}
+		sort.Strings(imports)
+		b.WriteString("\n")
+		for _, file := range imports {
+			fmt.Fprintf(&b, "import %q;\n", file)
+		}
+	}
+	if opts.GoPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", opts.GoPackage)
+	}
+	for _, m := range g.messages {
+		fmt.Fprintf(&b, "\n// %s %s.\n", m.name, m.doc)
+		fmt.Fprintf(&b, "message %s {\n", m.name)
+		for i, f := range m.fields {
+			label := ""
+			if f.label != "" {
+				label = f.label + " "
+			}
+			option := ""
+			if f.jsonName != "" {
+				option = fmt.Sprintf(" [json_name = %q]", f.jsonName)
+			}
+			fmt.Fprintf(&b, "  %s%s %s = %d%s;\n", label, f.protoType, f.name, i+1, option)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes(), nil
+}
+
+var (
+	// identifier matches the names of messages and fields.
+	identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// packageName matches dotted proto package names.
+	packageName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+)
+
+// generator collects the messages declared for a schema and the files
+// declaring the well-known types they use.
+type generator struct {
+	messages []*message
+	names    map[string]bool
+	imports  map[string]bool
+}
+
+// message is a message declaration.
+type message struct {
+	name   string
+	doc    string
+	fields []field
+}
+
+// field is a field of a message declaration. label is "repeated",
+// "optional" or empty, and jsonName is set when the JSON key is not the
+// default JSON name of the field.
+type field struct {
+	label, protoType, name, jsonName string
+}
+
+// object declares a message for the object schema, named after hint and
+// documented as doc, and returns its name. It reports false when schema has
+// no properties, which leaves nothing to declare.
+func (g *generator) object(schema map[string]interface{}, hint, doc string) (string, bool) {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return "", false
+	}
+	required := make(map[string]bool)
+	for _, key := range schemautil.StringList(schema["required"]) {
+		required[key] = true
+	}
+
+	m := &message{name: schemautil.Unique(hint, g.names), doc: doc}
+	g.messages = append(g.messages, m)
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fieldNames := make(map[string]bool)
+	for _, key := range keys {
+		prop, _ := props[key].(map[string]interface{})
+		name := schemautil.Unique(fieldName(key), fieldNames)
+		protoType, label := g.fieldType(prop, messageName(key), fmt.Sprintf("is the value of %q", key))
+		if label == "" && scalar(protoType) && (!required[key] || schemautil.Nullable(prop)) {
+			label = "optional"
+		}
+		f := field{label: label, protoType: protoType, name: name}
+		if jsonName(name) != key {
+			f.jsonName = key
+		}
+		m.fields = append(m.fields, f)
+	}
+	return m.name, true
+}
+
+// fieldType returns the type of a field holding values described by schema,
+// along with "repeated" for arrays. Messages declared for the values are
+// named after hint and documented as doc.
+func (g *generator) fieldType(schema map[string]interface{}, hint, doc string) (string, string) {
+	types := nonNull(schema)
+	if len(types) == 1 && types[0] == "array" {
+		items, _ := schema["items"].(map[string]interface{})
+		elem := g.valueType(items, schemautil.Singular(hint), strings.Replace(doc, "is the value of", "is an element of", 1))
+		return elem, "repeated"
+	}
+	return g.valueType(schema, hint, doc), ""
+}
+
+// valueType returns the type of a single value described by schema. Arrays
+// here are elements of arrays, which repeated fields cannot hold.
+func (g *generator) valueType(schema map[string]interface{}, hint, doc string) string {
+	types := nonNull(schema)
+	if len(types) != 1 {
+		return g.wellKnown(valueType)
+	}
+
+	switch types[0] {
+	case "object":
+		if name, ok := g.object(schema, hint, doc); ok {
+			return name
+		}
+		return g.wellKnown(structType)
+	case "array":
+		return g.wellKnown(listType)
+	case "string":
+		if schema["format"] == "date-time" {
+			return g.wellKnown(timestampType)
+		}
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "double"
+	case "boolean":
+		return "bool"
+	default:
+		return g.wellKnown(valueType)
+	}
+}
+
+// wellKnown returns the well-known type name and records its import.
+func (g *generator) wellKnown(name string) string {
+	g.imports[wellKnownImports[name]] = true
+	return name
+}
+
+// scalar reports whether protoType is a scalar type, whose presence is only
+// tracked when declared optional.
+func scalar(protoType string) bool {
+	switch protoType {
+	case "string", "int64", "double", "bool":
+		return true
+	default:
+		return false
+	}
+}
+
+// nonNull returns the types schema allows other than null.
+func nonNull(schema map[string]interface{}) []string {
+	var types []string
+	for _, t := range schemautil.StringList(schema["type"]) {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// messageName returns a message name for values found at the JSON key, such
+// as UserAddress for user_address.
+func messageName(key string) string {
+	var b strings.Builder
+	for _, word := range words(key) {
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return leadingLetter(b.String(), "Value", "X")
+}
+
+// fieldName returns the lower snake case field name for the JSON key, such
+// as user_id for userId.
+func fieldName(key string) string {
+	list := words(key)
+	for i, word := range list {
+		list[i] = strings.ToLower(word)
+	}
+	return leadingLetter(strings.Join(list, "_"), "field", "x")
+}
+
+// leadingLetter returns name, or fallback when it is empty, prefixed so it
+// starts with an ASCII letter as proto identifiers must.
+func leadingLetter(name, fallback, prefix string) string {
+	if name == "" {
+		return fallback
+	}
+	if first := name[0]; !(first >= 'a' && first <= 'z' || first >= 'A' && first <= 'Z') {
+		return prefix + name
+	}
+	return name
+}
+
+// words splits key into ASCII words at other characters and where a
+// lower-case letter is followed by an upper-case one.
+func words(key string) []string {
+	var (
+		list []string
+		word []rune
+	)
+	for _, r := range key {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				list = append(list, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 && unicode.IsLower(word[len(word)-1]) {
+			list = append(list, string(word))
+			word = nil
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		list = append(list, string(word))
+	}
+	return list
+}
+
+// jsonName returns the default JSON name protoc derives from the field name,
+// which drops underscores and capitalizes the letter after each.
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package protogen_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/protogen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+)
+
+// update rewrites the golden files with the generated output.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// infer returns the schema schemagen infers for the JSON documents docs.
+func infer(t *testing.T, docs ...string) map[string]interface{} {
+	t.Helper()
+	inferrer := schemagen.New()
+	for _, doc := range docs {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+		inferrer.Add(v)
+	}
+	return inferrer.Schema()
+}
+
+// TestGenerate compares the files generated for inferred schemas with the
+// golden files in testdata.
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts protogen.Options
+		docs []string
+	}{
+		{
+			name: "field names and numbers",
+			opts: protogen.Options{Package: "exports.v1"},
+			docs: []string{`{"userId":1,"first_name":"a","html-body":"b","2fa":true,"zip":"z","a":1.5}`},
+		},
+		{
+			name: "optional and well-known types",
+			opts: protogen.Options{Package: "exports.v1", GoPackage: "example.com/exports/v1;exportsv1"},
+			docs: []string{
+				`{"id":1,"name":"a","tags":["x"],"meta":{},"note":null,"any":1,"grid":[[1]],"created_at":"2024-01-02T03:04:05Z"}`,
+				`{"id":2,"tags":[],"meta":{},"note":"n","any":"x","grid":[],"created_at":"2024-01-03T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "nested",
+			opts: protogen.Options{Package: "exports"},
+			docs: []string{
+				`{"home_address":{"city":"Paris","geo":{"lat":1.5}},"addresses":[{"city":"Lyon"}],"entries":[{"sku":"a"}]}`,
+				`{"home_address":{"city":"Nice","geo":{"lat":2}},"addresses":[]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := protogen.Generate(infer(t, tt.docs...), "Record", tt.opts)
+			if err != nil {
+				t.Fatalf("Generate = %v", err)
+			}
+			golden := filepath.Join("testdata", strings.ReplaceAll(tt.name, " ", "_")+".proto.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Generate =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestGenerateErrors checks the errors for invalid names and schemas
+// without properties.
+func TestGenerateErrors(t *testing.T) {
+	schema := infer(t, `{"id":1}`)
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		typ    string
+		pkg    string
+		want   string
+	}{
+		{name: "package", schema: schema, typ: "Record", pkg: "exports..v1", want: `invalid package name "exports..v1"`},
+		{name: "empty package", schema: schema, typ: "Record", pkg: "", want: `invalid package name ""`},
+		{name: "message", schema: schema, typ: "my-record", pkg: "exports", want: `invalid message name "my-record"`},
+		{name: "no properties", schema: infer(t, `{}`), typ: "Record", pkg: "exports", want: "schema does not describe an object with properties"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := protogen.Generate(tt.schema, tt.typ, protogen.Options{Package: tt.pkg})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Generate = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
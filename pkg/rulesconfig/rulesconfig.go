@@ -0,0 +1,238 @@
+// Package rulesconfig loads a YAML file declaring how the built-in rules
+// behave, such as which strings count as true for BOOL values or which date
+// layouts S values are converted from, for example
+//
+//	bool:
+//	  truthy: ["true", "yes", "1"]
+//	  falsy: ["false", "no", "0"]
+//	date_layouts: [RFC3339, "2006-01-02"]
+//	epoch_unit: milliseconds
+//	invalid_numbers: omit
+//	aliases:
+//	  UUID: S
+//	overrides:
+//	  S: value.trim()
+//	rename:
+//	  - from: user_id
+//	    to: userId
+//	  - pattern: "^(.*)_at$"
+//	    to: "${1}At"
+//	keys:
+//	  drop: ["^internal_"]
+//	defaults:
+//	  country: US
+//	required: [id, address.city]
+//
+// YAML reads a bare NULL key as null, so write it quoted as "NULL".
+package rulesconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/celrules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Config is the contents of a rules configuration file. Settings left out
+// keep the Transformer's defaults.
+type Config struct {
+	// Bool lists the strings BOOL values are recognised by.
+	Bool *BoolValues `yaml:"bool"`
+
+	// DateLayouts replaces the layouts S values are converted from; an empty
+	// list disables timestamp conversion.
+	DateLayouts []string `yaml:"date_layouts"`
+
+	// EpochUnit is the unit of converted timestamps, as accepted by
+	// transform.ParseEpochUnit.
+	EpochUnit string `yaml:"epoch_unit"`
+
+	// InvalidNumbers is "zero" to replace unparseable N values with 0, the
+	// default, or "omit" to drop them.
+	InvalidNumbers string `yaml:"invalid_numbers"`
+
+	// Aliases maps new type tags to the built-in tag whose rule they share.
+	Aliases map[string]string `yaml:"aliases"`
+
+	// Overrides maps type tags to CEL expressions replacing their rules.
+	Overrides map[string]string `yaml:"overrides"`
+
+	// Rename lists the output keys to rename, tried in order.
+	Rename []Rename `yaml:"rename"`
+
+	// Keys filters the attributes of every object by key.
+	Keys *KeyFilter `yaml:"keys"`
+
+	// Defaults maps dotted output field paths to the value they take when
+	// a transformed document lacks them.
+	Defaults map[string]interface{} `yaml:"defaults"`
+
+	// Required lists the dotted output field paths every transformed
+	// document must have.
+	Required []string `yaml:"required"`
+}
+
+// KeyFilter lists regular expressions matched against the keys of every
+// object. Attributes whose key matches a Drop pattern are dropped and, when
+// Keep is set, so are those whose key matches no Keep pattern.
+type KeyFilter struct {
+	Drop []string `yaml:"drop"`
+	Keep []string `yaml:"keep"`
+}
+
+// Rename renames output keys equal to From, or the parts of keys matching
+// the regular expression Pattern, to To. Exactly one of From and Pattern
+// must be set.
+type Rename struct {
+	From    string `yaml:"from"`
+	Pattern string `yaml:"pattern"`
+	To      string `yaml:"to"`
+}
+
+// BoolValues are the truthy and falsy strings of a BOOL configuration.
+type BoolValues struct {
+	Truthy []string `yaml:"truthy"`
+	Falsy  []string `yaml:"falsy"`
+}
+
+// Load reads the rules configuration in the YAML file at path. Unknown keys
+// are rejected so misspelled settings are not silently ignored.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Options validates c and returns the options applying it. Aliases are
+// resolved before overrides, so an alias always shares the built-in rule.
+func (c *Config) Options() ([]transform.Option, error) {
+	var opts []transform.Option
+	if c.Bool != nil {
+		if len(c.Bool.Truthy) == 0 {
+			return nil, fmt.Errorf("bool: truthy values are required")
+		}
+		opts = append(opts, transform.WithBoolValues(c.Bool.Truthy, c.Bool.Falsy))
+	}
+	if c.DateLayouts != nil {
+		opts = append(opts, transform.WithAcceptedDateLayouts(c.DateLayouts...))
+	}
+	if c.EpochUnit != "" {
+		unit, err := transform.ParseEpochUnit(c.EpochUnit)
+		if err != nil {
+			return nil, fmt.Errorf("epoch_unit: %w", err)
+		}
+		opts = append(opts, transform.WithEpochUnit(unit))
+	}
+	switch c.InvalidNumbers {
+	case "", "zero":
+	case "omit":
+		opts = append(opts, transform.WithOmitInvalidNumbers(true))
+	default:
+		return nil, fmt.Errorf("invalid_numbers: unknown mode %q, want zero or omit", c.InvalidNumbers)
+	}
+
+	for typeKey, target := range c.Aliases {
+		opts = append(opts, alias(typeKey, target))
+	}
+
+	overrides, err := celrules.Compile(c.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("overrides: %w", err)
+	}
+	for typeKey, rule := range overrides {
+		opts = append(opts, transform.WithRule(typeKey, rule))
+	}
+
+	if len(c.Rename) > 0 {
+		renames := make([]transform.KeyRename, 0, len(c.Rename))
+		for i, rn := range c.Rename {
+			r, err := rn.keyRename()
+			if err != nil {
+				return nil, fmt.Errorf("rename[%d]: %w", i, err)
+			}
+			renames = append(renames, r)
+		}
+		opts = append(opts, transform.WithKeyRenames(renames...))
+	}
+
+	if c.Keys != nil {
+		drop, err := compilePatterns(c.Keys.Drop)
+		if err != nil {
+			return nil, fmt.Errorf("keys: drop: %w", err)
+		}
+		keep, err := compilePatterns(c.Keys.Keep)
+		if err != nil {
+			return nil, fmt.Errorf("keys: keep: %w", err)
+		}
+		opts = append(opts, transform.WithDropKeys(drop...), transform.WithKeepKeys(keep...))
+	}
+
+	for field := range c.Defaults {
+		if _, err := transform.ParseFieldPath(field); err != nil {
+			return nil, fmt.Errorf("defaults: %w", err)
+		}
+	}
+	if len(c.Defaults) > 0 {
+		opts = append(opts, transform.WithDefaults(c.Defaults))
+	}
+
+	for _, field := range c.Required {
+		if _, err := transform.ParseFieldPath(field); err != nil {
+			return nil, fmt.Errorf("required: %w", err)
+		}
+	}
+	if len(c.Required) > 0 {
+		opts = append(opts, transform.WithRequired(c.Required...))
+	}
+	return opts, nil
+}
+
+// keyRename validates r and returns the transform.KeyRename it declares.
+func (r Rename) keyRename() (transform.KeyRename, error) {
+	switch {
+	case r.From != "" && r.Pattern != "":
+		return transform.KeyRename{}, fmt.Errorf("from and pattern are mutually exclusive")
+	case r.Pattern != "":
+		return transform.PatternRename(r.Pattern, r.To)
+	case r.From != "":
+		return transform.ExactRename(r.From, r.To), nil
+	default:
+		return transform.KeyRename{}, fmt.Errorf("from or pattern is required")
+	}
+}
+
+// compilePatterns compiles the regular expressions in patterns.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// alias returns an option registering the rule for target under typeKey.
+// Unknown targets are left unregistered, so typeKey stays an unknown type.
+func alias(typeKey, target string) transform.Option {
+	return func(t *transform.Transformer) {
+		if rule, ok := t.Registry().Rule(target); ok {
+			t.Registry().OverrideRule(typeKey, rule)
+		}
+	}
+}
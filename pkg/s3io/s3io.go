@@ -0,0 +1,201 @@
+// Package s3io reads and writes objects addressed by s3://bucket/key URLs.
+package s3io
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// PartSize is the size of each part of a multipart upload. Outputs smaller
+// than one part are uploaded with a single PutObject call.
+const PartSize = 8 << 20
+
+var (
+	errClosed  = errors.New("s3io: writer closed")
+	errAborted = errors.New("s3io: upload aborted")
+)
+
+// API is the subset of the S3 client used by this package.
+type API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// IsURL reports whether name is an s3:// URL.
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "s3://")
+}
+
+// ParseURL splits an s3://bucket/key URL into its bucket and key.
+func ParseURL(rawURL string) (bucket, key string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	key = strings.TrimPrefix(u.Path, "/")
+	if u.Scheme != "s3" || u.Host == "" || key == "" {
+		return "", "", fmt.Errorf("invalid S3 URL %q, want s3://bucket/key", rawURL)
+	}
+	return u.Host, key, nil
+}
+
+// Open returns the body of the object at rawURL. The caller must close it.
+func Open(ctx context.Context, client API, rawURL string) (io.ReadCloser, error) {
+	bucket, key, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// Writer uploads everything written to it as a single object. Data is
+// buffered one part at a time, so arbitrarily large outputs use bounded
+// memory. The object only appears once Close succeeds.
+type Writer struct {
+	ctx    context.Context
+	client API
+	bucket string
+	key    string
+
+	buf      bytes.Buffer
+	uploadID *string
+	parts    []types.CompletedPart
+	err      error
+}
+
+// NewWriter returns a Writer for the object at rawURL.
+func NewWriter(ctx context.Context, client API, rawURL string) (*Writer, error) {
+	bucket, key, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{ctx: ctx, client: client, bucket: bucket, key: key}, nil
+}
+
+// Write buffers p, uploading a part whenever a full part is buffered.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, _ := w.buf.Write(p)
+	for w.buf.Len() >= PartSize {
+		if err := w.uploadPart(w.buf.Next(PartSize)); err != nil {
+			return n, w.fail(err)
+		}
+	}
+	return n, nil
+}
+
+// Close uploads the remaining data and completes the object.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	// Small objects never start a multipart upload
+	if w.uploadID == nil {
+		_, err := w.client.PutObject(w.ctx, &s3.PutObjectInput{
+			Bucket: aws.String(w.bucket),
+			Key:    aws.String(w.key),
+			Body:   bytes.NewReader(w.buf.Bytes()),
+		})
+		if err != nil {
+			return w.fail(err)
+		}
+		w.err = errClosed
+		return nil
+	}
+
+	if w.buf.Len() > 0 {
+		if err := w.uploadPart(w.buf.Bytes()); err != nil {
+			return w.fail(err)
+		}
+	}
+	_, err := w.client.CompleteMultipartUpload(w.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(w.bucket),
+		Key:             aws.String(w.key),
+		UploadId:        w.uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: w.parts},
+	})
+	if err != nil {
+		return w.fail(err)
+	}
+	w.err = errClosed
+	return nil
+}
+
+// Abort discards the upload so no object is created.
+func (w *Writer) Abort() error {
+	if w.err != nil {
+		return nil
+	}
+	w.err = errAborted
+	return w.abortUpload()
+}
+
+// uploadPart uploads data as the next part, starting the multipart upload
+// on first use.
+func (w *Writer) uploadPart(data []byte) error {
+	if w.uploadID == nil {
+		out, err := w.client.CreateMultipartUpload(w.ctx, &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(w.bucket),
+			Key:    aws.String(w.key),
+		})
+		if err != nil {
+			return err
+		}
+		w.uploadID = out.UploadId
+	}
+
+	partNumber := aws.Int32(int32(len(w.parts) + 1))
+	out, err := w.client.UploadPart(w.ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(w.bucket),
+		Key:        aws.String(w.key),
+		UploadId:   w.uploadID,
+		PartNumber: partNumber,
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return err
+	}
+	w.parts = append(w.parts, types.CompletedPart{ETag: out.ETag, PartNumber: partNumber})
+	return nil
+}
+
+// fail records err and aborts any multipart upload in progress so no
+// orphaned parts are left behind.
+func (w *Writer) fail(err error) error {
+	w.err = err
+	w.abortUpload()
+	return err
+}
+
+// abortUpload aborts the multipart upload, if one was started.
+func (w *Writer) abortUpload() error {
+	if w.uploadID == nil {
+		return nil
+	}
+	_, err := w.client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(w.bucket),
+		Key:      aws.String(w.key),
+		UploadId: w.uploadID,
+	})
+	return err
+}
@@ -0,0 +1,267 @@
+package s3io_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/s3io"
+)
+
+// fakeS3 is an in-memory S3 API holding completed objects by bucket/key and
+// the parts of multipart uploads in progress.
+type fakeS3 struct {
+	objects map[string][]byte
+	uploads map[string]map[int32][]byte
+	aborted []string
+	calls   []string
+
+	// failPart fails the upload of the part numbered failPart
+	failPart int32
+}
+
+// newFakeS3 returns a fakeS3 without objects or uploads.
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string][]byte), uploads: make(map[string]map[int32][]byte)}
+}
+
+// GetObject returns the body of a completed object.
+func (f *fakeS3) GetObject(ctx context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	data, ok := f.objects[*in.Bucket+"/"+*in.Key]
+	if !ok {
+		return nil, errors.New("NoSuchKey")
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+// PutObject stores an object whole.
+func (f *fakeS3) PutObject(ctx context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.calls = append(f.calls, "PutObject")
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*in.Bucket+"/"+*in.Key] = data
+	return &s3.PutObjectOutput{}, nil
+}
+
+// CreateMultipartUpload starts an upload without parts.
+func (f *fakeS3) CreateMultipartUpload(ctx context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.calls = append(f.calls, "CreateMultipartUpload")
+	id := fmt.Sprintf("upload-%d", len(f.uploads)+1)
+	f.uploads[id] = make(map[int32][]byte)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+// UploadPart stores a part of an upload, returning an ETag naming it.
+func (f *fakeS3) UploadPart(ctx context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	f.calls = append(f.calls, fmt.Sprintf("UploadPart %d", *in.PartNumber))
+	if *in.PartNumber == f.failPart {
+		return nil, errors.New("part upload failed")
+	}
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.uploads[*in.UploadId][*in.PartNumber] = data
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", *in.PartNumber))}, nil
+}
+
+// CompleteMultipartUpload stores the object made of the listed parts,
+// checking that they are every part uploaded, in order.
+func (f *fakeS3) CompleteMultipartUpload(ctx context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.calls = append(f.calls, "CompleteMultipartUpload")
+	parts := f.uploads[*in.UploadId]
+	var numbers []int
+	for _, part := range in.MultipartUpload.Parts {
+		if *part.ETag != fmt.Sprintf("etag-%d", *part.PartNumber) {
+			return nil, fmt.Errorf("part %d has ETag %s", *part.PartNumber, *part.ETag)
+		}
+		numbers = append(numbers, int(*part.PartNumber))
+	}
+	if !sort.IntsAreSorted(numbers) || len(numbers) != len(parts) {
+		return nil, fmt.Errorf("parts %v do not match the %d uploaded", numbers, len(parts))
+	}
+	var data []byte
+	for _, n := range numbers {
+		data = append(data, parts[int32(n)]...)
+	}
+	f.objects[*in.Bucket+"/"+*in.Key] = data
+	delete(f.uploads, *in.UploadId)
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+// AbortMultipartUpload discards an upload and its parts.
+func (f *fakeS3) AbortMultipartUpload(ctx context.Context, in *s3.AbortMultipartUploadInput, _ ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	f.calls = append(f.calls, "AbortMultipartUpload")
+	f.aborted = append(f.aborted, *in.UploadId)
+	delete(f.uploads, *in.UploadId)
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+// TestParseURL checks the bucket and key of S3 URLs and the rejection of
+// other URLs.
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		bucket, key string
+		wantErr     bool
+	}{
+		{url: "s3://bucket/key.json", bucket: "bucket", key: "key.json"},
+		{url: "s3://bucket/dir/sub/key.ndjson", bucket: "bucket", key: "dir/sub/key.ndjson"},
+		{url: "s3://bucket/", wantErr: true},
+		{url: "s3://bucket", wantErr: true},
+		{url: "s3:///key", wantErr: true},
+		{url: "https://bucket/key", wantErr: true},
+		{url: "s3://bucket/%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		bucket, key, err := s3io.ParseURL(tt.url)
+		if (err != nil) != tt.wantErr || bucket != tt.bucket || key != tt.key {
+			t.Errorf("ParseURL(%q) = %q, %q, %v", tt.url, bucket, key, err)
+		}
+	}
+	if !s3io.IsURL("s3://b/k") || s3io.IsURL("./s3://b/k") {
+		t.Error("IsURL misclassifies URLs")
+	}
+}
+
+// TestOpen checks that objects are read back and missing ones reported.
+func TestOpen(t *testing.T) {
+	client := newFakeS3()
+	client.objects["bucket/in.json"] = []byte(`{"a":{"S":"x"}}`)
+	r, err := s3io.Open(context.Background(), client, "s3://bucket/in.json")
+	if err != nil {
+		t.Fatalf("Open = %v", err)
+	}
+	defer r.Close()
+	if data, err := io.ReadAll(r); err != nil || string(data) != `{"a":{"S":"x"}}` {
+		t.Errorf("read %q, %v", data, err)
+	}
+	if _, err := s3io.Open(context.Background(), client, "s3://bucket/missing.json"); err == nil {
+		t.Error("Open of a missing object succeeded, want an error")
+	}
+}
+
+// TestWriterSmall checks that an output smaller than a part is uploaded with
+// a single PutObject call once the writer is closed.
+func TestWriterSmall(t *testing.T) {
+	client := newFakeS3()
+	w, err := s3io.NewWriter(context.Background(), client, "s3://bucket/out.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{`{"a":1}` + "\n", `{"a":2}` + "\n"} {
+		if _, err := io.WriteString(w, line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls before Close = %v, want none", client.calls)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if got := string(client.objects["bucket/out.json"]); got != `{"a":1}`+"\n"+`{"a":2}`+"\n" {
+		t.Errorf("object = %q", got)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Write after Close succeeded, want an error")
+	}
+}
+
+// TestWriterMultipart checks that every full part is uploaded as soon as it
+// is buffered, and that Close uploads the rest and completes the object.
+func TestWriterMultipart(t *testing.T) {
+	client := newFakeS3()
+	w, err := s3io.NewWriter(context.Background(), client, "s3://bucket/big.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 2*s3io.PartSize+100)
+	for i := range data {
+		data[i] = byte('a' + i%26)
+	}
+
+	if _, err := w.Write(data[:s3io.PartSize-1]); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls before a full part = %v, want none", client.calls)
+	}
+	if _, err := w.Write(data[s3io.PartSize-1 : 2*s3io.PartSize+1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2"}; fmt.Sprint(client.calls) != fmt.Sprint(want) {
+		t.Errorf("calls after two full parts = %v, want %v", client.calls, want)
+	}
+	if _, err := w.Write(data[2*s3io.PartSize+1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	if want := []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2", "UploadPart 3", "CompleteMultipartUpload"}; fmt.Sprint(client.calls) != fmt.Sprint(want) {
+		t.Errorf("calls = %v, want %v", client.calls, want)
+	}
+	if !bytes.Equal(client.objects["bucket/big.json"], data) {
+		t.Error("object differs from the data written")
+	}
+}
+
+// TestWriterFailure checks that a failed part upload aborts the multipart
+// upload and fails later writes and Close.
+func TestWriterFailure(t *testing.T) {
+	client := newFakeS3()
+	client.failPart = 2
+	w, err := s3io.NewWriter(context.Background(), client, "s3://bucket/big.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(make([]byte, 2*s3io.PartSize)); err == nil {
+		t.Fatal("Write succeeded, want the part upload error")
+	}
+	if len(client.aborted) != 1 || len(client.uploads) != 0 {
+		t.Errorf("aborted %v with %d uploads left, want the upload aborted", client.aborted, len(client.uploads))
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Write after a failure succeeded, want an error")
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Close after a failure succeeded, want an error")
+	}
+	if _, ok := client.objects["bucket/big.json"]; ok {
+		t.Error("object created despite the failure")
+	}
+}
+
+// TestWriterAbort checks that an aborted writer creates no object.
+func TestWriterAbort(t *testing.T) {
+	client := newFakeS3()
+	w, err := s3io.NewWriter(context.Background(), client, "s3://bucket/big.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(make([]byte, s3io.PartSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Fatalf("Abort = %v", err)
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Close after Abort succeeded, want an error")
+	}
+	if len(client.aborted) != 1 || len(client.objects) != 0 {
+		t.Errorf("aborted %v with objects %v, want the upload aborted and no object", client.aborted, client.objects)
+	}
+	if _, err := s3io.NewWriter(context.Background(), client, "s3://bucket"); err == nil {
+		t.Error("NewWriter without a key succeeded, want an error")
+	}
+}
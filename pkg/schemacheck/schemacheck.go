@@ -0,0 +1,99 @@
+// Package schemacheck checks transformed documents against a user-supplied
+// JSON Schema, draft 2020-12 unless the schema declares another draft with
+// $schema, so data-quality checks run as part of the transformation.
+package schemacheck
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// ErrViolation is wrapped by the error of every warning Check reports.
+var ErrViolation = errors.New("schema violation")
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// Load reads and compiles the JSON Schema in the file at path. Schemas it
+// refers to by relative $ref are loaded from next to it. Formats such as
+// date-time are asserted, not just annotated.
+func Load(path string) (*Schema, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	doc, err := jsonschema.UnmarshalJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.AssertFormat()
+	if err := c.AddResource(abs, doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	schema, err := c.Compile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Check validates the transformed document doc and returns a warning for
+// every violation, with the JSON Pointer of the offending value as its path.
+// Warnings are sorted by path, then message, so the first is the same on
+// every run.
+func (s *Schema) Check(doc map[string]interface{}) []transform.Warning {
+	err := s.schema.Validate(doc)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		if err != nil {
+			return []transform.Warning{violation("", err.Error())}
+		}
+		return nil
+	}
+
+	// The basic output lists every failed keyword; those without an error
+	// only group the failures of their subschemas
+	var warnings []transform.Warning
+	for _, unit := range verr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		warnings = append(warnings, violation(unit.InstanceLocation, unit.Error.String()))
+	}
+	// Keywords are evaluated in map order
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Path != warnings[j].Path {
+			return warnings[i].Path < warnings[j].Path
+		}
+		return warnings[i].Reason < warnings[j].Reason
+	})
+	return warnings
+}
+
+// Option returns the option checking every transformed document against s.
+func (s *Schema) Option() transform.Option {
+	return transform.WithOutputCheck(s.Check)
+}
+
+// violation returns the warning for a violation at the JSON Pointer path.
+func violation(path, msg string) transform.Warning {
+	err := fmt.Errorf("%w: %s", ErrViolation, msg)
+	return transform.Warning{Path: path, Reason: err.Error(), Err: err}
+}
@@ -0,0 +1,131 @@
+package schemacheck_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemacheck"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// update rewrites the golden files with the warnings reported.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// decode returns the JSON document doc decoded as the CLI decodes
+// transformed output, with numbers as json.Number.
+func decode(t *testing.T, doc string) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		t.Fatalf("decode %s: %v", doc, err)
+	}
+	return out
+}
+
+// TestCheck compares the warnings reported for documents checked against
+// testdata/user.schema.json, which refers to a schema next to it, with the
+// golden files in testdata.
+func TestCheck(t *testing.T) {
+	schema, err := schemacheck.Load(filepath.Join("testdata", "user.schema.json"))
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "valid", doc: `{"id":1,"email":"a@b.c","created_at":"2024-01-02T03:04:05Z","address":{"city":"Paris","zip":"75001"},"tags":["x"]}`},
+		{name: "missing and wrong types", doc: `{"id":"1","tags":[1]}`},
+		{name: "formats and referenced schema", doc: `{"id":0,"email":"nope","created_at":"yesterday","address":{"city":"","zip":"7500"}}`},
+		{name: "additional properties", doc: `{"id":1,"email":"a@b.c","extra":true,"tags":["x","x"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			for _, w := range schema.Check(decode(t, tt.doc)) {
+				if !errors.Is(w.Err, schemacheck.ErrViolation) || w.Reason != w.Err.Error() {
+					t.Errorf("warning %+v does not wrap ErrViolation as its reason", w)
+				}
+				fmt.Fprintf(&b, "%s: %s\n", w.Path, w.Reason)
+			}
+			got := b.String()
+			golden := filepath.Join("testdata", strings.ReplaceAll(tt.name, " ", "_")+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("Check =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestOption checks that violations fail a record with OnErrorFail and are
+// passed to the warning handler otherwise.
+func TestOption(t *testing.T) {
+	schema, err := schemacheck.Load(filepath.Join("testdata", "user.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := decode(t, `{"id":{"N":"1"},"email":{"S":"nope"}}`)
+
+	tr := transform.New(schema.Option(), transform.WithOnError(transform.OnErrorFail))
+	_, err = tr.TransformRecord(0, input)
+	var pathErr *transform.PathError
+	if !errors.As(err, &pathErr) || pathErr.Pointer != "/email" || !errors.Is(err, schemacheck.ErrViolation) {
+		t.Errorf("TransformRecord = %v, want a violation at /email", err)
+	}
+
+	var warnings []transform.Warning
+	tr = transform.New(schema.Option(), transform.WithWarningHandler(func(_ int, w transform.Warning) {
+		warnings = append(warnings, w)
+	}))
+	if _, err := tr.TransformRecord(0, input); err != nil {
+		t.Fatalf("TransformRecord = %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Path != "/email" {
+		t.Errorf("warnings = %+v, want one at /email", warnings)
+	}
+}
+
+// TestLoadErrors checks that unreadable and invalid schemas are rejected.
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "missing file", path: filepath.Join(dir, "missing.json")},
+		{name: "not JSON", path: write("bad.json", `{"type":`)},
+		{name: "invalid keyword", path: write("invalid.json", `{"type":"whole number"}`)},
+		{name: "missing reference", path: write("ref.json", `{"$ref":"other.json"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := schemacheck.Load(tt.path); err == nil {
+				t.Error("Load succeeded, want an error")
+			}
+		})
+	}
+}
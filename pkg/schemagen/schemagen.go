@@ -0,0 +1,214 @@
+// Package schemagen infers a JSON Schema describing a set of plain JSON
+// documents, such as the output of the transformer: the types of their
+// values, the properties every object has, and formats such as date-time
+// that every string of a value matches.
+package schemagen
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the inferred schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Inferrer accumulates the shape of the documents added to it.
+type Inferrer struct {
+	root *node
+}
+
+// New returns an Inferrer that has seen no documents.
+func New() *Inferrer {
+	return &Inferrer{root: newNode()}
+}
+
+// Add merges the shape of the decoded JSON document doc into the schema.
+func (i *Inferrer) Add(doc interface{}) {
+	i.root.add(doc)
+}
+
+// Schema returns the inferred JSON Schema, ready to be encoded as JSON. A
+// value whose instances have several types is described by a list of types,
+// integers and other numbers are merged into number, and a property is only
+// required when every object holding the value has it.
+func (i *Inferrer) Schema() map[string]interface{} {
+	schema := i.root.schema()
+	schema["$schema"] = Draft
+	return schema
+}
+
+// node is the merged shape of every instance of one value.
+type node struct {
+	// types counts the instances of each JSON Schema type
+	types map[string]int
+
+	// props holds the merged shape of each property across objects, and
+	// propCount how many objects had it
+	props     map[string]*node
+	propCount map[string]int
+
+	// items is the merged shape of every element of every array
+	items *node
+
+	// formats counts the strings matching each format
+	formats map[string]int
+}
+
+// newNode returns a node without instances.
+func newNode() *node {
+	return &node{
+		types:     make(map[string]int),
+		props:     make(map[string]*node),
+		propCount: make(map[string]int),
+		formats:   make(map[string]int),
+	}
+}
+
+// add merges the instance v into n.
+func (n *node) add(v interface{}) {
+	kind := typeOf(v)
+	n.types[kind]++
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			prop, ok := n.props[key]
+			if !ok {
+				prop = newNode()
+				n.props[key] = prop
+			}
+			prop.add(elem)
+			n.propCount[key]++
+		}
+	case []interface{}:
+		if n.items == nil {
+			n.items = newNode()
+		}
+		for _, elem := range v {
+			n.items.add(elem)
+		}
+	case string:
+		for _, f := range stringFormats {
+			if f.match(v) {
+				n.formats[f.name]++
+			}
+		}
+	}
+}
+
+// schema returns the JSON Schema describing the instances of n.
+func (n *node) schema() map[string]interface{} {
+	schema := make(map[string]interface{})
+	types := n.typeNames()
+	switch len(types) {
+	case 0:
+		// An array that was always empty says nothing about its elements
+		return schema
+	case 1:
+		schema["type"] = types[0]
+	default:
+		schema["type"] = types
+	}
+
+	if count := n.types["object"]; count > 0 {
+		props := make(map[string]interface{}, len(n.props))
+		var required []string
+		for key, prop := range n.props {
+			props[key] = prop.schema()
+			if n.propCount[key] == count {
+				required = append(required, key)
+			}
+		}
+		schema["properties"] = props
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	if n.types["array"] > 0 && n.items != nil {
+		if items := n.items.schema(); len(items) > 0 {
+			schema["items"] = items
+		}
+	}
+	if count := n.types["string"]; count > 0 {
+		for _, f := range stringFormats {
+			if n.formats[f.name] == count {
+				schema["format"] = f.name
+				break
+			}
+		}
+	}
+	return schema
+}
+
+// typeNames returns the JSON Schema types of n's instances in a fixed order,
+// folding integer into number when both occur.
+func (n *node) typeNames() []string {
+	var names []string
+	for _, name := range []string{"object", "array", "string", "integer", "number", "boolean", "null"} {
+		if n.types[name] == 0 {
+			continue
+		}
+		if name == "integer" && n.types["number"] > 0 {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// typeOf returns the JSON Schema type of the decoded JSON value v.
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case float32:
+		return "number"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// stringFormat is a JSON Schema format recognised in strings.
+type stringFormat struct {
+	name  string
+	match func(s string) bool
+}
+
+// stringFormats lists the recognised formats, most specific first.
+var stringFormats = []stringFormat{
+	{"date-time", func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	}},
+	{"date", func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	}},
+	{"uuid", uuidPattern.MatchString},
+	{"email", emailPattern.MatchString},
+}
+
+var (
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
@@ -0,0 +1,106 @@
+package schemagen_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+)
+
+// TestSchema checks the schema inferred for sets of documents against the
+// expected schema, without the $schema keyword.
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []string
+		want string
+	}{
+		{
+			name: "required only when every document has the property",
+			docs: []string{`{"id":1,"name":"a"}`, `{"id":2,"email":"b@c.d"}`},
+			want: `{"properties":{"email":{"format":"email","type":"string"},"id":{"type":"integer"},"name":{"type":"string"}},"required":["id"],"type":"object"}`,
+		},
+		{
+			name: "required in nested objects counted among the objects",
+			docs: []string{`{"user":{"id":1,"tags":["a"]}}`, `{"user":{"id":2}}`, `{"other":true}`},
+			want: `{"properties":{"other":{"type":"boolean"},"user":{"properties":{"id":{"type":"integer"},"tags":{"items":{"type":"string"},"type":"array"}},"required":["id"],"type":"object"}},"type":"object"}`,
+		},
+		{
+			name: "null and several types",
+			docs: []string{`{"v":1,"n":null}`, `{"v":"x","n":2.5}`, `{"v":[1.5,2],"n":3}`},
+			want: `{"properties":{"n":{"type":["number","null"]},"v":{"items":{"type":"number"},"type":["array","string","integer"]}},"required":["n","v"],"type":"object"}`,
+		},
+		{
+			name: "array items merged across elements",
+			docs: []string{`{"items":[{"a":1},{"a":2,"b":"x"}]}`, `{"items":[]}`},
+			want: `{"properties":{"items":{"items":{"properties":{"a":{"type":"integer"},"b":{"type":"string"}},"required":["a"],"type":"object"},"type":"array"}},"required":["items"],"type":"object"}`,
+		},
+		{
+			name: "always empty arrays have no items",
+			docs: []string{`{"e":[]}`},
+			want: `{"properties":{"e":{"type":"array"}},"required":["e"],"type":"object"}`,
+		},
+		{
+			name: "formats every string matches",
+			docs: []string{
+				`{"at":"2024-01-02T03:04:05Z","day":"2024-01-02","id":"123e4567-e89b-12d3-a456-426614174000","mixed":"2024-01-02"}`,
+				`{"at":"2024-01-03T00:00:00+02:00","day":"2024-01-03","id":"123E4567-E89B-12D3-A456-426614174000","mixed":"x"}`,
+			},
+			want: `{"properties":{"at":{"format":"date-time","type":"string"},"day":{"format":"date","type":"string"},"id":{"format":"uuid","type":"string"},"mixed":{"type":"string"}},"required":["at","day","id","mixed"],"type":"object"}`,
+		},
+		{
+			name: "integers beyond 64 bits are numbers",
+			docs: []string{`{"big":123456789012345678901234567890,"max":9223372036854775807}`},
+			want: `{"properties":{"big":{"type":"number"},"max":{"type":"integer"}},"required":["big","max"],"type":"object"}`,
+		},
+		{
+			name: "no documents",
+			want: `{}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inferrer := schemagen.New()
+			for _, doc := range tt.docs {
+				dec := json.NewDecoder(strings.NewReader(doc))
+				dec.UseNumber()
+				var v interface{}
+				if err := dec.Decode(&v); err != nil {
+					t.Fatal(err)
+				}
+				inferrer.Add(v)
+			}
+			schema := inferrer.Schema()
+			if schema["$schema"] != schemagen.Draft {
+				t.Errorf("$schema = %v, want %s", schema["$schema"], schemagen.Draft)
+			}
+			delete(schema, "$schema")
+			got, err := json.Marshal(schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Schema =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSchemaFloat64 checks that documents decoded without UseNumber are
+// typed the same way.
+func TestSchemaFloat64(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"i":3,"f":1.5}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	inferrer := schemagen.New()
+	inferrer.Add(doc)
+	props := inferrer.Schema()["properties"].(map[string]interface{})
+	if got := props["i"].(map[string]interface{})["type"]; got != "integer" {
+		t.Errorf("type of 3 = %v, want integer", got)
+	}
+	if got := props["f"].(map[string]interface{})["type"]; got != "number" {
+		t.Errorf("type of 1.5 = %v, want number", got)
+	}
+}
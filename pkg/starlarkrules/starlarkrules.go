@@ -0,0 +1,200 @@
+// Package starlarkrules loads transformation logic written in Starlark, a
+// Python-like language that runs in a sandbox: scripts cannot load other
+// files, have no access to the file system or network, and every call is
+// limited to a fixed number of execution steps.
+//
+// A script defines a single function that is called for every typed value:
+//
+//	def transform(path, type, value):
+//	    if type == "S" and path[-1] == "email":
+//	        return value.lower()
+//	    return PASS
+//
+// path is a tuple of the keys leading to the value, with list elements
+// addressed by their decimal index, type is the type tag such as "S" or "N",
+// and value is the raw value wrapped by the type tag. Returning PASS applies
+// the built-in rule for the type, and calling fail() omits the value.
+package starlarkrules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// MaxSteps bounds the execution steps of each call into a script, so a
+// runaway loop fails the value instead of hanging the transformation.
+const MaxSteps = 1_000_000
+
+// pass is the PASS sentinel returned to defer to the built-in rule.
+var pass = starlark.String("__starlarkrules_pass__")
+
+// Script is a loaded Starlark rules script. Its globals are frozen after
+// loading, so it is safe to call from several goroutines.
+type Script struct {
+	fn *starlark.Function
+}
+
+// Load executes the Starlark script at path and returns its transform
+// function.
+func Load(path string) (*Script, error) {
+	thread := newThread(path)
+	predeclared := starlark.StringDict{"PASS": pass}
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, path, nil, predeclared)
+	if err != nil {
+		return nil, err
+	}
+	globals.Freeze()
+
+	fn, ok := globals["transform"].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("%s: must define a transform(path, type, value) function", path)
+	}
+	if fn.NumParams() != 3 {
+		return nil, fmt.Errorf("%s: transform must take (path, type, value), not %d parameters", path, fn.NumParams())
+	}
+	return &Script{fn: fn}, nil
+}
+
+// newThread returns a sandboxed thread: load statements are rejected and
+// execution is bounded by MaxSteps.
+func newThread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Load: func(*starlark.Thread, string) (starlark.StringDict, error) {
+			return nil, errors.New("load is not allowed in rules scripts")
+		},
+	}
+	thread.SetMaxExecutionSteps(MaxSteps)
+	return thread
+}
+
+// Option returns the option registering the script as middleware.
+func (s *Script) Option() transform.Option {
+	return transform.WithMiddleware(s.middleware)
+}
+
+// middleware calls the script's transform function for every attribute,
+// falling through to the built-in rule when it returns PASS.
+func (s *Script) middleware(next transform.TransformFunc) transform.TransformFunc {
+	return func(a transform.Attribute) (interface{}, bool) {
+		path := make(starlark.Tuple, len(a.Path))
+		for i, key := range a.Path {
+			path[i] = starlark.String(key)
+		}
+		value, err := toStarlark(a.Value)
+		if err != nil {
+			return nil, false
+		}
+
+		result, err := starlark.Call(newThread("transform"), s.fn, starlark.Tuple{path, starlark.String(a.Type), value}, nil)
+		if err != nil {
+			return nil, false
+		}
+		if result == pass {
+			return next(a)
+		}
+		out, err := fromStarlark(result)
+		if err != nil {
+			return nil, false
+		}
+		return out, true
+	}
+}
+
+// toStarlark converts a decoded JSON value into a Starlark value.
+func toStarlark(v interface{}) (starlark.Value, error) {
+	switch val := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(val), nil
+	case string:
+		return starlark.String(val), nil
+	case float64:
+		return starlark.Float(val), nil
+	case int64:
+		return starlark.MakeInt64(val), nil
+	case json.Number:
+		if n, ok := new(big.Int).SetString(val.String(), 10); ok {
+			return starlark.MakeBigInt(n), nil
+		}
+		f, err := val.Float64()
+		return starlark.Float(f), err
+	case []interface{}:
+		list := make([]starlark.Value, 0, len(val))
+		for _, elem := range val {
+			sv, err := toStarlark(elem)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, sv)
+		}
+		return starlark.NewList(list), nil
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(val))
+		for k, elem := range val {
+			sv, err := toStarlark(elem)
+			if err != nil {
+				return nil, err
+			}
+			dict.SetKey(starlark.String(k), sv)
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %T", v)
+	}
+}
+
+// fromStarlark converts a Starlark value into a plain Go value.
+func fromStarlark(v starlark.Value) (interface{}, error) {
+	switch val := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(val), nil
+	case starlark.String:
+		return string(val), nil
+	case starlark.Float:
+		return float64(val), nil
+	case starlark.Int:
+		if n, ok := val.Int64(); ok {
+			return n, nil
+		}
+		// Integers beyond int64 keep their exact digits
+		return json.Number(val.String()), nil
+	case starlark.Indexable:
+		// Lists and tuples
+		out := make([]interface{}, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			elem, err := fromStarlark(val.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, elem)
+		}
+		return out, nil
+	case *starlark.Dict:
+		out := make(map[string]interface{}, val.Len())
+		for _, item := range val.Items() {
+			key, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict key %s is not a string", item[0])
+			}
+			elem, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, err
+			}
+			out[string(key)] = elem
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported %s result", v.Type())
+	}
+}
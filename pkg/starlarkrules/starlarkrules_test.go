@@ -0,0 +1,132 @@
+package starlarkrules_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/starlarkrules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// load writes the Starlark script src to a temporary file and loads it.
+func load(t *testing.T, src string) (*starlarkrules.Script, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.star")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return starlarkrules.Load(path)
+}
+
+// TestLoadErrors checks that scripts that fail to run or do not define a
+// suitable transform function are rejected when loaded.
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "syntax error", src: "def transform(path, type, value)\n", want: "rules.star:"},
+		{name: "runtime error", src: `fail("boom")`, want: "boom"},
+		{name: "load", src: `load("other.star", "x")`, want: "load is not allowed in rules scripts"},
+		{name: "no transform", src: "x = 1\n", want: "must define a transform(path, type, value) function"},
+		{name: "wrong parameters", src: "def transform(value):\n    return value\n", want: "transform must take (path, type, value), not 1 parameters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// script is the rules script the transformation tests load.
+const script = `
+def transform(path, type, value):
+    if path[-1] == "email":
+        return value.lower()
+    if path[-1] == "tags":
+        return value.split(",")
+    if path[-1] == "pair":
+        return {"key": value, "size": len(value)}
+    if path[-1] == "big":
+        return int(value) * 10
+    if path[-1] == "bad":
+        fail("bad value " + value)
+    if path[-1] == "loop":
+        for i in range(1000000000):
+            pass
+    if path[-1] == "unsupported":
+        return {1: value}
+    return PASS
+`
+
+// TestTransform checks the values the script produces, that failures and
+// runaway loops make values invalid and that PASS applies the built-in rule.
+func TestTransform(t *testing.T) {
+	s, err := load(t, script)
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	var input map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"user": {"M": {"email": {"S": "A@B.C"}, "tags": {"S": "x,y"}, "pair": {"S": "ab"}}},
+		"big": {"N": "123456789012345678901234567890"},
+		"bad": {"S": "v"},
+		"loop": {"S": "v"},
+		"unsupported": {"S": "v"},
+		"count": {"N": "7"}
+	}`), &input); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"user": map[string]interface{}{
+			"email": "a@b.c",
+			"tags":  []interface{}{"x", "y"},
+			"pair":  map[string]interface{}{"key": "ab", "size": int64(2)},
+		},
+		"big":   json.Number("1234567890123456789012345678900"),
+		"count": int64(7),
+	}
+	if got := transform.New(s.Option()).Transform(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform = %#v, want %#v", got, want)
+	}
+}
+
+// TestTransformContextCancelled checks that a stream transformed with the
+// script stops once its context is cancelled.
+func TestTransformContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := load(t, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cancel while the first record is being transformed, from middleware
+	// running outside the script's
+	cancelling := transform.WithMiddleware(func(next transform.TransformFunc) transform.TransformFunc {
+		return func(a transform.Attribute) (interface{}, bool) {
+			cancel()
+			return next(a)
+		}
+	})
+
+	in := strings.Repeat(`{"email":{"S":"AB"}}`+"\n", 3)
+	var out bytes.Buffer
+	err = transform.New(cancelling, s.Option()).TransformNDJSONContext(ctx, strings.NewReader(in), &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("TransformNDJSONContext = %v, want context.Canceled", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("wrote %q, want nothing", out.String())
+	}
+}
@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LoadCheckpoint reads the positions saved by SaveCheckpoint, keyed by
+// partition such as a shard ID. A missing file yields an empty map.
+func LoadCheckpoint(path string) (map[string]string, error) {
+	positions := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return positions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// SaveCheckpoint writes positions to path, replacing the file atomically so
+// an interrupted save never leaves a corrupt checkpoint behind.
+func SaveCheckpoint(path string, positions map[string]string) error {
+	data, err := json.Marshal(positions)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Checkpointer keeps the positions of a Source in a checkpoint file, saving
+// them as records are committed at most once per interval. The methods of a
+// nil Checkpointer do nothing, for sources without a checkpoint file.
+type Checkpointer struct {
+	path     string
+	interval time.Duration
+
+	mu        sync.Mutex
+	positions map[string]string
+	lastSave  time.Time
+}
+
+// NewCheckpointer returns a Checkpointer for the checkpoint file at path,
+// loading the positions saved by an earlier run, if any. A zero interval
+// saves at most every five seconds.
+func NewCheckpointer(path string, interval time.Duration) (*Checkpointer, error) {
+	positions, err := LoadCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	return &Checkpointer{path: path, interval: interval, positions: positions, lastSave: time.Now()}, nil
+}
+
+// Position returns the position saved under key, or "" if there is none.
+func (c *Checkpointer) Position(key string) string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.positions[key]
+}
+
+// Commit records positions, saving the checkpoint file when the interval
+// has elapsed since it was last saved.
+func (c *Checkpointer) Commit(positions map[string]string) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, position := range positions {
+		c.positions[key] = position
+	}
+	if time.Since(c.lastSave) < c.interval {
+		return nil
+	}
+	c.lastSave = time.Now()
+	return SaveCheckpoint(c.path, c.positions)
+}
+
+// Save writes the committed positions to the checkpoint file, such as when
+// the source is closed before it was read to the end.
+func (c *Checkpointer) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastSave = time.Now()
+	return SaveCheckpoint(c.path, c.positions)
+}
+
+// Remove deletes the checkpoint file once the source has been read and
+// committed to the end, so the next run starts afresh.
+func (c *Checkpointer) Remove() error {
+	if c == nil {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
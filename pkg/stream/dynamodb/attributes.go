@@ -0,0 +1,206 @@
+// Package dynamodb connects stream pipelines to Amazon DynamoDB tables.
+package dynamodb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ToAttributeValues converts an item in typed JSON, as produced by
+// transform.Reverse, into DynamoDB attribute values.
+func ToAttributeValues(item map[string]interface{}) (map[string]types.AttributeValue, error) {
+	out := make(map[string]types.AttributeValue, len(item))
+	for key, value := range item {
+		typed, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("attribute %q is not a typed value", key)
+		}
+		av, err := toAttributeValue(typed)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", key, err)
+		}
+		out[key] = av
+	}
+	return out, nil
+}
+
+// toAttributeValue converts a single typed value such as {"S": "x"}.
+func toAttributeValue(typed map[string]interface{}) (types.AttributeValue, error) {
+	if len(typed) != 1 {
+		return nil, fmt.Errorf("typed value must have exactly one type key, got %d", len(typed))
+	}
+	for typeKey, v := range typed {
+		switch typeKey {
+		case "S":
+			s, err := scalarString(v)
+			return &types.AttributeValueMemberS{Value: s}, err
+		case "N":
+			n, err := scalarString(v)
+			return &types.AttributeValueMemberN{Value: n}, err
+		case "BOOL":
+			s, err := scalarString(v)
+			if err != nil {
+				return nil, err
+			}
+			b, err := strconv.ParseBool(s)
+			return &types.AttributeValueMemberBOOL{Value: b}, err
+		case "NULL":
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		case "B":
+			b, err := binary(v)
+			return &types.AttributeValueMemberB{Value: b}, err
+		case "M":
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("M value is not an object")
+			}
+			values, err := ToAttributeValues(m)
+			return &types.AttributeValueMemberM{Value: values}, err
+		case "L":
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("L value is not an array")
+			}
+			values := make([]types.AttributeValue, 0, len(list))
+			for i, item := range list {
+				elem, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("L element %d is not a typed value", i)
+				}
+				av, err := toAttributeValue(elem)
+				if err != nil {
+					return nil, fmt.Errorf("L element %d: %w", i, err)
+				}
+				values = append(values, av)
+			}
+			return &types.AttributeValueMemberL{Value: values}, nil
+		case "SS", "NS":
+			set, err := stringSet(v)
+			if typeKey == "SS" {
+				return &types.AttributeValueMemberSS{Value: set}, err
+			}
+			return &types.AttributeValueMemberNS{Value: set}, err
+		case "BS":
+			set, err := stringSet(v)
+			if err != nil {
+				return nil, err
+			}
+			values := make([][]byte, len(set))
+			for i, elem := range set {
+				if values[i], err = binary(elem); err != nil {
+					return nil, err
+				}
+			}
+			return &types.AttributeValueMemberBS{Value: values}, nil
+		default:
+			return nil, fmt.Errorf("unknown type key %q", typeKey)
+		}
+	}
+	return nil, nil
+}
+
+// FromAttributeValues converts DynamoDB attribute values into typed JSON that
+// transform.Transform accepts.
+func FromAttributeValues(item map[string]types.AttributeValue) map[string]interface{} {
+	out := make(map[string]interface{}, len(item))
+	for key, av := range item {
+		if typed := fromAttributeValue(av); typed != nil {
+			out[key] = typed
+		}
+	}
+	return out
+}
+
+// fromAttributeValue converts a single attribute value into its typed JSON form.
+func fromAttributeValue(av types.AttributeValue) map[string]interface{} {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": v.Value}
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": v.Value}
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": v.Value}
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": true}
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": base64.StdEncoding.EncodeToString(v.Value)}
+	case *types.AttributeValueMemberM:
+		return map[string]interface{}{"M": FromAttributeValues(v.Value)}
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, 0, len(v.Value))
+		for _, elem := range v.Value {
+			if typed := fromAttributeValue(elem); typed != nil {
+				list = append(list, typed)
+			}
+		}
+		return map[string]interface{}{"L": list}
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": toInterfaces(v.Value)}
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": toInterfaces(v.Value)}
+	case *types.AttributeValueMemberBS:
+		set := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			set[i] = base64.StdEncoding.EncodeToString(b)
+		}
+		return map[string]interface{}{"BS": toInterfaces(set)}
+	default:
+		return nil
+	}
+}
+
+// scalarString returns a typed scalar as a string, accepting the native JSON
+// booleans and numbers some producers emit in place of strings.
+func scalarString(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case json.Number:
+		return val.String(), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("value %v is not a scalar", v)
+	}
+}
+
+// binary decodes a base64 encoded B value.
+func binary(v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("binary value %v is not a string", v)
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// stringSet converts an SS, NS or BS array into strings.
+func stringSet(v interface{}) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("set value is not an array")
+	}
+	set := make([]string, len(list))
+	for i, elem := range list {
+		s, err := scalarString(elem)
+		if err != nil {
+			return nil, err
+		}
+		set[i] = s
+	}
+	return set, nil
+}
+
+// toInterfaces converts strings into a JSON array.
+func toInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxBatchItems is the most items BatchWriteItem accepts in one request.
+const maxBatchItems = 25
+
+// WriteAPI is the subset of the DynamoDB client used by Sink.
+type WriteAPI interface {
+	BatchWriteItem(ctx context.Context, params *awsdynamodb.BatchWriteItemInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.BatchWriteItemOutput, error)
+}
+
+// SinkOptions configures a Sink.
+type SinkOptions struct {
+	// MaxRetries bounds how often unprocessed items are resubmitted before
+	// Write fails. It defaults to 8.
+	MaxRetries int
+
+	// RetryDelay is the initial delay before resubmitting unprocessed items,
+	// doubling on every attempt. It defaults to 50 milliseconds.
+	RetryDelay time.Duration
+}
+
+// Sink writes typed JSON items, as produced by transform.Reverse, to a
+// DynamoDB table with BatchWriteItem.
+type Sink struct {
+	client WriteAPI
+	table  string
+	opts   SinkOptions
+}
+
+// NewSink returns a Sink writing to table.
+func NewSink(client WriteAPI, table string, opts SinkOptions) *Sink {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 8
+	}
+	if opts.RetryDelay == 0 {
+		opts.RetryDelay = 50 * time.Millisecond
+	}
+	return &Sink{client: client, table: table, opts: opts}
+}
+
+// Write puts every item in docs, split into batches of 25.
+func (s *Sink) Write(ctx context.Context, docs []map[string]interface{}) error {
+	requests := make([]types.WriteRequest, 0, len(docs))
+	for i, doc := range docs {
+		item, err := ToAttributeValues(doc)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+
+	for start := 0; start < len(requests); start += maxBatchItems {
+		end := min(start+maxBatchItems, len(requests))
+		if err := s.writeBatch(ctx, requests[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBatch submits one batch, resubmitting unprocessed items with
+// exponential backoff.
+func (s *Sink) writeBatch(ctx context.Context, requests []types.WriteRequest) error {
+	delay := s.opts.RetryDelay
+	for attempt := 0; ; attempt++ {
+		out, err := s.client.BatchWriteItem(ctx, &awsdynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{s.table: requests},
+		})
+		if err != nil {
+			return err
+		}
+
+		requests = out.UnprocessedItems[s.table]
+		if len(requests) == 0 {
+			return nil
+		}
+		if attempt == s.opts.MaxRetries {
+			return fmt.Errorf("%d items still unprocessed after %d retries", len(requests), attempt)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// Close is a no-op; every Write is complete when it returns.
+func (s *Sink) Close() error {
+	return nil
+}
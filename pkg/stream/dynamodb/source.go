@@ -0,0 +1,205 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+)
+
+// ScanAPI is the subset of the DynamoDB client used by Source.
+type ScanAPI interface {
+	Scan(ctx context.Context, params *awsdynamodb.ScanInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.ScanOutput, error)
+}
+
+// SourceOptions configures a Source.
+type SourceOptions struct {
+	// PageSize is the number of items requested per Scan call. Zero lets
+	// DynamoDB fill each page up to its 1 MB limit.
+	PageSize int32
+
+	// ConsistentRead requests strongly consistent reads.
+	ConsistentRead bool
+
+	// CheckpointFile, when set, persists the start of the page being read
+	// and how many of its items have been committed, so a restarted scan
+	// resumes there instead of from the start of the table. The file is
+	// loaded by the first call to Next and removed once the whole table has
+	// been committed.
+	CheckpointFile string
+
+	// CheckpointInterval bounds how often committing a record rewrites the
+	// checkpoint file. It defaults to five seconds; stream.Run also saves the
+	// file after every batch.
+	CheckpointInterval time.Duration
+}
+
+// Checkpoint keys of the start key of the page being read, as typed JSON,
+// and of the number of its items committed.
+const (
+	pageStartPosition = "start"
+	pageItemsPosition = "skip"
+)
+
+// Source scans every item of a DynamoDB table, one page at a time, and
+// yields each item as typed JSON.
+type Source struct {
+	client ScanAPI
+	table  string
+	opts   SourceOptions
+
+	page    []map[string]types.AttributeValue
+	lastKey map[string]types.AttributeValue
+	done    bool
+
+	// checkpoint records the position of the last item committed: the
+	// start key of its page, as typed JSON, and its index in the page
+	checkpoint *stream.Checkpointer
+	loaded     bool
+	pageStart  string
+	pageIndex  int
+	read       int
+	committed  int
+}
+
+// NewSource returns a Source scanning table.
+func NewSource(client ScanAPI, table string, opts SourceOptions) *Source {
+	return &Source{client: client, table: table, opts: opts}
+}
+
+// Next returns the next item, fetching another page when the current one is
+// used up, and io.EOF once the whole table has been scanned.
+func (s *Source) Next(ctx context.Context) (stream.Record, error) {
+	if !s.loaded {
+		if err := s.resume(ctx); err != nil {
+			return stream.Record{}, err
+		}
+		s.loaded = true
+	}
+	for len(s.page) == 0 {
+		if s.done {
+			return stream.Record{}, io.EOF
+		}
+		if err := s.fetch(ctx); err != nil {
+			return stream.Record{}, err
+		}
+	}
+
+	item := s.page[0]
+	s.page = s.page[1:]
+	s.pageIndex++
+	s.read++
+	rec := stream.Record{Document: FromAttributeValues(item)}
+	if s.checkpoint != nil {
+		start, index := s.pageStart, s.pageIndex
+		rec.Commit = func() error {
+			s.committed++
+			return s.checkpoint.Commit(map[string]string{
+				pageStartPosition: start,
+				pageItemsPosition: strconv.Itoa(index),
+			})
+		}
+	}
+	return rec, nil
+}
+
+// resume loads the checkpoint file, if any, and positions the scan at the
+// page and item it records.
+func (s *Source) resume(ctx context.Context) error {
+	if s.opts.CheckpointFile == "" {
+		return nil
+	}
+	checkpoint, err := stream.NewCheckpointer(s.opts.CheckpointFile, s.opts.CheckpointInterval)
+	if err != nil {
+		return err
+	}
+	s.checkpoint = checkpoint
+
+	start := checkpoint.Position(pageStartPosition)
+	skip, _ := strconv.Atoi(checkpoint.Position(pageItemsPosition))
+	if start == "" && skip == 0 {
+		return nil
+	}
+	if start != "" {
+		var typed map[string]interface{}
+		if err := json.Unmarshal([]byte(start), &typed); err != nil {
+			return fmt.Errorf("checkpoint %s: %w", s.opts.CheckpointFile, err)
+		}
+		if s.lastKey, err = ToAttributeValues(typed); err != nil {
+			return fmt.Errorf("checkpoint %s: %w", s.opts.CheckpointFile, err)
+		}
+	}
+
+	// Pages are requested with the same start key and size as before, so
+	// the items already committed are the first of the page
+	if err := s.fetch(ctx); err != nil {
+		return err
+	}
+	if skip > len(s.page) {
+		skip = len(s.page)
+	}
+	s.page, s.pageIndex = s.page[skip:], skip
+	return nil
+}
+
+// fetch scans the next page of the table.
+func (s *Source) fetch(ctx context.Context) error {
+	in := &awsdynamodb.ScanInput{
+		TableName:         aws.String(s.table),
+		ExclusiveStartKey: s.lastKey,
+		ConsistentRead:    aws.Bool(s.opts.ConsistentRead),
+	}
+	if s.opts.PageSize > 0 {
+		in.Limit = aws.Int32(s.opts.PageSize)
+	}
+
+	out, err := s.client.Scan(ctx, in)
+	if err != nil {
+		return err
+	}
+	if s.checkpoint != nil {
+		if s.pageStart, err = startKeyJSON(s.lastKey); err != nil {
+			return err
+		}
+	}
+	s.page, s.pageIndex = out.Items, 0
+	s.lastKey = out.LastEvaluatedKey
+	// An empty LastEvaluatedKey marks the final page
+	s.done = len(out.LastEvaluatedKey) == 0
+	return nil
+}
+
+// startKeyJSON returns the start key of a page as typed JSON, or "" for
+// the first page.
+func startKeyJSON(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(FromAttributeValues(key))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// CommitBatch saves the checkpoint with the items committed so far.
+func (s *Source) CommitBatch() error {
+	return s.checkpoint.Save()
+}
+
+// Close saves the checkpoint, or removes it once the whole table has been
+// scanned and committed. The scan holds no other resources between pages.
+func (s *Source) Close() error {
+	if s.done && len(s.page) == 0 && s.committed == s.read {
+		return s.checkpoint.Remove()
+	}
+	return s.checkpoint.Save()
+}
@@ -0,0 +1,151 @@
+// Package kafka provides a stream.Source consuming typed JSON records from a
+// Kafka topic and a stream.Sink producing transformed records to one.
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// SourceOptions configures a Source.
+type SourceOptions struct {
+	Brokers []string
+	Topic   string
+
+	// GroupID is the consumer group whose committed offsets the Source
+	// resumes from.
+	GroupID string
+
+	// ErrorTopic, when set, receives messages that are not valid JSON, with
+	// the failure in an "error" header, instead of stopping the consumer.
+	ErrorTopic string
+}
+
+// Source consumes typed JSON records from a Kafka topic as part of a
+// consumer group, committing offsets once records reach the sink.
+type Source struct {
+	reader    *kafkago.Reader
+	errWriter *kafkago.Writer
+}
+
+// NewSource returns a Source for the topic described by opts.
+func NewSource(opts SourceOptions) *Source {
+	s := &Source{
+		reader: kafkago.NewReader(kafkago.ReaderConfig{
+			Brokers: opts.Brokers,
+			Topic:   opts.Topic,
+			GroupID: opts.GroupID,
+		}),
+	}
+	if opts.ErrorTopic != "" {
+		s.errWriter = &kafkago.Writer{
+			Addr:         kafkago.TCP(opts.Brokers...),
+			Topic:        opts.ErrorTopic,
+			RequiredAcks: kafkago.RequireAll,
+		}
+	}
+	return s
+}
+
+// Next returns the next valid record, routing invalid ones to the error topic.
+func (s *Source) Next(ctx context.Context) (stream.Record, error) {
+	for {
+		msg, err := s.reader.FetchMessage(ctx)
+		if err != nil {
+			return stream.Record{}, err
+		}
+
+		doc, err := transform.ParseReader(bytes.NewReader(msg.Value))
+		if err == nil {
+			return stream.Record{
+				Document: doc,
+				Commit: func() error {
+					return s.reader.CommitMessages(context.Background(), msg)
+				},
+			}, nil
+		}
+
+		err = fmt.Errorf("topic %s partition %d offset %d: %w", msg.Topic, msg.Partition, msg.Offset, err)
+		if s.errWriter == nil {
+			return stream.Record{}, err
+		}
+		// Dead-lettered messages are not committed themselves: offsets are
+		// cumulative, so the next committed record in the partition covers them
+		if err := s.deadLetter(ctx, msg, err); err != nil {
+			return stream.Record{}, err
+		}
+	}
+}
+
+// deadLetter publishes msg to the error topic along with its failure.
+func (s *Source) deadLetter(ctx context.Context, msg kafkago.Message, cause error) error {
+	return s.errWriter.WriteMessages(ctx, kafkago.Message{
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: append(msg.Headers,
+			kafkago.Header{Key: "error", Value: []byte(cause.Error())},
+			kafkago.Header{Key: "source-topic", Value: []byte(msg.Topic)},
+		),
+	})
+}
+
+// Close leaves the consumer group and closes the error topic writer.
+func (s *Source) Close() error {
+	err := s.reader.Close()
+	if s.errWriter != nil {
+		if closeErr := s.errWriter.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// SinkOptions configures a Sink.
+type SinkOptions struct {
+	Brokers []string
+	Topic   string
+}
+
+// Sink produces transformed records to a Kafka topic, one message per record.
+type Sink struct {
+	writer *kafkago.Writer
+}
+
+// NewSink returns a Sink for the topic described by opts.
+func NewSink(opts SinkOptions) *Sink {
+	return &Sink{
+		writer: &kafkago.Writer{
+			Addr:         kafkago.TCP(opts.Brokers...),
+			Topic:        opts.Topic,
+			RequiredAcks: kafkago.RequireAll,
+			// Batches arrive complete from stream.Run, so don't wait for more
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+// Write produces every document in docs, returning once all are acknowledged.
+func (s *Sink) Write(ctx context.Context, docs []map[string]interface{}) error {
+	msgs := make([]kafkago.Message, len(docs))
+	for i, doc := range docs {
+		value, err := codec.Default().Marshal(doc)
+		if err != nil {
+			return err
+		}
+		msgs[i] = kafkago.Message{Value: value}
+	}
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+// Close flushes pending messages and closes the writer.
+func (s *Sink) Close() error {
+	return s.writer.Close()
+}
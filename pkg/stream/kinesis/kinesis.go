@@ -0,0 +1,310 @@
+// Package kinesis provides a stream.Source reading typed JSON records from
+// an Amazon Kinesis data stream.
+package kinesis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awskinesis "github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// API is the subset of the Kinesis client used by Source.
+type API interface {
+	ListShards(ctx context.Context, params *awskinesis.ListShardsInput, optFns ...func(*awskinesis.Options)) (*awskinesis.ListShardsOutput, error)
+	GetShardIterator(ctx context.Context, params *awskinesis.GetShardIteratorInput, optFns ...func(*awskinesis.Options)) (*awskinesis.GetShardIteratorOutput, error)
+	GetRecords(ctx context.Context, params *awskinesis.GetRecordsInput, optFns ...func(*awskinesis.Options)) (*awskinesis.GetRecordsOutput, error)
+}
+
+// Options configures a Source.
+type Options struct {
+	// StartPosition is where shards without a checkpoint start reading,
+	// either types.ShardIteratorTypeTrimHorizon (the default) or
+	// types.ShardIteratorTypeLatest.
+	StartPosition types.ShardIteratorType
+
+	// CheckpointFile, when set, persists the last committed sequence number
+	// of every shard so a restarted Source resumes where it left off.
+	CheckpointFile string
+
+	// CheckpointInterval bounds how often committing a record rewrites the
+	// checkpoint file. It defaults to five seconds; the file is also written
+	// after every batch of stream.Run and on Close.
+	CheckpointInterval time.Duration
+
+	// PollInterval is how long a shard waits after an empty read.
+	// It defaults to one second, the GetRecords rate limit per shard.
+	PollInterval time.Duration
+}
+
+// result is a record or error handed from a shard reader to Next.
+type result struct {
+	rec stream.Record
+	err error
+}
+
+// Source reads records from every shard of a Kinesis stream. Child shards
+// created by resharding are read once their parents have been drained, so
+// records for a partition key keep their order.
+type Source struct {
+	client     API
+	streamName string
+	opts       Options
+
+	results chan result
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu          sync.Mutex
+	checkpoints map[string]string
+	lastSave    time.Time
+}
+
+// NewSource lists the shards of streamName and starts reading them.
+func NewSource(ctx context.Context, client API, streamName string, opts Options) (*Source, error) {
+	if opts.StartPosition == "" {
+		opts.StartPosition = types.ShardIteratorTypeTrimHorizon
+	}
+	if opts.CheckpointInterval == 0 {
+		opts.CheckpointInterval = 5 * time.Second
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = time.Second
+	}
+
+	checkpoints := make(map[string]string)
+	if opts.CheckpointFile != "" {
+		var err error
+		if checkpoints, err = stream.LoadCheckpoint(opts.CheckpointFile); err != nil {
+			return nil, err
+		}
+	}
+
+	shards, err := listShards(ctx, client, streamName)
+	if err != nil {
+		return nil, err
+	}
+
+	readCtx, cancel := context.WithCancel(context.Background())
+	s := &Source{
+		client:      client,
+		streamName:  streamName,
+		opts:        opts,
+		results:     make(chan result),
+		cancel:      cancel,
+		checkpoints: checkpoints,
+		lastSave:    time.Now(),
+	}
+
+	// Each shard signals on its done channel so child shards can wait for it
+	done := make(map[string]chan struct{}, len(shards))
+	for _, shard := range shards {
+		done[aws.ToString(shard.ShardId)] = make(chan struct{})
+	}
+	for _, shard := range shards {
+		var parents []<-chan struct{}
+		for _, parentID := range []*string{shard.ParentShardId, shard.AdjacentParentShardId} {
+			if ch, ok := done[aws.ToString(parentID)]; ok {
+				parents = append(parents, ch)
+			}
+		}
+		s.wg.Add(1)
+		go s.readShard(readCtx, aws.ToString(shard.ShardId), parents, done[aws.ToString(shard.ShardId)])
+	}
+
+	// Once every shard has been closed and drained the stream is exhausted
+	go func() {
+		s.wg.Wait()
+		close(s.results)
+	}()
+	return s, nil
+}
+
+// Next returns the next record from any shard.
+func (s *Source) Next(ctx context.Context) (stream.Record, error) {
+	select {
+	case res, ok := <-s.results:
+		if !ok {
+			return stream.Record{}, io.EOF
+		}
+		return res.rec, res.err
+	case <-ctx.Done():
+		return stream.Record{}, ctx.Err()
+	}
+}
+
+// CommitBatch writes the checkpoint with the sequence numbers committed so
+// far.
+func (s *Source) CommitBatch() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saveLocked()
+}
+
+// Close stops reading and writes the final checkpoint.
+func (s *Source) Close() error {
+	s.cancel()
+	// Drain pending sends so shard readers can observe the cancellation
+	for range s.results {
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saveLocked()
+}
+
+// readShard delivers the records of a single shard in order.
+func (s *Source) readShard(ctx context.Context, shardID string, parents []<-chan struct{}, done chan struct{}) {
+	defer s.wg.Done()
+	defer close(done)
+
+	for _, parent := range parents {
+		select {
+		case <-parent:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	lastSeq := s.checkpoints[shardID]
+	s.mu.Unlock()
+
+	iterator, err := s.shardIterator(ctx, shardID, lastSeq)
+	for err == nil && iterator != nil {
+		var out *awskinesis.GetRecordsOutput
+		out, err = s.client.GetRecords(ctx, &awskinesis.GetRecordsInput{ShardIterator: iterator})
+
+		var throttled *types.ProvisionedThroughputExceededException
+		var expired *types.ExpiredIteratorException
+		switch {
+		case errors.As(err, &throttled):
+			err = s.sleep(ctx, s.opts.PollInterval)
+			continue
+		case errors.As(err, &expired):
+			iterator, err = s.shardIterator(ctx, shardID, lastSeq)
+			continue
+		case err != nil:
+			continue
+		}
+
+		for _, r := range out.Records {
+			seq := aws.ToString(r.SequenceNumber)
+			doc, parseErr := transform.ParseReader(bytes.NewReader(r.Data))
+			if parseErr != nil {
+				err = fmt.Errorf("shard %s sequence %s: %w", shardID, seq, parseErr)
+				break
+			}
+			rec := stream.Record{
+				Document: doc,
+				Commit: func() error {
+					return s.commit(shardID, seq)
+				},
+			}
+			if !s.send(ctx, result{rec: rec}) {
+				return
+			}
+			lastSeq = seq
+		}
+
+		// A nil iterator means the shard was closed and fully read
+		iterator = out.NextShardIterator
+		if err == nil && iterator != nil && len(out.Records) == 0 {
+			err = s.sleep(ctx, s.opts.PollInterval)
+		}
+	}
+
+	if err != nil && ctx.Err() == nil {
+		s.send(ctx, result{err: err})
+	}
+}
+
+// shardIterator returns an iterator positioned after lastSeq, or at the
+// configured start position when the shard has no checkpoint.
+func (s *Source) shardIterator(ctx context.Context, shardID, lastSeq string) (*string, error) {
+	in := &awskinesis.GetShardIteratorInput{
+		StreamName:        aws.String(s.streamName),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: s.opts.StartPosition,
+	}
+	if lastSeq != "" {
+		in.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		in.StartingSequenceNumber = aws.String(lastSeq)
+	}
+	out, err := s.client.GetShardIterator(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out.ShardIterator, nil
+}
+
+// send hands res to Next, reporting false if the source was closed first.
+func (s *Source) send(ctx context.Context, res result) bool {
+	select {
+	case s.results <- res:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func (s *Source) sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// commit records seq as processed for shardID, saving the checkpoint file
+// when the checkpoint interval has elapsed.
+func (s *Source) commit(shardID, seq string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[shardID] = seq
+	if time.Since(s.lastSave) < s.opts.CheckpointInterval {
+		return nil
+	}
+	return s.saveLocked()
+}
+
+// saveLocked writes the checkpoint file. s.mu must be held.
+func (s *Source) saveLocked() error {
+	if s.opts.CheckpointFile == "" {
+		return nil
+	}
+	s.lastSave = time.Now()
+	return stream.SaveCheckpoint(s.opts.CheckpointFile, s.checkpoints)
+}
+
+// listShards returns every shard of the stream, following pagination.
+func listShards(ctx context.Context, client API, streamName string) ([]types.Shard, error) {
+	var shards []types.Shard
+	in := &awskinesis.ListShardsInput{StreamName: aws.String(streamName)}
+	for {
+		out, err := client.ListShards(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, out.Shards...)
+		if out.NextToken == nil {
+			return shards, nil
+		}
+		// Follow-up pages are requested by token alone
+		in = &awskinesis.ListShardsInput{NextToken: out.NextToken}
+	}
+}
@@ -0,0 +1,322 @@
+// Package stream moves typed JSON records from a Source, through a
+// transform.Transformer, into a Sink.
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// tracer records sink writes with the global OpenTelemetry tracer provider.
+var tracer = otel.Tracer("github.com/Ravali181221/Ravali_Challenge/pkg/stream")
+
+// Record is a single typed JSON document read from a Source.
+type Record struct {
+	// Document is the parsed typed JSON document.
+	Document map[string]interface{}
+
+	// Commit, when set, is called once the transformed record has been
+	// written to the sink so the source can checkpoint its position.
+	Commit func() error
+}
+
+// Source yields typed JSON records. Next returns io.EOF once the source is
+// exhausted; unbounded sources block until a record arrives or ctx is done.
+type Source interface {
+	Next(ctx context.Context) (Record, error)
+	Close() error
+}
+
+// BatchCommitter is implemented by sources that checkpoint their position.
+// Run calls CommitBatch once every record of a batch has been written and
+// committed, so the saved checkpoint never lags output the sink has already
+// flushed and a resumed run does not write those records again.
+type BatchCommitter interface {
+	CommitBatch() error
+}
+
+// Sink receives transformed plain JSON documents in batches.
+type Sink interface {
+	Write(ctx context.Context, docs []map[string]interface{}) error
+	Close() error
+}
+
+// Options tunes Run.
+type Options struct {
+	// BatchSize is the largest number of records written to the sink at once.
+	// It defaults to 1, writing every record as soon as it arrives.
+	BatchSize int
+
+	// BatchTimeout bounds how long a partial batch waits for more records
+	// before it is written. It defaults to one second.
+	BatchTimeout time.Duration
+
+	// Reverse converts plain JSON records into typed JSON with t.Reverse
+	// instead of transforming them, e.g. to feed a DynamoDB sink.
+	Reverse bool
+}
+
+// Run reads every record from src, transforms (or reverses) it with t and writes the
+// results to sink in batches, committing each record once its batch has been
+// written and then the batch itself when src is a BatchCommitter. It stops at the end of src, on the first error, or when ctx is done.
+func Run(ctx context.Context, src Source, t *transform.Transformer, sink Sink, opts Options) error {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1
+	}
+	if opts.BatchTimeout == 0 {
+		opts.BatchTimeout = time.Second
+	}
+
+	for index := 0; ; {
+		batch, err := nextBatch(ctx, src, opts)
+		if len(batch) > 0 {
+			docs, err := transformBatch(t, index, batch, opts.Reverse)
+			if err != nil {
+				return err
+			}
+			// Records left out by the record filter are still committed
+			if len(docs) > 0 {
+				if err := writeBatch(ctx, sink, docs); err != nil {
+					return err
+				}
+			}
+			index += len(batch)
+			for _, rec := range batch {
+				if rec.Commit == nil {
+					continue
+				}
+				if err := rec.Commit(); err != nil {
+					return err
+				}
+			}
+			if bc, ok := src.(BatchCommitter); ok {
+				if err := bc.CommitBatch(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// transformBatch transforms (or reverses) the records of batch, the first of
+// which is the record at index of the stream, with t, leaving out those not
+// passing t's record filter.
+func transformBatch(t *transform.Transformer, index int, batch []Record, reverse bool) ([]map[string]interface{}, error) {
+	docs := make([]map[string]interface{}, 0, len(batch))
+	for i, rec := range batch {
+		doc := rec.Document
+		if !reverse {
+			var err error
+			if doc, err = t.TransformRecord(index+i, rec.Document); err != nil {
+				return nil, err
+			}
+		}
+		keep, err := t.KeepRecord(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !keep {
+			continue
+		}
+		if reverse {
+			doc = t.Reverse(doc)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// writeBatch writes docs to sink, recorded as a span of the trace in ctx.
+func writeBatch(ctx context.Context, sink Sink, docs []map[string]interface{}) error {
+	ctx, span := tracer.Start(ctx, "Sink.Write", trace.WithAttributes(attribute.Int("records", len(docs))))
+	defer span.End()
+	if err := sink.Write(ctx, docs); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// nextBatch reads records until the batch is full, the batch timeout expires
+// or src reports an error, which is returned along with the records read so far.
+func nextBatch(ctx context.Context, src Source, opts Options) ([]Record, error) {
+	// Block for the first record, then only wait out the batch timeout
+	rec, err := src.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch := []Record{rec}
+	if opts.BatchSize == 1 {
+		return batch, nil
+	}
+
+	batchCtx, cancel := context.WithTimeout(ctx, opts.BatchTimeout)
+	defer cancel()
+	for len(batch) < opts.BatchSize {
+		rec, err := src.Next(batchCtx)
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			break
+		}
+		if err != nil {
+			return batch, err
+		}
+		batch = append(batch, rec)
+	}
+	return batch, nil
+}
+
+// ReaderSource is a Source reading newline-delimited JSON records, or the
+// elements of a JSON array, from an io.Reader.
+type ReaderSource struct {
+	dec   codec.Decoder
+	index int
+
+	// checkpoint holds the number of records committed, for sources
+	// resuming where an earlier run stopped
+	checkpoint *Checkpointer
+	committed  int
+	exhausted  bool
+}
+
+// ReaderOptions configures a ReaderSource.
+type ReaderOptions struct {
+	// CheckpointFile, when set, persists the number of records committed so
+	// a source reading the same input again skips them. The file is removed
+	// once every record has been committed.
+	CheckpointFile string
+
+	// CheckpointInterval bounds how often Commit rewrites the checkpoint
+	// file. It defaults to five seconds; Run also saves the file after every
+	// batch.
+	CheckpointInterval time.Duration
+}
+
+// readerPosition is the checkpoint key of the number of records committed.
+const readerPosition = "records"
+
+// NewReaderSource returns a ReaderSource reading from r.
+func NewReaderSource(r io.Reader) *ReaderSource {
+	return &ReaderSource{dec: transform.NewRecordDecoder(r)}
+}
+
+// NewCheckpointedReaderSource returns a ReaderSource reading from r that
+// skips the records committed by an earlier run, as recorded in the
+// checkpoint file of opts, and records its own.
+func NewCheckpointedReaderSource(r io.Reader, opts ReaderOptions) (*ReaderSource, error) {
+	s := NewReaderSource(r)
+	if opts.CheckpointFile == "" {
+		return s, nil
+	}
+	checkpoint, err := NewCheckpointer(opts.CheckpointFile, opts.CheckpointInterval)
+	if err != nil {
+		return nil, err
+	}
+	s.checkpoint = checkpoint
+
+	if position := checkpoint.Position(readerPosition); position != "" {
+		skip, err := strconv.Atoi(position)
+		if err != nil || skip < 0 {
+			return nil, fmt.Errorf("checkpoint %s: invalid record count %q", opts.CheckpointFile, position)
+		}
+		for s.index < skip {
+			var discard json.RawMessage
+			if err := s.dec.Decode(&discard); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, &transform.RecordError{Index: s.index, Err: err}
+			}
+			s.index++
+		}
+		s.committed = s.index
+	}
+	return s, nil
+}
+
+// Next decodes the next record, returning io.EOF at the end of the input.
+func (s *ReaderSource) Next(ctx context.Context) (Record, error) {
+	if err := ctx.Err(); err != nil {
+		return Record{}, err
+	}
+
+	var doc map[string]interface{}
+	if err := s.dec.Decode(&doc); err == io.EOF {
+		s.exhausted = true
+		return Record{}, io.EOF
+	} else if err != nil {
+		return Record{}, &transform.RecordError{Index: s.index, Err: err}
+	}
+	s.index++
+	rec := Record{Document: doc}
+	if s.checkpoint != nil {
+		committed := s.index
+		rec.Commit = func() error {
+			s.committed = committed
+			return s.checkpoint.Commit(map[string]string{readerPosition: strconv.Itoa(committed)})
+		}
+	}
+	return rec, nil
+}
+
+// CommitBatch saves the checkpoint with the records committed so far.
+func (s *ReaderSource) CommitBatch() error {
+	return s.checkpoint.Save()
+}
+
+// Close saves the checkpoint, or removes it once every record has been
+// committed. The caller owns the underlying reader.
+func (s *ReaderSource) Close() error {
+	if s.exhausted && s.committed == s.index {
+		return s.checkpoint.Remove()
+	}
+	return s.checkpoint.Save()
+}
+
+// WriterSink is a Sink writing newline-delimited JSON to an io.Writer.
+type WriterSink struct {
+	bw  *bufio.Writer
+	enc codec.Encoder
+}
+
+// NewWriterSink returns a WriterSink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	bw := bufio.NewWriter(w)
+	return &WriterSink{bw: bw, enc: codec.Default().NewEncoder(bw)}
+}
+
+// Write encodes each document as a single line. Every batch is flushed so
+// downstream consumers see records as soon as they are produced.
+func (s *WriterSink) Write(ctx context.Context, docs []map[string]interface{}) error {
+	for _, doc := range docs {
+		if err := s.enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return s.bw.Flush()
+}
+
+// Close flushes any buffered output. It does not close the underlying writer.
+func (s *WriterSink) Close() error {
+	return s.bw.Flush()
+}
@@ -0,0 +1,384 @@
+package stream_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// chanSource is a Source yielding the records sent on its channel, and
+// io.EOF once the channel is closed. Committing a record appends its
+// document's id to commits.
+type chanSource struct {
+	records chan map[string]interface{}
+	commits *[]string
+}
+
+// Next returns the next record sent, blocking until one is or ctx is done.
+func (s *chanSource) Next(ctx context.Context) (stream.Record, error) {
+	select {
+	case doc, ok := <-s.records:
+		if !ok {
+			return stream.Record{}, io.EOF
+		}
+		return stream.Record{Document: doc, Commit: func() error {
+			*s.commits = append(*s.commits, doc["id"].(map[string]interface{})["S"].(string))
+			return nil
+		}}, nil
+	case <-ctx.Done():
+		return stream.Record{}, ctx.Err()
+	}
+}
+
+// Close does nothing.
+func (s *chanSource) Close() error {
+	return nil
+}
+
+// newSource returns a chanSource yielding a record with the id of each of
+// ids, then io.EOF.
+func newSource(commits *[]string, ids ...string) *chanSource {
+	s := &chanSource{records: make(chan map[string]interface{}, len(ids)), commits: commits}
+	for _, id := range ids {
+		s.records <- typed(id)
+	}
+	close(s.records)
+	return s
+}
+
+// typed returns the typed record with the id id.
+func typed(id string) map[string]interface{} {
+	return map[string]interface{}{"id": map[string]interface{}{"S": id}}
+}
+
+// batchSink is a Sink recording the ids of each batch written, along with
+// the commits made before it was written.
+type batchSink struct {
+	batches [][]string
+	commits *[]string
+	seen    []int
+	fail    int
+	onWrite func()
+}
+
+// Write records the batch, failing it when it is the batch numbered fail,
+// counting from 1.
+func (s *batchSink) Write(ctx context.Context, docs []map[string]interface{}) error {
+	if s.onWrite != nil {
+		s.onWrite()
+	}
+	if len(s.batches)+1 == s.fail {
+		return errors.New("sink unavailable")
+	}
+	var ids []string
+	for _, doc := range docs {
+		ids = append(ids, doc["id"].(string))
+	}
+	s.batches = append(s.batches, ids)
+	s.seen = append(s.seen, len(*s.commits))
+	return nil
+}
+
+// Close does nothing.
+func (s *batchSink) Close() error {
+	return nil
+}
+
+// TestRunBatches checks that records are written in batches of at most
+// BatchSize and committed once their batch has been written.
+func TestRunBatches(t *testing.T) {
+	var commits []string
+	src := newSource(&commits, "a", "b", "c", "d", "e")
+	sink := &batchSink{commits: &commits}
+	if err := stream.Run(context.Background(), src, transform.New(), sink, stream.Options{BatchSize: 2}); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	if want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}; !reflect.DeepEqual(sink.batches, want) {
+		t.Errorf("batches = %v, want %v", sink.batches, want)
+	}
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(sink.seen, want) {
+		t.Errorf("commits before each batch = %v, want %v", sink.seen, want)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(commits, want) {
+		t.Errorf("commits = %v, want %v", commits, want)
+	}
+}
+
+// TestRunBatchTimeout checks that a partial batch is written once the batch
+// timeout expires, without waiting for more records.
+func TestRunBatchTimeout(t *testing.T) {
+	var commits []string
+	src := &chanSource{records: make(chan map[string]interface{}), commits: &commits}
+	sink := &batchSink{commits: &commits}
+	written := make(chan struct{}, 1)
+	sink.onWrite = func() { written <- struct{}{} }
+
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Run(context.Background(), src, transform.New(), sink, stream.Options{BatchSize: 100, BatchTimeout: 10 * time.Millisecond})
+	}()
+	src.records <- typed("a")
+	select {
+	case <-written:
+	case <-time.After(10 * time.Second):
+		t.Fatal("partial batch was not written after the batch timeout")
+	}
+	src.records <- typed("b")
+	<-written
+	close(src.records)
+	if err := <-done; err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	if want := [][]string{{"a"}, {"b"}}; !reflect.DeepEqual(sink.batches, want) {
+		t.Errorf("batches = %v, want %v", sink.batches, want)
+	}
+}
+
+// TestRunFailures checks that records are not committed when their batch
+// fails to transform or be written.
+func TestRunFailures(t *testing.T) {
+	var commits []string
+	sink := &batchSink{commits: &commits, fail: 2}
+	err := stream.Run(context.Background(), newSource(&commits, "a", "b", "c"), transform.New(), sink, stream.Options{BatchSize: 2})
+	if err == nil || err.Error() != "sink unavailable" {
+		t.Errorf("Run = %v, want the sink error", err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(commits, want) {
+		t.Errorf("commits = %v, want %v", commits, want)
+	}
+
+	commits = nil
+	src := &chanSource{records: make(chan map[string]interface{}, 1), commits: &commits}
+	src.records <- map[string]interface{}{"id": map[string]interface{}{"N": "x"}}
+	close(src.records)
+	tr := transform.New(transform.WithOnError(transform.OnErrorFail))
+	if err := stream.Run(context.Background(), src, tr, &batchSink{commits: &commits}, stream.Options{}); err == nil {
+		t.Error("Run of an invalid record succeeded, want an error")
+	}
+	if len(commits) != 0 {
+		t.Errorf("commits = %v, want none", commits)
+	}
+}
+
+// TestRunFilter checks that records left out by the record filter are
+// committed without being written.
+func TestRunFilter(t *testing.T) {
+	var commits []string
+	sink := &batchSink{commits: &commits}
+	tr := transform.New(transform.WithRecordFilter(func(record map[string]interface{}) (bool, error) {
+		return record["id"] != "b", nil
+	}))
+	if err := stream.Run(context.Background(), newSource(&commits, "a", "b", "c"), tr, sink, stream.Options{}); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	if want := [][]string{{"a"}, {"c"}}; !reflect.DeepEqual(sink.batches, want) {
+		t.Errorf("batches = %v, want %v", sink.batches, want)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(commits, want) {
+		t.Errorf("commits = %v, want %v", commits, want)
+	}
+}
+
+// TestReaderSourceResume checks that a checkpointed ReaderSource read again
+// after a failure skips the records committed by the first run, and that
+// the checkpoint is removed once every record has been committed.
+func TestReaderSourceResume(t *testing.T) {
+	input := `{"id":{"S":"a"}}` + "\n" + `{"id":{"S":"b"}}` + "\n" + `{"id":{"S":"c"}}` + "\n" + `{"id":{"S":"d"}}` + "\n" + `{"id":{"S":"e"}}` + "\n"
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	opts := stream.ReaderOptions{CheckpointFile: checkpoint}
+
+	// The second batch fails, after the first was written and committed
+	var out bytes.Buffer
+	src, err := stream.NewCheckpointedReaderSource(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &failingSink{WriterSink: stream.NewWriterSink(&out), fail: 2}
+	if err := stream.Run(context.Background(), src, transform.New(), sink, stream.Options{BatchSize: 2}); err == nil {
+		t.Fatal("Run succeeded, want the sink error")
+	}
+	if err := src.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	positions, err := stream.LoadCheckpoint(checkpoint)
+	if err != nil || !reflect.DeepEqual(positions, map[string]string{"records": "2"}) {
+		t.Fatalf("checkpoint = %v, %v, want 2 records", positions, err)
+	}
+
+	src, err = stream.NewCheckpointedReaderSource(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Run(context.Background(), src, transform.New(), stream.NewWriterSink(&out), stream.Options{BatchSize: 2}); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	if err := src.Close(); err != nil {
+		t.Fatalf("Close = %v", err)
+	}
+	want := `{"id":"a"}` + "\n" + `{"id":"b"}` + "\n" + `{"id":"c"}` + "\n" + `{"id":"d"}` + "\n" + `{"id":"e"}` + "\n"
+	if out.String() != want {
+		t.Errorf("wrote\n%s\nwant\n%s", out.String(), want)
+	}
+	if _, err := os.Stat(checkpoint); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("checkpoint file left behind: %v", err)
+	}
+}
+
+// TestReaderSourceCheckpointsBatches checks that the checkpoint is saved as
+// soon as a batch has been written, well within the checkpoint interval, so
+// a run killed before Close resumes after the records it already wrote.
+func TestReaderSourceCheckpointsBatches(t *testing.T) {
+	input := `{"id":{"S":"a"}}` + "\n" + `{"id":{"S":"b"}}` + "\n" + `{"id":{"S":"c"}}` + "\n" + `{"id":{"S":"d"}}` + "\n" + `{"id":{"S":"e"}}` + "\n"
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	opts := stream.ReaderOptions{CheckpointFile: checkpoint, CheckpointInterval: time.Hour}
+
+	// The process dies during the third batch, leaving Close uncalled
+	var out bytes.Buffer
+	src, err := stream.NewCheckpointedReaderSource(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &failingSink{WriterSink: stream.NewWriterSink(&out), fail: 3}
+	if err := stream.Run(context.Background(), src, transform.New(), sink, stream.Options{BatchSize: 2}); err == nil {
+		t.Fatal("Run succeeded, want the sink error")
+	}
+	positions, err := stream.LoadCheckpoint(checkpoint)
+	if err != nil || !reflect.DeepEqual(positions, map[string]string{"records": "4"}) {
+		t.Fatalf("checkpoint = %v, %v, want the 4 records written", positions, err)
+	}
+
+	src, err = stream.NewCheckpointedReaderSource(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Run(context.Background(), src, transform.New(), stream.NewWriterSink(&out), stream.Options{BatchSize: 2}); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	want := `{"id":"a"}` + "\n" + `{"id":"b"}` + "\n" + `{"id":"c"}` + "\n" + `{"id":"d"}` + "\n" + `{"id":"e"}` + "\n"
+	if out.String() != want {
+		t.Errorf("wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+// failingSink is a WriterSink failing the batch numbered fail, counting
+// from 1.
+type failingSink struct {
+	*stream.WriterSink
+	fail, batches int
+}
+
+// Write writes docs unless it is the failing batch.
+func (s *failingSink) Write(ctx context.Context, docs []map[string]interface{}) error {
+	s.batches++
+	if s.batches == s.fail {
+		return errors.New("sink unavailable")
+	}
+	return s.WriterSink.Write(ctx, docs)
+}
+
+// TestReaderSourceInvalidCheckpoint checks that a checkpoint that is not a
+// record count is rejected.
+func TestReaderSourceInvalidCheckpoint(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	for _, content := range []string{`{"records":"x"}`, `{"records":"-1"}`, `not json`} {
+		if err := os.WriteFile(checkpoint, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.NewCheckpointedReaderSource(strings.NewReader(""), stream.ReaderOptions{CheckpointFile: checkpoint}); err == nil {
+			t.Errorf("checkpoint %s accepted, want an error", content)
+		}
+	}
+}
+
+// TestCheckpointer checks that commits are saved at most once per interval
+// and that Save and Remove write and delete the file.
+func TestCheckpointer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if positions, err := stream.LoadCheckpoint(path); err != nil || len(positions) != 0 {
+		t.Fatalf("LoadCheckpoint of a missing file = %v, %v, want no positions", positions, err)
+	}
+
+	c, err := stream.NewCheckpointer(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit(map[string]string{"shard-1": "10"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("commit within the interval saved the checkpoint: %v", err)
+	}
+	if err := c.Commit(map[string]string{"shard-2": "20"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = stream.NewCheckpointer(path, time.Nanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Position("shard-1") != "10" || c.Position("shard-2") != "20" {
+		t.Errorf("positions %q and %q, want 10 and 20", c.Position("shard-1"), c.Position("shard-2"))
+	}
+	time.Sleep(time.Millisecond)
+	if err := c.Commit(map[string]string{"shard-1": "11"}); err != nil {
+		t.Fatal(err)
+	}
+	if positions, err := stream.LoadCheckpoint(path); err != nil || positions["shard-1"] != "11" {
+		t.Errorf("checkpoint after the interval = %v, %v, want shard-1 at 11", positions, err)
+	}
+
+	if err := c.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Remove left the checkpoint: %v", err)
+	}
+
+	// A nil Checkpointer does nothing
+	var none *stream.Checkpointer
+	if none.Position("x") != "" || none.Commit(map[string]string{"x": "1"}) != nil || none.Save() != nil || none.Remove() != nil {
+		t.Error("nil Checkpointer did something")
+	}
+}
+
+// flushRecorder records the content of each write made to it.
+type flushRecorder struct {
+	writes []string
+}
+
+// Write records p.
+func (r *flushRecorder) Write(p []byte) (int, error) {
+	r.writes = append(r.writes, string(p))
+	return len(p), nil
+}
+
+// TestWriterSinkFlushesBatches checks that every batch reaches the
+// underlying writer as soon as it is written.
+func TestWriterSinkFlushesBatches(t *testing.T) {
+	var w flushRecorder
+	sink := stream.NewWriterSink(&w)
+	for _, id := range []string{"a", "b"} {
+		if err := sink.Write(context.Background(), []map[string]interface{}{{"id": id}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{`{"id":"a"}` + "\n", `{"id":"b"}` + "\n"}; !reflect.DeepEqual(w.writes, want) {
+		t.Errorf("writes = %q, want %q", w.writes, want)
+	}
+	if err := sink.Close(); err != nil || len(w.writes) != 2 {
+		t.Errorf("Close = %v after %d writes", err, len(w.writes))
+	}
+}
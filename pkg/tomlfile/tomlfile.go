@@ -0,0 +1,120 @@
+// Package tomlfile writes a transformed document as TOML, for configuration
+// tooling that reads TOML rather than JSON.
+package tomlfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// ErrUnrepresentable is wrapped by the errors of values TOML cannot hold.
+var ErrUnrepresentable = errors.New("cannot be represented in TOML")
+
+// Writer writes a single document as TOML.
+type Writer struct {
+	w       io.Writer
+	written bool
+}
+
+// NewWriter returns a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes the decoded JSON document doc as a TOML document. Objects
+// become tables and arrays of objects arrays of tables. It fails, naming the
+// value by its JSON Pointer, for null, integers beyond 64 bits, arrays mixing
+// types of values and a second document, as TOML holds one.
+func (w *Writer) Write(doc map[string]interface{}) error {
+	if w.written {
+		return fmt.Errorf("a second document %w, as a TOML file holds one", ErrUnrepresentable)
+	}
+	w.written = true
+	table, err := convert(doc, "")
+	if err != nil {
+		return err
+	}
+	enc := toml.NewEncoder(w.w)
+	enc.Indent = ""
+	return enc.Encode(table)
+}
+
+// Close does nothing, as every document is written at once. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	return nil
+}
+
+// convert returns the decoded JSON value v, found at the JSON Pointer path,
+// as the TOML encoder expects it.
+func convert(v interface{}, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, fmt.Errorf("%s: null %w", schemautil.DescribePointer(path), ErrUnrepresentable)
+	case string, bool:
+		return v, nil
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			i, err := v.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("%s: integer %s %w", schemautil.DescribePointer(path), v, ErrUnrepresentable)
+			}
+			return i, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: number %s %w", schemautil.DescribePointer(path), v, ErrUnrepresentable)
+		}
+		return f, nil
+	case map[string]interface{}:
+		table := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			converted, err := convert(value, schemautil.JoinPointer(path, key))
+			if err != nil {
+				return nil, err
+			}
+			table[key] = converted
+		}
+		return table, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			converted, err := convert(elem, fmt.Sprintf("%s/%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 && kind(converted) != kind(list[0]) {
+				return nil, fmt.Errorf("%s: array mixing %s and %s values %w", schemautil.DescribePointer(path), kind(list[0]), kind(converted), ErrUnrepresentable)
+			}
+			list[i] = converted
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("%s: value of type %T %w", schemautil.DescribePointer(path), v, ErrUnrepresentable)
+	}
+}
+
+// kind names the TOML type of the converted value v.
+func kind(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case map[string]interface{}:
+		return "table"
+	default:
+		return "array"
+	}
+}
@@ -0,0 +1,109 @@
+package tomlfile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/tomlfile"
+)
+
+// decode returns the JSON document doc decoded as the CLI decodes
+// transformed output, with numbers as json.Number.
+func decode(t *testing.T, doc string) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		t.Fatalf("decode %s: %v", doc, err)
+	}
+	return out
+}
+
+// TestWriter checks the TOML written for a document, and the errors of
+// values TOML cannot hold.
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "keys of arrays of tables kept per record",
+			doc:  `{"items":[{"id":1,"name":"a"},{"id":2,"email":"b@c"}]}`,
+			want: "[[items]]\nid = 1\nname = \"a\"\n\n[[items]]\nemail = \"b@c\"\nid = 2\n",
+		},
+		{
+			name: "nested objects as tables",
+			doc:  `{"id":1,"address":{"city":"Paris","geo":{"lat":1.5}}}`,
+			want: "id = 1\n\n[address]\ncity = \"Paris\"\n[address.geo]\nlat = 1.5\n",
+		},
+		{
+			name: "largest 64-bit integer kept exact",
+			doc:  `{"id":9223372036854775807}`,
+			want: "id = 9223372036854775807\n",
+		},
+		{
+			name:    "integer beyond 64 bits",
+			doc:     `{"a":{"id":9223372036854775808}}`,
+			wantErr: "/a/id: integer 9223372036854775808 cannot be represented in TOML",
+		},
+		{
+			name: "empty document",
+			doc:  `{}`,
+			want: "",
+		},
+		{
+			name: "empty table and array",
+			doc:  `{"a":{},"e":[]}`,
+			want: "e = []\n\n[a]\n",
+		},
+		{
+			name:    "null",
+			doc:     `{"a":[{"b":null}]}`,
+			wantErr: "/a/0/b: null cannot be represented in TOML",
+		},
+		{
+			name:    "mixed array",
+			doc:     `{"a":[1,"x"]}`,
+			wantErr: "/a: array mixing integer and string values cannot be represented in TOML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := tomlfile.NewWriter(&buf)
+			err := w.Write(decode(t, tt.doc))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || !errors.Is(err, tomlfile.ErrUnrepresentable) {
+					t.Fatalf("Write = %v, want %q wrapping ErrUnrepresentable", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write = %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("wrote\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestWriterSecondDocument checks that a Writer refuses a second document.
+func TestWriterSecondDocument(t *testing.T) {
+	w := tomlfile.NewWriter(&bytes.Buffer{})
+	if err := w.Write(decode(t, `{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(decode(t, `{"a":2}`)); !errors.Is(err, tomlfile.ErrUnrepresentable) {
+		t.Errorf("second Write = %v, want ErrUnrepresentable", err)
+	}
+}
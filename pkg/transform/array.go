@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// AcceptArray returns a ParseOption also accepting input whose root is an
+// array of typed documents, as written by many export tools. The documents
+// of such an array are stored in *items and the parse functions return a nil
+// map with a nil error; *items is set to nil when the root is an object.
+func AcceptArray(items *[]map[string]interface{}) ParseOption {
+	return func(c *parseConfig) {
+		c.items = items
+	}
+}
+
+// isArray reports whether the JSON text data starts with an array.
+func isArray(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
+
+// NewRecordDecoder returns a Decoder reading the records of r, which holds
+// either newline-delimited JSON objects or a single array of them, decoded
+// one element at a time. Text is read through NewTextReader.
+func NewRecordDecoder(r io.Reader) codec.Decoder {
+	br := bufio.NewReader(NewTextReader(r))
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			// Leave the error to the first Decode
+			break
+		}
+		if b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+			continue
+		}
+		br.UnreadByte()
+		if b == '[' {
+			dec := json.NewDecoder(br)
+			dec.UseNumber()
+			return &arrayDecoder{dec: dec}
+		}
+		break
+	}
+	return codec.Default().NewDecoder(br)
+}
+
+// arrayDecoder decodes the elements of a JSON array one at a time.
+type arrayDecoder struct {
+	dec     *json.Decoder
+	started bool
+	done    bool
+}
+
+// Decode reads the next element into v, returning io.EOF after the last one.
+func (a *arrayDecoder) Decode(v interface{}) error {
+	if a.done {
+		return io.EOF
+	}
+	if !a.started {
+		a.started = true
+		if _, err := a.dec.Token(); err != nil {
+			return err
+		}
+	}
+	if a.dec.More() {
+		return a.dec.Decode(v)
+	}
+
+	a.done = true
+	if tok, err := a.dec.Token(); err == io.EOF {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	} else if tok != json.Delim(']') {
+		return ErrTrailingData
+	}
+	if _, err := a.dec.Token(); err != io.EOF {
+		return ErrTrailingData
+	}
+	return io.EOF
+}
@@ -0,0 +1,100 @@
+package transform_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// benchItem is a typed item shaped like a DynamoDB export record, with
+// scalars, a nested map and lists of typed and nested values.
+const benchItem = `{
+	"id": {"S": "3f9a6c1e-8d2b-4b7e-9c3a-1f2e4d5c6b7a"},
+	"createdAt": {"S": "2024-03-01T12:34:56Z"},
+	"amount": {"N": "1234.56"},
+	"count": {"N": "42"},
+	"active": {"BOOL": true},
+	"deleted": {"NULL": true},
+	"tags": {"SS": ["a", "b", "c"]},
+	"address": {"M": {
+		"street": {"S": "1 Main St"},
+		"city": {"S": "Springfield"},
+		"zip": {"N": "12345"}
+	}},
+	"items": {"L": [
+		{"M": {"sku": {"S": "A-1"}, "qty": {"N": "2"}, "price": {"N": "9.99"}}},
+		{"M": {"sku": {"S": "B-2"}, "qty": {"N": "1"}, "price": {"N": "19.5"}}},
+		{"S": "note"},
+		{"N": "7"}
+	]}
+}`
+
+// benchDocument returns a freshly decoded copy of benchItem.
+func benchDocument(b *testing.B) map[string]interface{} {
+	b.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(benchItem), &doc); err != nil {
+		b.Fatal(err)
+	}
+	return doc
+}
+
+// BenchmarkTransformJSON measures transforming one item with the default
+// rules.
+func BenchmarkTransformJSON(b *testing.B) {
+	doc := benchDocument(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		transform.TransformJSON(doc)
+	}
+}
+
+// BenchmarkFormatList measures transforming a list of typed and nested
+// elements.
+func BenchmarkFormatList(b *testing.B) {
+	list := benchDocument(b)["items"].(map[string]interface{})["L"]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		transform.FormatList(list)
+	}
+}
+
+// BenchmarkMarshalSorted measures encoding a transformed item with sorted
+// keys.
+func BenchmarkMarshalSorted(b *testing.B) {
+	out, _ := transform.TransformJSON(benchDocument(b))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := transform.MarshalSorted(out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTransformNDJSON measures streaming a thousand records through
+// TransformNDJSON.
+func BenchmarkTransformNDJSON(b *testing.B) {
+	// One compact record per line, 1000 records per stream
+	var line bytes.Buffer
+	if err := json.Compact(&line, []byte(benchItem)); err != nil {
+		b.Fatal(err)
+	}
+	line.WriteByte('\n')
+	input := bytes.Repeat(line.Bytes(), 1000)
+
+	t := transform.New()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := t.TransformNDJSON(bytes.NewReader(input), io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BinaryFormat selects how decoded B values are written to the output.
+type BinaryFormat int
+
+const (
+	// BinaryBase64 passes validated base64 strings through unchanged.
+	BinaryBase64 BinaryFormat = iota
+	// BinaryHex re-encodes the decoded bytes as a hex string.
+	BinaryHex
+	// BinaryFile writes the raw bytes to a side file and outputs its path.
+	BinaryFile
+)
+
+// ParseBinaryFormat parses the name of a BinaryFormat: base64, hex or file.
+func ParseBinaryFormat(name string) (BinaryFormat, error) {
+	switch name {
+	case "base64":
+		return BinaryBase64, nil
+	case "hex":
+		return BinaryHex, nil
+	case "file":
+		return BinaryFile, nil
+	default:
+		return 0, fmt.Errorf("unknown binary format %q", name)
+	}
+}
+
+// WithBinaryFormat sets how B values are written to the output.
+func WithBinaryFormat(format BinaryFormat) Option {
+	return func(t *Transformer) {
+		t.binaryFormat = format
+	}
+}
+
+// WithBinaryDir sets the directory side files are written to in BinaryFile mode.
+func WithBinaryDir(dir string) Option {
+	return func(t *Transformer) {
+		t.binaryDir = dir
+	}
+}
+
+// FormatBinary transforms base64 encoded binary values, returning nil for a
+// value that is not a valid base64 string.
+func FormatBinary(v interface{}) interface{} {
+	return std.formatBinary(v)
+}
+
+// formatBinary decodes a base64 value and writes it out in the configured
+// format. Invalid base64, and in BinaryFile mode a side file that cannot be
+// written, make the value invalid, so the error mode decides whether it
+// becomes null or is omitted.
+func (t *Transformer) formatBinary(v interface{}) interface{} {
+	b64, ok := v.(string)
+	if !ok {
+		return t.invalid(&TypeError{Type: "B", Want: "a string", Value: v}, nil)
+	}
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return t.invalid(invalidValue(ErrInvalidBinary, "invalid base64: %v", err), nil)
+	}
+	if t.extendedJSON {
+		return extendedBinary(data)
+	}
+
+	switch t.binaryFormat {
+	case BinaryHex:
+		return hex.EncodeToString(data)
+	case BinaryFile:
+		path, err := t.writeBinaryFile(data)
+		if err != nil {
+			return t.invalid(fmt.Errorf("write binary file: %w", err), nil)
+		}
+		return path
+	default:
+		return b64
+	}
+}
+
+// writeBinaryFile stores data in the binary directory under its content hash,
+// so identical values share one file, and returns the file path.
+func (t *Transformer) writeBinaryFile(data []byte) (string, error) {
+	dir := t.binaryDir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	path := filepath.Join(dir, hex.EncodeToString(sum[:])+".bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,52 @@
+package transform_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// TestBinaryFileWriteFailure checks that a B value whose side file cannot be
+// written is invalid, so the error mode decides what becomes of it.
+func TestBinaryFileWriteFailure(t *testing.T) {
+	// A regular file where the binary directory should be makes every write fail
+	blocked := filepath.Join(t.TempDir(), "blocked")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	in := map[string]interface{}{"a": map[string]interface{}{"B": "aGk="}}
+
+	tests := []struct {
+		name string
+		mode transform.ErrorMode
+		want map[string]interface{}
+	}{
+		{name: "default", mode: transform.OnErrorDefault, want: map[string]interface{}{"a": nil}},
+		{name: "skip", mode: transform.OnErrorSkip, want: map[string]interface{}{}},
+		{name: "fail", mode: transform.OnErrorFail, want: map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := transform.New(transform.WithBinaryFormat(transform.BinaryFile), transform.WithBinaryDir(filepath.Join(blocked, "bin")), transform.WithOnError(tt.mode))
+			out := tr.Transform(in)
+			if len(out) != len(tt.want) {
+				t.Fatalf("Transform = %v, want %v", out, tt.want)
+			}
+			for key, want := range tt.want {
+				if got, ok := out[key]; !ok || got != want {
+					t.Errorf("Transform = %v, want %v", out, tt.want)
+				}
+			}
+		})
+	}
+
+	// Writable directories receive the file and the output its path
+	dir := t.TempDir()
+	out := transform.New(transform.WithBinaryFormat(transform.BinaryFile), transform.WithBinaryDir(dir)).Transform(in)
+	path, _ := out["a"].(string)
+	if data, err := os.ReadFile(path); err != nil || string(data) != "hi" {
+		t.Errorf("Transform = %v, file holds %q, %v", out, data, err)
+	}
+}
@@ -0,0 +1,66 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// TransformBytes parses the typed JSON document in, transforms it with a
+// Transformer configured by opts and returns the output encoded with sorted
+// keys, so equal inputs give equal outputs. Malformed input, invalid values
+// under OnErrorFail and even panicking custom rules are reported as errors
+// rather than panics, so it is safe on untrusted data and as a fuzzing
+// target.
+func TransformBytes(in []byte, opts ...Option) ([]byte, error) {
+	output, err := transformSafely(in, opts)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalSorted(output)
+}
+
+// TransformInto transforms the typed JSON document in like TransformBytes
+// and stores the plain result in a value of type T, such as a struct with
+// json tags, in one step:
+//
+//	user, err := transform.TransformInto[User](item)
+//
+// The result is encoded to JSON once and decoded into T with
+// encoding/json. Numbers keep their precision on the way, so int64 and
+// uint64 fields receive exact values and interface{} fields receive
+// json.Number. A result that does not fit T, such as a string for an int
+// field, is reported as an error wrapping a *json.UnmarshalTypeError.
+func TransformInto[T any](in []byte, opts ...Option) (T, error) {
+	var v T
+	output, err := transformSafely(in, opts)
+	if err != nil {
+		return v, err
+	}
+	out, err := MarshalSorted(output)
+	if err != nil {
+		return v, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return v, fmt.Errorf("decode transformed document: %w", err)
+	}
+	return v, nil
+}
+
+// transformSafely parses and transforms the typed JSON document in with a
+// Transformer configured by opts, returning panics as errors.
+func transformSafely(in []byte, opts []Option) (output map[string]interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			output, err = nil, fmt.Errorf("transform failed: %v", r)
+		}
+	}()
+
+	doc, err := ParseReader(bytes.NewReader(in))
+	if err != nil {
+		return nil, err
+	}
+	return New(opts...).TransformChecked(doc)
+}
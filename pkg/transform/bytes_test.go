@@ -0,0 +1,216 @@
+package transform_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// TestTransformBytesInvalidValues checks that values of the wrong kind are
+// reported at their pointer with OnErrorFail, and replaced with their
+// type's default otherwise, rather than panicking.
+func TestTransformBytesInvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		pointer string
+		want    string
+	}{
+		{name: "non-string S", in: `{"a":{"S":5}}`, pointer: "/a", want: `{"a":""}`},
+		{name: "non-numeric N", in: `{"a":{"N":"zz"}}`, pointer: "/a", want: `{"a":0}`},
+		{name: "non-string N", in: `{"a":{"N":true}}`, pointer: "/a", want: `{"a":0}`},
+		{name: "non-list L", in: `{"a":{"L":"x"}}`, pointer: "/a", want: `{"a":[]}`},
+		{name: "non-object M", in: `{"a":{"M":[1]}}`, pointer: "/a", want: `{"a":{}}`},
+		{name: "non-list SS", in: `{"a":{"SS":"x"}}`, pointer: "/a", want: `{"a":[]}`},
+		{name: "non-boolean BOOL", in: `{"a":{"BOOL":"x"}}`, pointer: "/a", want: `{"a":false}`},
+		{name: "invalid base64 B", in: `{"a":{"B":"!!"}}`, pointer: "/a", want: `{"a":null}`},
+		{name: "invalid N in L", in: `{"a":{"L":[{"N":"x"}]}}`, pointer: "/a/0", want: `{"a":[0]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := transform.TransformBytes([]byte(tt.in), transform.WithOnError(transform.OnErrorFail))
+			var pathErr *transform.PathError
+			if !errors.As(err, &pathErr) {
+				t.Fatalf("TransformBytes(%s) with OnErrorFail = %v, want a *PathError", tt.in, err)
+			}
+			if pathErr.Pointer != tt.pointer {
+				t.Errorf("TransformBytes(%s) failed at %q, want %q", tt.in, pathErr.Pointer, tt.pointer)
+			}
+
+			out, err := transform.TransformBytes([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("TransformBytes(%s) = %v", tt.in, err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("TransformBytes(%s) = %s, want %s", tt.in, out, tt.want)
+			}
+		})
+	}
+}
+
+// TestTransformBytesNonFiniteNumbers checks that N values strconv parses
+// but JSON cannot represent are invalid numbers in every error mode, rather
+// than failing to marshal the whole document.
+func TestTransformBytesNonFiniteNumbers(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []transform.Option
+		want string
+	}{
+		{name: "default", want: `{"a":0,"b":1}`},
+		{name: "skip", opts: []transform.Option{transform.WithOnError(transform.OnErrorSkip)}, want: `{"b":1}`},
+		{name: "strict", opts: []transform.Option{transform.WithStrict(true)}, want: `{"b":1}`},
+		{name: "omit invalid numbers", opts: []transform.Option{transform.WithOmitInvalidNumbers(true)}, want: `{"b":1}`},
+		{name: "float numbers", opts: []transform.Option{transform.WithFloatNumbers(true)}, want: `{"a":0,"b":1}`},
+	}
+	for _, num := range []string{"NaN", "Infinity", "-Inf", "+inf"} {
+		in := `{"a":{"N":"` + num + `"},"b":{"N":"1"}}`
+		for _, tt := range tests {
+			t.Run(num+"/"+tt.name, func(t *testing.T) {
+				out, err := transform.TransformBytes([]byte(in), tt.opts...)
+				if err != nil {
+					t.Fatalf("TransformBytes(%s) = %v", in, err)
+				}
+				if string(out) != tt.want {
+					t.Errorf("TransformBytes(%s) = %s, want %s", in, out, tt.want)
+				}
+			})
+		}
+		t.Run(num+"/fail", func(t *testing.T) {
+			_, err := transform.TransformBytes([]byte(in), transform.WithOnError(transform.OnErrorFail))
+			var pathErr *transform.PathError
+			if !errors.Is(err, transform.ErrUnparsableNumber) || !errors.As(err, &pathErr) || pathErr.Pointer != "/a" {
+				t.Errorf("TransformBytes(%s) with OnErrorFail = %v, want an ErrUnparsableNumber at /a", in, err)
+			}
+		})
+	}
+}
+
+// TestTransformBytesMalformed checks that input that is not a JSON object is
+// reported as an error.
+func TestTransformBytesMalformed(t *testing.T) {
+	for _, in := range []string{``, `{`, `[1]`, `"x"`, `{"a":}`, `{"a":{"S":"x"}} trailing`} {
+		if out, err := transform.TransformBytes([]byte(in)); err == nil {
+			t.Errorf("TransformBytes(%q) = %s, want an error", in, out)
+		}
+	}
+}
+
+// FuzzTransformBytes checks that TransformBytes never panics and that what
+// it returns is valid JSON, the same for the same input.
+func FuzzTransformBytes(f *testing.F) {
+	for _, seed := range []string{
+		`{"a":{"S":"x"},"b":{"N":"1.5"},"c":{"BOOL":true},"d":{"NULL":true}}`,
+		`{"a":{"S":"2024-03-01T12:34:56Z"},"b":{"N":"9007199254740993"},"c":{"N":"-1e400"}}`,
+		`{"a":{"M":{"b":{"L":[{"S":"x"},{"M":{"c":{"N":"1"}}},3,null]}}}}`,
+		`{"a":{"SS":["x","x"]},"b":{"NS":["1","zz"]},"c":{"BS":["AA==","!!"]},"d":{"B":"aGk="}}`,
+		`{"a":{"S":5},"b":{"N":true},"c":{"L":"x"},"d":{"M":[1]},"e":{"BOOL":"x"}}`,
+		`{"a":{"S":"x","N":"1"},"b":{},"c":{"X":"y"}," ":{"S":"x"},"d":"plain"}`,
+		`{"a":{"L":[[[[{"N":"1"}]]]]}}`,
+		`{"a":{"S":"x"},"a":{"N":"1"}}`,
+		`{"a":{"S":"\u0000\ud800<&>"}}`,
+		`{"a":{"S":"x"}`,
+		`[{"a":{"S":"x"}}]`,
+		`null`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, in []byte) {
+		for _, mode := range []transform.ErrorMode{transform.OnErrorDefault, transform.OnErrorSkip, transform.OnErrorFail} {
+			out, err := transform.TransformBytes(in, transform.WithOnError(mode))
+			if err != nil {
+				continue
+			}
+			if !json.Valid(out) {
+				t.Fatalf("TransformBytes(%q) = %q, which is not valid JSON", in, out)
+			}
+			again, err := transform.TransformBytes(in, transform.WithOnError(mode))
+			if err != nil || !bytes.Equal(out, again) {
+				t.Fatalf("TransformBytes(%q) = %q, then %q, %v", in, out, again, err)
+			}
+		}
+	})
+}
+
+// TestTransformInto checks that transformed values are stored in a struct
+// with exact numbers, and that values that do not fit are reported with
+// their field.
+func TestTransformInto(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Base struct {
+		ID int64 `json:"id"`
+	}
+	type User struct {
+		Base
+		Name    string                 `json:"name"`
+		Big     uint64                 `json:"big"`
+		Score   float64                `json:"score"`
+		Active  *bool                  `json:"active"`
+		Tags    []string               `json:"tags"`
+		Data    []byte                 `json:"data"`
+		Address *Address               `json:"address"`
+		Extra   map[string]interface{} `json:"extra"`
+		Count   int                    `json:"count,string"`
+		Ignored string                 `json:"-"`
+	}
+	in := `{
+		"id": {"N": "9007199254740993"},
+		"NAME": {"S": "Ada"},
+		"big": {"N": "18446744073709551615"},
+		"score": {"N": "1.5"},
+		"active": {"BOOL": true},
+		"tags": {"SS": ["a", "b"]},
+		"data": {"B": "aGk="},
+		"address": {"M": {"city": {"S": "Paris"}}},
+		"extra": {"M": {"n": {"N": "7"}}},
+		"count": {"S": "12"},
+		"Ignored": {"S": "x"}
+	}`
+	user, err := transform.TransformInto[User]([]byte(in))
+	if err != nil {
+		t.Fatalf("TransformInto = %v", err)
+	}
+	if user.ID != 9007199254740993 || user.Name != "Ada" || user.Big != 18446744073709551615 || user.Score != 1.5 {
+		t.Errorf("TransformInto scalars = %+v", user)
+	}
+	if user.Active == nil || !*user.Active || len(user.Tags) != 2 || string(user.Data) != "hi" || user.Count != 12 || user.Ignored != "" {
+		t.Errorf("TransformInto = %+v", user)
+	}
+	if user.Address == nil || user.Address.City != "Paris" || user.Extra["n"] != json.Number("7") {
+		t.Errorf("TransformInto nested = %+v, %+v", user.Address, user.Extra)
+	}
+
+	tests := []struct {
+		in    string
+		field string
+	}{
+		{in: `{"name": {"N": "1"}}`, field: "name"},
+		{in: `{"score": {"S": "x"}}`, field: "score"},
+		{in: `{"id": {"N": "1.5"}}`, field: "id"},
+		{in: `{"big": {"N": "-1"}}`, field: "big"},
+		{in: `{"tags": {"L": [{"S": "a"}, {"N": "2"}]}}`, field: "tags.1"},
+		{in: `{"address": {"M": {"city": {"BOOL": true}}}}`, field: "address.city"},
+	}
+	for _, tt := range tests {
+		_, err := transform.TransformInto[User]([]byte(tt.in))
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Errorf("TransformInto(%s) = %v, want a *json.UnmarshalTypeError", tt.in, err)
+			continue
+		}
+		if typeErr.Field != tt.field {
+			t.Errorf("TransformInto(%s) failed at %q, want %q", tt.in, typeErr.Field, tt.field)
+		}
+	}
+
+	if _, err := transform.TransformInto[struct{ A int8 }]([]byte(`{"A": {"N": "300"}}`)); err == nil {
+		t.Error("TransformInto of 300 into an int8 succeeded, want an error")
+	}
+}
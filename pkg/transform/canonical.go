@@ -0,0 +1,213 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// MarshalCanonical encodes v in the JSON Canonicalization Scheme of RFC 8785,
+// so equal values always encode to the same bytes and outputs can be hashed,
+// signed and compared byte for byte: no whitespace, object keys sorted by
+// their UTF-16 code units, strings with only the escapes JSON requires and
+// numbers formatted as ECMAScript does. As JCS numbers are IEEE 754 doubles,
+// integers it would write as another integer, as happens beyond 2^53, are
+// rejected with a *PathError wrapping ErrInexactNumber rather than silently
+// rounded, so a hash or signature never covers a changed value; NaN and
+// infinities are rejected too.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v, ""); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeCanonical appends the canonical encoding of v, found at the JSON
+// Pointer path, to buf.
+func writeCanonical(buf *bytes.Buffer, v interface{}, path string) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case string:
+		writeCanonicalString(buf, val)
+	case json.Number:
+		f, err := strconv.ParseFloat(val.String(), 64)
+		if err != nil && !math.IsInf(f, 0) {
+			return &PathError{Pointer: path, Err: fmt.Errorf("invalid number %q", val)}
+		}
+		if n, ok := new(big.Int).SetString(val.String(), 10); ok && !exactInteger(n, f) {
+			return inexactNumber(path, val.String())
+		}
+		return writeCanonicalNumber(buf, f, path)
+	case float64:
+		return writeCanonicalNumber(buf, val, path)
+	case int64:
+		if !exactInteger(big.NewInt(val), float64(val)) {
+			return inexactNumber(path, strconv.FormatInt(val, 10))
+		}
+		return writeCanonicalNumber(buf, float64(val), path)
+	case int:
+		if !exactInteger(big.NewInt(int64(val)), float64(val)) {
+			return inexactNumber(path, strconv.Itoa(val))
+		}
+		return writeCanonicalNumber(buf, float64(val), path)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, val[k], schemautil.JoinPointer(path, k)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item, schemautil.JoinPointer(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		// Other values, such as those of custom rules, are canonicalized
+		// through their plain JSON encoding
+		data, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var plain interface{}
+		if err := dec.Decode(&plain); err != nil {
+			return err
+		}
+		return writeCanonical(buf, plain, path)
+	}
+	return nil
+}
+
+// exactInteger reports whether the double f, as JCS writes it, reads as
+// exactly the integer n. Beyond 2^53 a double may not hold n, and even one
+// that does, such as 2^62, is written with other trailing digits.
+func exactInteger(n *big.Int, f float64) bool {
+	if f == 0 {
+		return n.Sign() == 0
+	}
+	return math.Abs(f) < 1e21 && strconv.FormatFloat(f, 'f', -1, 64) == n.String()
+}
+
+// inexactNumber returns the error for the integer literal at path that a
+// double cannot hold.
+func inexactNumber(path, literal string) error {
+	return &PathError{Pointer: path, Err: fmt.Errorf("%w: %s", ErrInexactNumber, literal)}
+}
+
+// writeCanonicalNumber appends f, found at path, formatted as ECMAScript's
+// Number.toString does, as JCS requires.
+func writeCanonicalNumber(buf *bytes.Buffer, f float64, path string) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return &PathError{Pointer: path, Err: fmt.Errorf("number %v cannot be represented in canonical JSON", f)}
+	}
+	if f == 0 {
+		// Negative zero included
+		buf.WriteByte('0')
+		return nil
+	}
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		buf.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+		return nil
+	}
+	// ECMAScript writes exponents without leading zeros, as in 1e-7
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	sign, digits := exp[:1], strings.TrimLeft(exp[1:], "0")
+	buf.WriteString(mantissa + "e" + sign + digits)
+	return nil
+}
+
+// writeCanonicalString appends s as a JSON string escaping only quotes,
+// backslashes and control characters, with invalid UTF-8 replaced by U+FFFD.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xF])
+				continue
+			}
+			// Ranging replaces invalid UTF-8 with utf8.RuneError already
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 reports whether a sorts before b when both are compared as
+// sequences of UTF-16 code units, as JCS orders object keys.
+func lessUTF16(a, b string) bool {
+	if isASCII(a) && isASCII(b) {
+		return a < b
+	}
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// isASCII reports whether s only holds ASCII characters, whose byte order is
+// their UTF-16 order.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
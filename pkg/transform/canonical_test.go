@@ -0,0 +1,59 @@
+package transform_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// TestMarshalCanonicalNumbers checks that numbers are written as JCS
+// requires and that integers a double cannot hold exactly are rejected at
+// their pointer rather than rounded.
+func TestMarshalCanonicalNumbers(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      interface{}
+		want    string
+		pointer string
+	}{
+		{name: "int64", in: int64(42), want: `42`},
+		{name: "largest exact int64", in: int64(1 << 53), want: `9007199254740992`},
+		{name: "negative int64", in: int64(-(1 << 53)), want: `-9007199254740992`},
+		{name: "float", in: 1.5, want: `1.5`},
+		{name: "small float", in: 1e-7, want: `1e-7`},
+		{name: "large float", in: 1e21, want: `1e+21`},
+		{name: "negative zero", in: json.Number("-0"), want: `0`},
+		{name: "decimal number", in: json.Number("0.1"), want: `0.1`},
+		{name: "inexact int64", in: map[string]interface{}{"a": []interface{}{int64(9007199254740993)}}, pointer: "/a/0"},
+		{name: "inexact big integer", in: map[string]interface{}{"a/b": json.Number("123456789012345678901234567890")}, pointer: "/a~1b"},
+		{name: "inexact number", in: json.Number("9007199254740993"), pointer: ""},
+		{name: "power of two written differently", in: int64(1 << 62), pointer: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := transform.MarshalCanonical(tt.in)
+			if tt.want != "" {
+				if err != nil || string(out) != tt.want {
+					t.Errorf("MarshalCanonical(%v) = %s, %v, want %s", tt.in, out, err, tt.want)
+				}
+				return
+			}
+			var pathErr *transform.PathError
+			if !errors.Is(err, transform.ErrInexactNumber) || !errors.As(err, &pathErr) || pathErr.Pointer != tt.pointer {
+				t.Errorf("MarshalCanonical(%v) = %s, %v, want ErrInexactNumber at %q", tt.in, out, err, tt.pointer)
+			}
+		})
+	}
+}
+
+// TestMarshalCanonicalTransformed checks that big integers kept exact by
+// the transformation are rejected rather than rounded on the way to
+// canonical JSON.
+func TestMarshalCanonicalTransformed(t *testing.T) {
+	output, _ := transform.TransformJSON(map[string]interface{}{"id": map[string]interface{}{"N": "9007199254740993"}})
+	if canonical, err := transform.MarshalCanonical(output); !errors.Is(err, transform.ErrInexactNumber) {
+		t.Errorf("MarshalCanonical(%v) = %s, %v, want ErrInexactNumber", output, canonical, err)
+	}
+}
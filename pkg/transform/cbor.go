@@ -0,0 +1,71 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// IsCBORFile reports whether fileName names a CBOR document by its .cbor
+// extension, before any .gz or .zst compression extension.
+func IsCBORFile(fileName string) bool {
+	for _, ext := range []string{".gz", ".zst"} {
+		fileName = strings.TrimSuffix(fileName, ext)
+	}
+	return strings.HasSuffix(strings.ToLower(fileName), ".cbor")
+}
+
+// ParseCBORReader reads a CBOR document from r and parses it like
+// ParseReader parses the equivalent JSON, applying opts the same way. Byte
+// strings become base64 strings, as B values hold them, timestamps RFC 3339
+// strings and bignums plain numbers; other tags are replaced by their
+// content. Map keys must be strings. The size limit applies to the CBOR
+// input.
+func ParseCBORReader(r io.Reader, opts ...ParseOption) (map[string]interface{}, error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	inputBytes, err := readInput(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	decOpts := cbor.DecOptions{
+		DefaultMapType:       reflect.TypeOf(map[string]interface{}(nil)),
+		BigIntDec:            cbor.BigIntDecodePointer,
+		UnrecognizedTagToAny: cbor.UnrecognizedTagContentToAny,
+	}
+	if cfg.rejectDuplicates {
+		decOpts.DupMapKey = cbor.DupMapKeyEnforcedAPF
+	}
+	dm, err := decOpts.DecMode()
+	if err != nil {
+		return nil, err
+	}
+	dec := dm.NewDecoder(bytes.NewReader(inputBytes))
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		var dupErr *cbor.DupMapKeyError
+		if errors.As(err, &dupErr) {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateKey, err)
+		}
+		return nil, err
+	}
+	var extra cbor.RawMessage
+	if err := dec.Decode(&extra); err != io.EOF {
+		return nil, ErrTrailingData
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+	return parseChecked(data, cfg)
+}
@@ -0,0 +1,94 @@
+package transform
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewContextReader returns a reader reading from r until ctx is done, after
+// which every read fails with the error of ctx, so parsing or streaming a
+// slow or very large input stops as soon as it is cancelled or its deadline
+// passes. Reads from r are made in the background, so a read blocked waiting
+// for input, such as a stalled pipe, is abandoned once ctx is done; r must
+// not be read by anything else after that.
+func NewContextReader(ctx context.Context, r io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		return r
+	}
+	return &contextReader{ctx: ctx, r: r, result: make(chan readResult, 1)}
+}
+
+// contextReader is the reader returned by NewContextReader.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+	// buf receives each read from r, which may still be in progress once
+	// the context is done, so it is never the caller's buffer
+	buf    []byte
+	result chan readResult
+}
+
+// readResult is the outcome of one read of a contextReader.
+type readResult struct {
+	n   int
+	err error
+}
+
+// Read reads from the underlying reader until the context is done.
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if cap(c.buf) < len(p) {
+		c.buf = make([]byte, len(p))
+	}
+	buf := c.buf[:len(p)]
+	go func() {
+		n, err := c.r.Read(buf)
+		c.result <- readResult{n: n, err: err}
+	}()
+	select {
+	case res := <-c.result:
+		return copy(p, buf[:res.n]), res.err
+	case <-c.ctx.Done():
+		return 0, c.ctx.Err()
+	}
+}
+
+// TransformRecordContext is TransformRecord recorded as a span of the trace
+// in ctx. It fails with the error of ctx, without transforming input, once
+// ctx is done.
+func (t *Transformer) TransformRecordContext(ctx context.Context, index int, input map[string]interface{}) (_ map[string]interface{}, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	_, span := tracer.Start(ctx, "Transform", trace.WithAttributes(attribute.Int("attributes", len(input))))
+	defer func() { endSpan(span, err) }()
+	return t.TransformRecord(index, input)
+}
+
+// withContext wraps fn so that records are no longer passed to it once ctx
+// is done, failing with the error of ctx instead. A record during which ctx
+// is done fails the same way, as middleware such as plugins bound to ctx may
+// have been cut short.
+func withContext(ctx context.Context, fn recordFunc) recordFunc {
+	if ctx.Done() == nil {
+		return fn
+	}
+	return func(index int, record map[string]interface{}) (map[string]interface{}, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		output, err := fn(index, record)
+		if err == nil {
+			err = ctx.Err()
+		}
+		return output, err
+	}
+}
@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultValue is a value set at path when a document lacks it.
+type defaultValue struct {
+	path  []string
+	value interface{}
+}
+
+// WithDefaults sets the value of every output field in defaults that is
+// absent once a document has been transformed, so partially populated items
+// produce complete documents. Fields are keyed by dotted path, such as
+// "country" or "address.country", naming output keys after any renaming, and
+// missing objects along a path are created. Defaults are applied to the
+// documents returned by Transform and StreamTransform and to the images of
+// stream records.
+func WithDefaults(defaults map[string]interface{}) Option {
+	return func(t *Transformer) {
+		for field, value := range defaults {
+			t.defaults = append(t.defaults, defaultValue{path: strings.Split(field, "."), value: value})
+		}
+		// Apply parents before the fields nested within them
+		sort.Slice(t.defaults, func(i, j int) bool {
+			return strings.Join(t.defaults[i].path, ".") < strings.Join(t.defaults[j].path, ".")
+		})
+	}
+}
+
+// applyDefaults sets every default absent from doc.
+func (t *Transformer) applyDefaults(doc map[string]interface{}) {
+	for _, d := range t.defaults {
+		setDefault(doc, d.path, d.value)
+	}
+}
+
+// setDefault sets value at path within doc unless a value is already there.
+// Paths running into a value that is not an object are left alone.
+func setDefault(doc map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := doc[key]
+		if !ok {
+			next = make(map[string]interface{})
+			doc[key] = next
+		}
+		if doc, ok = next.(map[string]interface{}); !ok {
+			return
+		}
+	}
+	last := path[len(path)-1]
+	if _, ok := doc[last]; !ok {
+		// Documents must not share, and later modify, the configured value
+		doc[last] = copyValue(value)
+	}
+}
+
+// copyValue returns a deep copy of the objects and lists in v.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[key] = copyValue(elem)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = copyValue(elem)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,34 @@
+package transform
+
+// DroppedKeyHandler receives every attribute key the transformation drops,
+// with the path of the object holding it and the reason, such as a key left
+// empty once sanitized.
+type DroppedKeyHandler func(path []string, key, reason string)
+
+// Reasons passed to a DroppedKeyHandler.
+const (
+	// DroppedEmptyKey is the reason of keys holding nothing but whitespace,
+	// which sanitizing leaves empty.
+	DroppedEmptyKey = "empty once whitespace is trimmed"
+	// DroppedRenamedKey is the reason of keys renamed to the empty key by
+	// WithKeyRenames or WithKeyCase.
+	DroppedRenamedKey = "renamed to an empty key"
+)
+
+// WithDroppedKeyHandler sets the handler receiving the keys Transform drops,
+// e.g. to log them as diagnostics. Setting one makes the transformation
+// track key paths, as middleware does.
+func WithDroppedKeyHandler(h DroppedKeyHandler) Option {
+	return func(t *Transformer) {
+		t.droppedKey = h
+	}
+}
+
+// dropKey counts key of the object at path as dropped for reason and passes
+// it to the DroppedKeyHandler, if any.
+func (t *Transformer) dropKey(path []string, key, reason string) {
+	t.stats.addDroppedKey()
+	if t.droppedKey != nil {
+		t.droppedKey(path, key, reason)
+	}
+}
@@ -0,0 +1,126 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// bufferPool recycles the scratch buffers used while encoding, which matters
+// when millions of items are marshaled one after another.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// keysPool recycles the slices used to sort object keys.
+var keysPool = sync.Pool{
+	New: func() interface{} {
+		keys := make([]string, 0, 16)
+		return &keys
+	},
+}
+
+// getBuffer returns an empty buffer from bufferPool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to bufferPool. Unusually large buffers are dropped so
+// one huge document does not pin its memory for the life of the process.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > 1<<20 {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// MarshalSorted encodes v as JSON with object keys in lexicographic order at
+// every nesting level. Unlike json.Marshal, the ordering is part of this
+// function's contract rather than an implementation detail of the encoder, so
+// outputs stay reproducible and diffable.
+func MarshalSorted(v interface{}) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	enc := &sortedEncoder{buf: buf, scalar: getBuffer()}
+	defer putBuffer(enc.scalar)
+	enc.enc = json.NewEncoder(enc.scalar)
+	enc.enc.SetEscapeHTML(codec.EscapeHTML())
+
+	if err := enc.write(v); err != nil {
+		return nil, err
+	}
+	// The buffer goes back to the pool, so the caller gets its own copy
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+// sortedEncoder writes the sorted encoding of a value to buf, encoding
+// scalars through a single reusable json.Encoder.
+type sortedEncoder struct {
+	buf    *bytes.Buffer
+	scalar *bytes.Buffer
+	enc    *json.Encoder
+}
+
+// write appends the sorted encoding of v to the output buffer.
+func (e *sortedEncoder) write(v interface{}) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keysPtr := keysPool.Get().(*[]string)
+		keys := (*keysPtr)[:0]
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		e.buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			if err := e.writeScalar(k); err != nil {
+				return err
+			}
+			e.buf.WriteByte(':')
+			if err := e.write(val[k]); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte('}')
+
+		*keysPtr = keys[:0]
+		keysPool.Put(keysPtr)
+	case []interface{}:
+		e.buf.WriteByte('[')
+		for i, item := range val {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			if err := e.write(item); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte(']')
+	default:
+		// Scalars have a single encoding, so defer to encoding/json
+		return e.writeScalar(val)
+	}
+	return nil
+}
+
+// writeScalar appends the encoding/json encoding of v to the output buffer.
+func (e *sortedEncoder) writeScalar(v interface{}) error {
+	e.scalar.Reset()
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+	// Encode terminates every value with a newline
+	e.buf.Write(bytes.TrimSuffix(e.scalar.Bytes(), []byte{'\n'}))
+	return nil
+}
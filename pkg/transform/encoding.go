@@ -0,0 +1,38 @@
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// utf8BOM is the byte order mark some Windows tools start UTF-8 text with.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// NewTextReader returns r decoded into UTF-8 without a byte order mark, as
+// the parsers expect. A UTF-8 byte order mark is dropped, and UTF-16 input,
+// as written by Windows exports, is transcoded whether it starts with a
+// byte order mark or, as JSON text starts with an ASCII character, with a
+// zero byte before or after it. Other input is returned as it is read.
+func NewTextReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	// A short or failed peek leaves the error to the first read
+	head, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, utf8BOM):
+		br.Discard(len(utf8BOM))
+		return br
+	case bytes.HasPrefix(head, []byte{0xFE, 0xFF}):
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Reader(br)
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Reader(br)
+	case len(head) >= 2 && head[0] == 0 && head[1] != 0:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder().Reader(br)
+	case len(head) >= 2 && head[0] != 0 && head[1] == 0:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder().Reader(br)
+	default:
+		return br
+	}
+}
@@ -0,0 +1,72 @@
+package transform
+
+import "fmt"
+
+// ErrorMode decides what happens to invalid values, such as unparseable N
+// values, invalid base64 or values of the wrong JSON type.
+type ErrorMode int
+
+const (
+	// OnErrorDefault replaces invalid values with the default of their type:
+	// 0, "", false, null, an empty object or an empty array.
+	OnErrorDefault ErrorMode = iota
+	// OnErrorSkip omits invalid values from the output.
+	OnErrorSkip
+	// OnErrorFail makes Check, and so TransformChecked, report the first
+	// invalid value. Transform, which cannot fail, omits invalid values as
+	// OnErrorSkip does.
+	OnErrorFail
+)
+
+// ParseErrorMode parses the name of an ErrorMode: default, skip or fail.
+func ParseErrorMode(name string) (ErrorMode, error) {
+	switch name {
+	case "default":
+		return OnErrorDefault, nil
+	case "skip":
+		return OnErrorSkip, nil
+	case "fail":
+		return OnErrorFail, nil
+	default:
+		return 0, fmt.Errorf("unknown error mode %q", name)
+	}
+}
+
+// WithOnError sets what happens to invalid values. Values with an unknown
+// type tag have no type to take a default from and are omitted in every mode.
+func WithOnError(mode ErrorMode) Option {
+	return func(t *Transformer) {
+		t.onError = mode
+	}
+}
+
+// Check returns the first invalid value of input in key order as a
+// *PathError when the error mode is OnErrorFail, and nil otherwise. Values of
+// the wrong JSON type are reported first, wrapping a *TypeError.
+func (t *Transformer) Check(input map[string]interface{}) error {
+	if t.onError != OnErrorFail {
+		return nil
+	}
+	if err := t.CheckTypes(input); err != nil {
+		return err
+	}
+	if warnings := t.Validate(input); len(warnings) > 0 {
+		return &PathError{Pointer: warnings[0].Path, Err: warnings[0].Err}
+	}
+	return nil
+}
+
+// skipInvalid reports whether rules omit invalid values rather than
+// replacing them with a default.
+func (t *Transformer) skipInvalid() bool {
+	return t.strict || t.onError != OnErrorDefault
+}
+
+// invalid returns the result of a rule for an invalid value: err, which
+// omits the value, or def when invalid values are replaced with defaults.
+func (t *Transformer) invalid(err error, def interface{}) interface{} {
+	if t.skipInvalid() {
+		return err
+	}
+	return def
+}
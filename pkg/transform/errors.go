@@ -0,0 +1,73 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors classifying invalid input. The errors returned by this
+// package wrap them, so callers can test for a kind of problem with
+// errors.Is rather than by matching messages.
+var (
+	// ErrNotJSONFile is returned by ParseSchema for a file name without a
+	// .json, .yaml, .yml or .cbor extension.
+	ErrNotJSONFile = errors.New("config file is not a JSON, YAML or CBOR file")
+	// ErrInvalidTypeTag reports an unknown type tag, or an attribute that
+	// does not hold exactly one.
+	ErrInvalidTypeTag = errors.New("invalid type tag")
+	// ErrEmptyKey reports an attribute whose key is empty or only whitespace.
+	ErrEmptyKey = errors.New("empty key")
+	// ErrEmptyString reports an empty S value in strict mode.
+	ErrEmptyString = errors.New("empty string")
+	// ErrUnparsableNumber reports an N value that is not a number.
+	ErrUnparsableNumber = errors.New("unparsable number")
+	// ErrInvalidBoolean reports a BOOL value that is not a recognised boolean.
+	ErrInvalidBoolean = errors.New("invalid boolean")
+	// ErrInvalidBinary reports a B value that is not valid base64.
+	ErrInvalidBinary = errors.New("invalid base64")
+	// ErrInvalidNull reports a NULL value other than true.
+	ErrInvalidNull = errors.New("invalid null")
+	// ErrDuplicateKey reports an object holding the same key more than once;
+	// the error is a *DuplicateKeyError.
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrLimitExceeded reports input beyond a limit set by a ParseOption; the
+	// error is a *LimitError.
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrTypeMismatch reports a value whose JSON type does not match its type
+	// tag; the error is a *TypeError.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrMissingField reports a required field absent from the transformed
+	// output.
+	ErrMissingField = errors.New("missing required field")
+	// ErrLossyRoundTrip reports a value that does not survive being
+	// transformed and reversed unchanged.
+	ErrLossyRoundTrip = errors.New("lossy round trip")
+	// ErrMergeConflict reports a key holding different values in documents
+	// merged with MergeError.
+	ErrMergeConflict = errors.New("merge conflict")
+	// ErrInexactNumber reports an integer that MarshalCanonical cannot write
+	// as the IEEE 754 double JCS requires without changing its value.
+	ErrInexactNumber = errors.New("number cannot be represented exactly as a double")
+)
+
+// valueError describes a specific invalid value while matching the sentinel
+// error of its kind.
+type valueError struct {
+	kind error
+	msg  string
+}
+
+// invalidValue returns an error of the given kind with a formatted message.
+func invalidValue(kind error, format string, args ...interface{}) error {
+	return &valueError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// Error returns the message describing the value.
+func (e *valueError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the sentinel error of the kind of problem.
+func (e *valueError) Unwrap() error {
+	return e.kind
+}
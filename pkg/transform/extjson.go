@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// WithExtendedJSON writes the transformed values whose type plain JSON loses
+// as MongoDB Extended JSON v2 in canonical mode, so mongoimport restores
+// them: N values become $numberInt, $numberLong, $numberDouble, or
+// $numberDecimal for integers beyond 64 bits, converted timestamps become
+// $date and B values $binary, in sets too. The binary format and epoch unit
+// do not apply. Reverse does not read Extended JSON.
+func WithExtendedJSON(enabled bool) Option {
+	return func(t *Transformer) {
+		t.extendedJSON = enabled
+	}
+}
+
+// extendedNumber returns the N value n, as converted by formatNum, in
+// Extended JSON. Invalid values are returned as they are.
+func extendedNumber(n interface{}) interface{} {
+	switch n := n.(type) {
+	case int64:
+		if n >= math.MinInt32 && n <= math.MaxInt32 {
+			return map[string]interface{}{"$numberInt": strconv.FormatInt(n, 10)}
+		}
+		return map[string]interface{}{"$numberLong": strconv.FormatInt(n, 10)}
+	case json.Number:
+		return map[string]interface{}{"$numberDecimal": n.String()}
+	case float64:
+		var s string
+		switch {
+		case math.IsInf(n, 1):
+			s = "Infinity"
+		case math.IsInf(n, -1):
+			s = "-Infinity"
+		case math.IsNaN(n):
+			s = "NaN"
+		default:
+			s = strconv.FormatFloat(n, 'g', -1, 64)
+		}
+		return map[string]interface{}{"$numberDouble": s}
+	default:
+		return n
+	}
+}
+
+// extendedDate returns tm as an Extended JSON $date, in milliseconds since
+// the Unix epoch.
+func extendedDate(tm time.Time) interface{} {
+	return map[string]interface{}{
+		"$date": map[string]interface{}{"$numberLong": strconv.FormatInt(tm.UnixMilli(), 10)},
+	}
+}
+
+// extendedBinary returns data as an Extended JSON $binary of the generic
+// subtype.
+func extendedBinary(data []byte) interface{} {
+	return map[string]interface{}{
+		"$binary": map[string]interface{}{
+			"base64":  base64.StdEncoding.EncodeToString(data),
+			"subType": "00",
+		},
+	}
+}
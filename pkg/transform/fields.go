@@ -0,0 +1,173 @@
+package transform
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FieldPath selects attributes by their key path within the input, one
+// pattern per nesting level. Each pattern is matched against a key, or the
+// decimal index of a list element, with path.Match syntax, so "*" matches any
+// key and "user_*" any key starting with "user_".
+type FieldPath []string
+
+// ParseFieldPath parses a dotted field path such as "user.email" or
+// "items.*.price".
+func ParseFieldPath(s string) (FieldPath, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty field path")
+	}
+	p := FieldPath(strings.Split(s, "."))
+	for _, pattern := range p {
+		if pattern == "" {
+			return nil, fmt.Errorf("field path %q has an empty key", s)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("field path %q: %w", s, err)
+		}
+	}
+	return p, nil
+}
+
+// String returns p in its dotted form.
+func (p FieldPath) String() string {
+	return strings.Join(p, ".")
+}
+
+// matches reports whether the first len(p) keys of keys match p, that is
+// whether keys is at or below an attribute selected by p.
+func (p FieldPath) matches(keys []string) bool {
+	if len(keys) < len(p) {
+		return false
+	}
+	for i, pattern := range p {
+		if ok, _ := path.Match(pattern, keys[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// leadsTo reports whether keys is above an attribute selected by p.
+func (p FieldPath) leadsTo(keys []string) bool {
+	return len(keys) < len(p) && FieldPath(p[:len(keys)]).matches(keys)
+}
+
+// WithInclude keeps only the attributes selected by paths, with everything
+// nested within them and the objects and lists leading to them. Objects and
+// lists that only lead to selected attributes are dropped when none of them
+// are present. Paths refer to the input keys, before any renaming.
+func WithInclude(paths ...FieldPath) Option {
+	return func(t *Transformer) {
+		t.include = append(t.include, paths...)
+	}
+}
+
+// WithExclude drops the attributes selected by paths, with everything nested
+// within them. Objects and lists left empty because every attribute in them
+// was excluded are dropped too, while those already empty in the input are
+// kept. Paths refer to the input keys, before any renaming, and exclusion
+// takes precedence over WithInclude.
+func WithExclude(paths ...FieldPath) Option {
+	return func(t *Transformer) {
+		t.exclude = append(t.exclude, paths...)
+	}
+}
+
+// selectsFields reports whether include or exclude paths are set, so key
+// paths must be tracked.
+func (t *Transformer) selectsFields() bool {
+	return len(t.include) > 0 || len(t.exclude) > 0
+}
+
+// selectField decides whether the attribute at keys appears in the output.
+// partial reports that it is only kept because it leads to included
+// attributes, so it is dropped unless its transformed value is a non-empty
+// object or list.
+func (t *Transformer) selectField(keys []string) (keep, partial bool) {
+	for _, p := range t.exclude {
+		if p.matches(keys) {
+			return false, false
+		}
+	}
+	if len(t.include) == 0 {
+		return true, false
+	}
+	for _, p := range t.include {
+		if p.matches(keys) {
+			return true, false
+		}
+	}
+	for _, p := range t.include {
+		if p.leadsTo(keys) {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// emptiedByExclude reports whether the attribute at keys, an object or list
+// with elements in the input, has none left in its transformed value out
+// while exclude paths reach into it.
+func (t *Transformer) emptiedByExclude(keys []string, in, out interface{}) bool {
+	if nonEmptyContainer(out) || !nonEmptyContainer(in) {
+		return false
+	}
+	for _, p := range t.exclude {
+		if p.leadsTo(keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// nonEmptyContainer reports whether v is an object or list with elements.
+func nonEmptyContainer(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// WithDropKeys drops the attributes whose key matches any of patterns, such
+// as ^internal_, at every nesting level, with everything nested within them.
+// Keys are matched before any renaming.
+func WithDropKeys(patterns ...*regexp.Regexp) Option {
+	return func(t *Transformer) {
+		t.dropKeys = append(t.dropKeys, patterns...)
+	}
+}
+
+// WithKeepKeys keeps only the attributes whose key matches any of patterns
+// at every nesting level, so the keys of nested objects must match too. Keys
+// are matched before any renaming, and WithDropKeys takes precedence.
+func WithKeepKeys(patterns ...*regexp.Regexp) Option {
+	return func(t *Transformer) {
+		t.keepKeys = append(t.keepKeys, patterns...)
+	}
+}
+
+// keepKey reports whether the attribute with the sanitized key passes the
+// key patterns.
+func (t *Transformer) keepKey(key string) bool {
+	if matchesAny(t.dropKeys, key) {
+		return false
+	}
+	return len(t.keepKeys) == 0 || matchesAny(t.keepKeys, key)
+}
+
+// matchesAny reports whether s matches any of patterns.
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
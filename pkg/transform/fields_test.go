@@ -0,0 +1,69 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// TestExcludeDropsEmptiedContainers checks that objects and lists whose
+// every attribute is excluded are dropped, while those that keep some
+// attributes or were already empty in the input are kept.
+func TestExcludeDropsEmptiedContainers(t *testing.T) {
+	const in = `{
+		"id": {"S": "1"},
+		"address": {"M": {"city": {"S": "Oslo"}, "zip": {"S": "0150"}}},
+		"contact": {"M": {"email": {"S": "a@example.com"}, "phone": {"S": "123"}}},
+		"meta": {"M": {}},
+		"orders": {"L": [{"M": {"secret": {"S": "x"}}}, {"M": {"secret": {"S": "y"}, "total": {"N": "5"}}}]},
+		"tags": {"L": []}
+	}`
+	tests := []struct {
+		name    string
+		exclude []string
+		want    string
+	}{
+		{
+			name:    "every attribute of an object",
+			exclude: []string{"address.*"},
+			want:    `{"contact":{"email":"a@example.com","phone":"123"},"id":"1","meta":{},"orders":[{"secret":"x"},{"secret":"y","total":5}],"tags":[]}`,
+		},
+		{
+			name:    "some attributes of an object",
+			exclude: []string{"contact.phone"},
+			want:    `{"address":{"city":"Oslo","zip":"0150"},"contact":{"email":"a@example.com"},"id":"1","meta":{},"orders":[{"secret":"x"},{"secret":"y","total":5}],"tags":[]}`,
+		},
+		{
+			name:    "list elements emptied",
+			exclude: []string{"orders.*.secret"},
+			want:    `{"address":{"city":"Oslo","zip":"0150"},"contact":{"email":"a@example.com","phone":"123"},"id":"1","meta":{},"orders":[{"total":5}],"tags":[]}`,
+		},
+		{
+			name:    "nested containers emptied",
+			exclude: []string{"orders.*.*", "address.*"},
+			want:    `{"contact":{"email":"a@example.com","phone":"123"},"id":"1","meta":{},"tags":[]}`,
+		},
+		{
+			name:    "containers empty in the input",
+			exclude: []string{"meta.*", "tags.*"},
+			want:    `{"address":{"city":"Oslo","zip":"0150"},"contact":{"email":"a@example.com","phone":"123"},"id":"1","meta":{},"orders":[{"secret":"x"},{"secret":"y","total":5}],"tags":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		var paths []transform.FieldPath
+		for _, s := range tt.exclude {
+			p, err := transform.ParseFieldPath(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths = append(paths, p)
+		}
+		got, err := transform.TransformBytes([]byte(in), transform.WithExclude(paths...))
+		if err != nil {
+			t.Fatalf("%s: TransformBytes error: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: TransformBytes = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package transform
+
+// RecordFilter decides whether a record of a record stream is emitted. It
+// receives the transformed plain record, or the plain input record when
+// reversing, and an error fails the stream.
+type RecordFilter func(record map[string]interface{}) (bool, error)
+
+// WithRecordFilter only emits the records of TransformNDJSON, ReverseNDJSON
+// and TransformStreamEvent for which filter returns true. Stream records are
+// filtered on their new image, or their old image for removals, falling back
+// to their keys. Whole documents passed to Transform are never filtered.
+func WithRecordFilter(filter RecordFilter) Option {
+	return func(t *Transformer) {
+		t.filter = filter
+	}
+}
+
+// KeepRecord reports whether the plain record passes the filter set with
+// WithRecordFilter, for callers streaming records themselves.
+func (t *Transformer) KeepRecord(record map[string]interface{}) (bool, error) {
+	if t.filter == nil {
+		return true, nil
+	}
+	return t.filter(record)
+}
+
+// filtered wraps fn so records not passing the record filter yield a nil
+// record, which the record streams skip. Reversed records are filtered before
+// reversing, so the filter always sees plain JSON.
+func (t *Transformer) filtered(fn recordFunc, reverse bool) recordFunc {
+	if t.filter == nil {
+		return fn
+	}
+	return func(index int, record map[string]interface{}) (map[string]interface{}, error) {
+		plain := record
+		if !reverse {
+			out, err := fn(index, record)
+			if err != nil {
+				return nil, err
+			}
+			plain = out
+		}
+		keep, err := t.KeepRecord(plain)
+		if err != nil || !keep {
+			return nil, err
+		}
+		if reverse {
+			return fn(index, record)
+		}
+		return plain, nil
+	}
+}
@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithFlatten flattens nested objects in transformed documents into keys
+// joining their key paths with delimiter, such as "address.city" for ".",
+// for consumers that cannot handle nesting. Objects within arrays are
+// flattened on their own and empty objects are kept. Reverse does the
+// inverse to its plain input with Unflatten, so flattened documents convert
+// back into nested typed ones. Defaults apply before flattening, and
+// required fields may be given as the nested paths or the flattened keys.
+// StreamTransform and StreamReverse do not reshape documents.
+func WithFlatten(delimiter string) Option {
+	return func(t *Transformer) {
+		t.reshape = Flatten
+		t.unshape = Unflatten
+		t.delimiter = delimiter
+	}
+}
+
+// WithUnflatten is the inverse of WithFlatten: keys of transformed documents
+// holding delimiter are split into nested objects, and Reverse flattens its
+// plain input first.
+func WithUnflatten(delimiter string) Option {
+	return func(t *Transformer) {
+		t.reshape = Unflatten
+		t.unshape = Flatten
+		t.delimiter = delimiter
+	}
+}
+
+// Flatten returns doc with its nested objects replaced by keys joining their
+// key paths with delimiter, recursing into arrays. Empty objects are kept as
+// values.
+func Flatten(doc map[string]interface{}, delimiter string) map[string]interface{} {
+	out := make(map[string]interface{}, len(doc))
+	flattenInto(out, "", doc, delimiter)
+	return out
+}
+
+// flattenInto adds the entries of doc to out with their keys after prefix.
+func flattenInto(out map[string]interface{}, prefix string, doc map[string]interface{}, delimiter string) {
+	for key, value := range doc {
+		if prefix != "" {
+			key = prefix + delimiter + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(out, key, nested, delimiter)
+			continue
+		}
+		out[key] = reshapeList(value, delimiter, Flatten)
+	}
+}
+
+// Unflatten returns doc with keys holding delimiter split into nested
+// objects, recursing into nested objects and arrays. Keys are handled in
+// sorted order; one whose path runs into a value that is not an object is
+// kept as it is rather than dropped.
+func Unflatten(doc map[string]interface{}, delimiter string) map[string]interface{} {
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make(map[string]interface{}, len(doc))
+	for _, key := range keys {
+		value := doc[key]
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = Unflatten(nested, delimiter)
+		} else {
+			value = reshapeList(value, delimiter, Unflatten)
+		}
+		if !setPath(out, strings.Split(key, delimiter), value) {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// setPath sets value at path within doc, creating the objects on the way
+// and merging into those already there. It reports false when the path runs
+// into another value.
+func setPath(doc map[string]interface{}, path []string, value interface{}) bool {
+	for i, key := range path[:len(path)-1] {
+		next, ok := doc[key]
+		if !ok {
+			// Create the rest of the path at once, as nothing can conflict
+			for j := len(path) - 1; j > i; j-- {
+				value = map[string]interface{}{path[j]: value}
+			}
+			doc[key] = value
+			return true
+		}
+		nested, ok := next.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		doc = nested
+	}
+
+	last := path[len(path)-1]
+	existing, ok := doc[last]
+	if !ok {
+		doc[last] = value
+		return true
+	}
+	// Objects reached both ways are merged
+	into, ok := existing.(map[string]interface{})
+	from, isMap := value.(map[string]interface{})
+	if !ok || !isMap {
+		return false
+	}
+	for key, v := range from {
+		if !setPath(into, []string{key}, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// reshapeList applies reshape to the objects within the array v, returning
+// other values as they are.
+func reshapeList(v interface{}, delimiter string, reshape func(map[string]interface{}, string) map[string]interface{}) interface{} {
+	list, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	out := make([]interface{}, len(list))
+	for i, elem := range list {
+		switch elem := elem.(type) {
+		case map[string]interface{}:
+			out[i] = reshape(elem, delimiter)
+		case []interface{}:
+			out[i] = reshapeList(elem, delimiter, reshape)
+		default:
+			out[i] = elem
+		}
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// Transform transforms the typed JSON document read from r with a
+// Transformer configured by opts and writes the plain result to w, so the
+// transformer can sit on any stream, such as a network connection, a pipe or
+// an archive entry. It is StreamTransform: the document is never held in
+// memory whole, attributes keep their input order and the output is compact
+// JSON. Flattening does not apply.
+func Transform(r io.Reader, w io.Writer, opts ...Option) error {
+	return New(opts...).StreamTransform(r, w)
+}
+
+// StreamTransform transforms the single JSON object read from r and writes
+// the result to w as compact JSON. Unlike ParseReader and Transform it
+// decodes one top-level attribute at a time and writes it out before moving
+// on, so memory is bounded by the largest attribute rather than the whole
+// document. Attributes keep their input order, and duplicate keys are all
+// written rather than the last one winning. Each attribute is checked with
+// Check, so with OnErrorFail an invalid value stops the stream with its
+// *PathError. Defaults set with WithDefaults are applied to each attribute
+// and those of absent attributes are written last.
+func (t *Transformer) StreamTransform(r io.Reader, w io.Writer) error {
+	return t.StreamTransformContext(context.Background(), r, w)
+}
+
+// StreamTransformContext is StreamTransform stopping with the error of ctx
+// once ctx is done, before the next attribute is read or transformed. The
+// output written so far is then an incomplete object.
+func (t *Transformer) StreamTransformContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	t.stats.addRecord()
+	written := make(map[string]bool)
+	return streamObject(NewContextReader(ctx, r), w, func(attr map[string]interface{}) (map[string]interface{}, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := t.Check(attr); err != nil {
+			return nil, err
+		}
+		out := t.transformDocument(attr)
+		for key := range out {
+			written[key] = true
+		}
+		// Fill in the fields nested within this attribute only
+		for _, d := range t.defaults {
+			if _, ok := out[d.path[0]]; ok && len(d.path) > 1 {
+				setDefault(out, d.path, d.value)
+			}
+		}
+		return out, nil
+	}, func() map[string]interface{} {
+		missing := make(map[string]interface{})
+		for _, d := range t.defaults {
+			if !written[d.path[0]] {
+				setDefault(missing, d.path, d.value)
+			}
+		}
+		return missing
+	})
+}
+
+// StreamReverse is like StreamTransform but converts plain JSON into typed JSON.
+func (t *Transformer) StreamReverse(r io.Reader, w io.Writer) error {
+	return t.StreamReverseContext(context.Background(), r, w)
+}
+
+// StreamReverseContext is StreamReverse stopping once ctx is done, like
+// StreamTransformContext.
+func (t *Transformer) StreamReverseContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	return streamObject(NewContextReader(ctx, r), w, func(attr map[string]interface{}) (map[string]interface{}, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return t.Reverse(attr), nil
+	}, nil)
+}
+
+// streamObject applies fn to each top-level attribute of the object read from
+// r in turn and writes the results to w as a single object, followed by the
+// attributes returned by rest, if set, once the input is exhausted.
+func streamObject(r io.Reader, w io.Writer, fn func(map[string]interface{}) (map[string]interface{}, error), rest func() map[string]interface{}) error {
+	dec := json.NewDecoder(NewTextReader(r))
+	dec.UseNumber()
+	bw := bufio.NewWriter(w)
+
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		// Report the same error decoding into a map would
+		return &json.UnmarshalTypeError{Value: jsonKind(tok), Type: reflect.TypeOf(map[string]interface{}{}), Offset: dec.InputOffset()}
+	}
+	bw.WriteByte('{')
+
+	// Keys and values are encoded through one reusable scratch buffer
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+	enc := json.NewEncoder(scratch)
+	enc.SetEscapeHTML(codec.EscapeHTML())
+	encode := func(v interface{}) error {
+		scratch.Reset()
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		bw.Write(bytes.TrimSuffix(scratch.Bytes(), []byte{'\n'}))
+		return nil
+	}
+
+	first := true
+	writeAttribute := func(key string, value interface{}) error {
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		if err := encode(key); err != nil {
+			return err
+		}
+		bw.WriteByte(':')
+		return encode(value)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("attribute %q: %w", key, err)
+		}
+
+		// Transform the attribute on its own; it may be dropped or renamed
+		out, err := fn(map[string]interface{}{key: value})
+		if err != nil {
+			return err
+		}
+		for outKey, outValue := range out {
+			if err := writeAttribute(outKey, outValue); err != nil {
+				return fmt.Errorf("attribute %q: %w", key, err)
+			}
+		}
+	}
+
+	// Consume the closing brace and reject anything after the object, as
+	// ParseReader does
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return ErrTrailingData
+	}
+
+	if rest != nil {
+		extra := rest()
+		keys := make([]string, 0, len(extra))
+		for key := range extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := writeAttribute(key, extra[key]); err != nil {
+				return fmt.Errorf("attribute %q: %w", key, err)
+			}
+		}
+	}
+
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// jsonKind names the kind of JSON value that starts with tok, as used in
+// json.UnmarshalTypeError.
+func jsonKind(tok json.Token) string {
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '[' {
+			return "array"
+		}
+		return "object"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// AllowComments accepts JSONC input: // line and /* block */ comments and
+// trailing commas before a closing bracket, as in hand-maintained files.
+// They are blanked out before parsing, so the line and column of syntax
+// errors still point into the input. ParseSchema enables it for files named
+// .jsonc or .json5.
+func AllowComments() ParseOption {
+	return func(c *parseConfig) {
+		c.allowComments = true
+	}
+}
+
+// IsJSONCFile reports whether fileName names a JSONC document by its .jsonc
+// or .json5 extension, before any .gz or .zst compression extension.
+func IsJSONCFile(fileName string) bool {
+	for _, ext := range []string{".gz", ".zst"} {
+		fileName = strings.TrimSuffix(fileName, ext)
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".jsonc", ".json5":
+		return true
+	default:
+		return false
+	}
+}
+
+// StripComments returns data with its comments and trailing commas replaced
+// by spaces, keeping line breaks, so the result is plain JSON of the same
+// length. Strings are left untouched and an unterminated block comment
+// blanks the rest of the input.
+func StripComments(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	// comma is the offset of a comma that may turn out to be trailing, or -1
+	comma := -1
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			comma = -1
+			// Skip to the closing quote, past escaped characters
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; i < len(out); i++ {
+				if out[i] == '*' && i+1 < len(out) && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				blank(out, i)
+			}
+		case c == ',':
+			comma = i
+		case c == '}' || c == ']':
+			if comma >= 0 {
+				out[comma] = ' '
+			}
+			comma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			comma = -1
+		}
+	}
+	return out
+}
+
+// blank replaces the byte at i with a space unless it breaks a line.
+func blank(data []byte, i int) {
+	if data[i] != '\n' && data[i] != '\r' {
+		data[i] = ' '
+	}
+}
@@ -0,0 +1,130 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyCase selects the naming convention output keys are rewritten to.
+type KeyCase int
+
+const (
+	// KeyCasePreserve keeps keys as they are in the input.
+	KeyCasePreserve KeyCase = iota
+	// KeyCaseCamel rewrites keys as camelCase.
+	KeyCaseCamel
+	// KeyCaseSnake rewrites keys as snake_case.
+	KeyCaseSnake
+	// KeyCasePascal rewrites keys as PascalCase.
+	KeyCasePascal
+	// KeyCaseKebab rewrites keys as kebab-case.
+	KeyCaseKebab
+)
+
+// ParseKeyCase parses the name of a KeyCase: preserve, camel, snake, pascal
+// or kebab.
+func ParseKeyCase(name string) (KeyCase, error) {
+	switch name {
+	case "", "preserve":
+		return KeyCasePreserve, nil
+	case "camel":
+		return KeyCaseCamel, nil
+	case "snake":
+		return KeyCaseSnake, nil
+	case "pascal":
+		return KeyCasePascal, nil
+	case "kebab":
+		return KeyCaseKebab, nil
+	default:
+		return 0, fmt.Errorf("unknown key case %q, want camel, snake, pascal or kebab", name)
+	}
+}
+
+// WithKeyCase rewrites every output key to the naming convention kc at every
+// nesting level. Keys are split into words at underscores, hyphens, spaces,
+// dots and case changes, so user_id, userId and UserID all become userId in
+// camel case. Keys renamed by WithKeyRenames are kept as given.
+func WithKeyCase(kc KeyCase) Option {
+	return func(t *Transformer) {
+		t.keyCase = kc
+	}
+}
+
+// convertCase returns key rewritten to the naming convention kc.
+func convertCase(key string, kc KeyCase) string {
+	if kc == KeyCasePreserve {
+		return key
+	}
+	words := splitWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		switch kc {
+		case KeyCaseSnake, KeyCaseKebab:
+			if i > 0 {
+				if kc == KeyCaseSnake {
+					b.WriteByte('_')
+				} else {
+					b.WriteByte('-')
+				}
+			}
+			b.WriteString(word)
+		case KeyCaseCamel:
+			if i == 0 {
+				b.WriteString(word)
+			} else {
+				b.WriteString(capitalize(word))
+			}
+		case KeyCasePascal:
+			b.WriteString(capitalize(word))
+		}
+	}
+	return b.String()
+}
+
+// splitWords splits key into words at separators and case changes. An
+// upper-case run followed by a lower-case letter ends before its last letter,
+// so HTTPServer splits into HTTP and Server. Digits stay with the word they
+// follow.
+func splitWords(key string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// capitalize returns word with its first letter in upper case.
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
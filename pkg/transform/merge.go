@@ -0,0 +1,76 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeStrategy decides what Merge does with a key present in both
+// documents.
+type MergeStrategy int
+
+const (
+	// MergeError fails on a key holding different values in both
+	// documents. Keys holding equal values are not conflicts.
+	MergeError MergeStrategy = iota
+	// MergeFirstWins keeps the value the destination already holds.
+	MergeFirstWins
+	// MergeDeep merges objects held by the key in both documents key by
+	// key, and otherwise takes the value of the source.
+	MergeDeep
+)
+
+// ParseMergeStrategy parses the name of a MergeStrategy: error, first-wins
+// or deep-merge.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	switch name {
+	case "error":
+		return MergeError, nil
+	case "first-wins":
+		return MergeFirstWins, nil
+	case "deep-merge":
+		return MergeDeep, nil
+	default:
+		return 0, fmt.Errorf("unknown merge strategy %q", name)
+	}
+}
+
+// Merge merges the document src into dst, such as the transformed outputs
+// of the shards of an export, resolving keys present in both with strategy.
+// With MergeError, a conflict is returned as a *PathError wrapping
+// ErrMergeConflict, and dst is left partly merged. Values of src are taken
+// into dst as they are, not copied, so later merges into dst may change
+// them.
+func Merge(dst, src map[string]interface{}, strategy MergeStrategy) error {
+	return merge(nil, dst, src, strategy)
+}
+
+// merge merges src into dst, the objects found at path.
+func merge(path []string, dst, src map[string]interface{}, strategy MergeStrategy) error {
+	for _, key := range sortedKeys(src) {
+		value := src[key]
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		switch strategy {
+		case MergeFirstWins:
+		case MergeDeep:
+			dstObj, dstOK := existing.(map[string]interface{})
+			srcObj, srcOK := value.(map[string]interface{})
+			if !dstOK || !srcOK {
+				dst[key] = value
+				break
+			}
+			if err := merge(append(path, key), dstObj, srcObj, strategy); err != nil {
+				return err
+			}
+		default:
+			if !reflect.DeepEqual(existing, value) {
+				return &PathError{Pointer: JSONPointer(append(path, key)), Err: ErrMergeConflict}
+			}
+		}
+	}
+	return nil
+}
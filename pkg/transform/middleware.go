@@ -0,0 +1,51 @@
+package transform
+
+// Attribute is a single typed value about to be transformed.
+type Attribute struct {
+	// Path holds the keys leading to the value from the document root, with
+	// list elements addressed by their decimal index. It is only populated
+	// when middleware is registered.
+	Path []string
+	// Type is the type tag of the value, such as "S" or "N". Attributes of
+	// top-level and map values reach middleware even when no rule is
+	// registered for their type, in which case next reports them invalid.
+	Type string
+	// Value is the raw value wrapped by the type tag.
+	Value interface{}
+}
+
+// TransformFunc transforms an attribute, reporting whether the result is
+// valid. An invalid result omits the attribute or list element from the output.
+type TransformFunc func(a Attribute) (interface{}, bool)
+
+// Middleware wraps the transformation of every typed value. It can inspect or
+// rewrite the attribute before calling next, and the result after, which
+// makes it suitable for logging, metrics or value rewriting without changing
+// the rules themselves.
+type Middleware func(next TransformFunc) TransformFunc
+
+// WithMiddleware appends mw to the middleware chain. Middleware registered
+// first runs outermost, so it sees attributes first and results last.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(t *Transformer) {
+		t.middleware = append(t.middleware, mw...)
+	}
+}
+
+// Hooks returns Middleware calling before with each attribute before it is
+// transformed and after with the attribute and its result. before may return
+// a replacement raw value and after a replacement result; either may be nil.
+func Hooks(before func(a Attribute) interface{}, after func(a Attribute, result interface{}) interface{}) Middleware {
+	return func(next TransformFunc) TransformFunc {
+		return func(a Attribute) (interface{}, bool) {
+			if before != nil {
+				a.Value = before(a)
+			}
+			result, ok := next(a)
+			if ok && after != nil {
+				result = after(a, result)
+			}
+			return result, ok
+		}
+	}
+}
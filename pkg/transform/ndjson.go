@@ -0,0 +1,187 @@
+package transform
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// TransformNDJSON reads newline-delimited JSON records from r, transforms each
+// one independently and writes the results to w, one record per line. Input
+// holding a single array of records is read one element at a time too.
+// Only a single record is held in memory at a time, or one per goroutine
+// with WithParallelism, in which case output keeps the input order. Records
+// are checked with Check, so with OnErrorFail an invalid value fails the
+// stream with a *RecordError wrapping its *PathError.
+func (t *Transformer) TransformNDJSON(r io.Reader, w io.Writer) error {
+	return t.TransformNDJSONContext(context.Background(), r, w)
+}
+
+// TransformNDJSONContext is TransformNDJSON stopping once ctx is done, with
+// a *RecordError wrapping the error of ctx, even if the input ended in the
+// meantime. Records already transformed, and those before a record failing
+// for any other reason, are still written.
+func (t *Transformer) TransformNDJSONContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	return streamNDJSON(ctx, NewContextReader(ctx, r), w, withContext(ctx, t.filtered(t.TransformRecord, false)), t.parallelism)
+}
+
+// ReverseNDJSON is like TransformNDJSON but converts plain records into typed JSON.
+func (t *Transformer) ReverseNDJSON(r io.Reader, w io.Writer) error {
+	return t.ReverseNDJSONContext(context.Background(), r, w)
+}
+
+// ReverseNDJSONContext is ReverseNDJSON stopping once ctx is done, like
+// TransformNDJSONContext.
+func (t *Transformer) ReverseNDJSONContext(ctx context.Context, r io.Reader, w io.Writer) error {
+	reverse := func(_ int, record map[string]interface{}) (map[string]interface{}, error) {
+		return t.Reverse(record), nil
+	}
+	return streamNDJSON(ctx, NewContextReader(ctx, r), w, withContext(ctx, t.filtered(reverse, true)), t.parallelism)
+}
+
+// recordFunc transforms the record at index of a record stream.
+type recordFunc func(index int, record map[string]interface{}) (map[string]interface{}, error)
+
+// streamNDJSON applies fn to every record read from r, newline-delimited or
+// in a root array, and writes the results to w with the default codec,
+// transforming up to parallelism records at once. Records for which fn
+// returns a nil map are left out. The results written before a failure are
+// flushed to w as well. The stream fails once ctx is done.
+func streamNDJSON(ctx context.Context, r io.Reader, w io.Writer, fn recordFunc, parallelism int) (err error) {
+	dec := NewRecordDecoder(r)
+	bw := bufio.NewWriter(w)
+	enc := codec.Default().NewEncoder(bw)
+	defer func() {
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+
+	if parallelism > 1 {
+		return streamNDJSONParallel(ctx, dec, enc, fn, parallelism)
+	}
+
+	// The decoded record is only needed until its result is encoded, so one
+	// map is cleared and reused for every record
+	record := make(map[string]interface{})
+	for index := 0; ; index++ {
+		clear(record)
+		if err := dec.Decode(&record); err == io.EOF {
+			// Input ending after ctx is done may have been cut short
+			if err := ctx.Err(); err != nil {
+				return &RecordError{Index: index, Err: err}
+			}
+			return nil
+		} else if err != nil {
+			return &RecordError{Index: index, Err: err}
+		}
+
+		out, err := fn(index, record)
+		if err != nil {
+			return &RecordError{Index: index, Err: err}
+		}
+		if out == nil {
+			continue
+		}
+		// Encode appends the trailing newline for us
+		if err := enc.Encode(out); err != nil {
+			return &RecordError{Index: index, Err: err}
+		}
+	}
+}
+
+// RecordError is an error reading, transforming or writing one record of a
+// newline-delimited JSON stream.
+type RecordError struct {
+	// Index is the zero-based position of the record in the stream.
+	Index int
+	Err   error
+}
+
+// Error returns the message of the underlying error prefixed with the record index.
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// recordResult is the outcome of transforming one NDJSON record.
+type recordResult struct {
+	index  int
+	record map[string]interface{}
+	err    error
+	panic  interface{}
+}
+
+// streamNDJSONParallel decodes records on one goroutine and transforms them
+// on up to parallelism others, writing the results in input order.
+func streamNDJSONParallel(ctx context.Context, dec codec.Decoder, enc codec.Encoder, fn recordFunc, parallelism int) error {
+	// pending holds one result channel per record in input order; its buffer
+	// bounds how many records are in flight
+	pending := make(chan chan recordResult, parallelism)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(pending)
+		for index := 0; ; index++ {
+			var record map[string]interface{}
+			err := dec.Decode(&record)
+			if err == io.EOF {
+				return
+			}
+
+			result := make(chan recordResult, 1)
+			select {
+			case pending <- result:
+			case <-done:
+				return
+			}
+			if err != nil {
+				result <- recordResult{index: index, err: err}
+				return
+			}
+
+			go func(index int) {
+				// Hand rule panics back to the writing goroutine
+				defer func() {
+					if r := recover(); r != nil {
+						result <- recordResult{index: index, panic: r}
+					}
+				}()
+				out, err := fn(index, record)
+				result <- recordResult{index: index, record: out, err: err}
+			}(index)
+		}
+	}()
+
+	index := 0
+	for result := range pending {
+		res := <-result
+		if res.panic != nil {
+			panic(res.panic)
+		}
+		if res.err != nil {
+			return &RecordError{Index: res.index, Err: res.err}
+		}
+		if res.record == nil {
+			continue
+		}
+		if err := enc.Encode(res.record); err != nil {
+			return &RecordError{Index: res.index, Err: err}
+		}
+		index = res.index + 1
+	}
+
+	// Input ending after ctx is done may have been cut short
+	if err := ctx.Err(); err != nil {
+		return &RecordError{Index: index, Err: err}
+	}
+	return nil
+}
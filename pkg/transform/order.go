@@ -0,0 +1,203 @@
+package transform
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+)
+
+// KeyOrder records the order in which the keys of every object of a parsed
+// JSON document appeared, so output can keep it. Parse with RecordKeyOrder
+// to fill one in and pass it to OrderKeys. The zero value records no order.
+type KeyOrder struct {
+	// keys are the keys of an object in input order, repeated keys once
+	keys []string
+	// children are the orders of the objects and arrays held by the keys
+	children map[string]*KeyOrder
+	// elems are the orders of the elements of an array, nil for scalars
+	elems []*KeyOrder
+}
+
+// RecordKeyOrder returns a ParseOption recording the order of the keys of
+// every object of the parsed document into order, replacing what it held.
+func RecordKeyOrder(order *KeyOrder) ParseOption {
+	return func(c *parseConfig) {
+		c.keyOrder = order
+	}
+}
+
+// setChild records child as the order of the value held by key, the last
+// one winning for repeated keys as it does when decoding.
+func (k *KeyOrder) setChild(key string, child *KeyOrder) {
+	if k == nil {
+		return
+	}
+	if child == nil {
+		delete(k.children, key)
+		return
+	}
+	if k.children == nil {
+		k.children = make(map[string]*KeyOrder)
+	}
+	k.children[key] = child
+}
+
+// child returns the order of the value held by key, or nil.
+func (k *KeyOrder) child(key string) *KeyOrder {
+	if k == nil {
+		return nil
+	}
+	return k.children[key]
+}
+
+// Item returns the key order of the element at index i of an array, such as
+// a document of input parsed with AcceptArray, or nil if there is none.
+func (k *KeyOrder) Item(i int) *KeyOrder {
+	if k == nil || i >= len(k.elems) {
+		return nil
+	}
+	return k.elems[i]
+}
+
+// has reports whether the object recorded key.
+func (k *KeyOrder) has(key string) bool {
+	if k == nil {
+		return false
+	}
+	for _, known := range k.keys {
+		if known == key {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderedMap is a JSON object marshaled with its keys in the order of Keys
+// rather than sorted, such as the output of OrderKeys.
+type OrderedMap struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// MarshalJSON encodes the object with the default codec, keys in order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := codec.Default().Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := codec.Default().Marshal(m.Values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// OrderKeys returns doc, the result of Transform or Reverse for the input
+// whose key order was recorded in order, with every object turned into an
+// OrderedMap listing its keys in the order of the input keys they came from.
+// Type tag wrappers on either side are looked through, and renamed keys are
+// traced back to their input keys. Keys with no input key, such as those
+// added by WithDefaults or joined by WithFlatten, follow in lexicographic
+// order.
+func (t *Transformer) OrderKeys(doc map[string]interface{}, order *KeyOrder) *OrderedMap {
+	return t.orderObject(doc, order)
+}
+
+// orderValue orders the objects within v by order, the key order of the
+// input value v came from.
+func (t *Transformer) orderValue(v interface{}, order *KeyOrder) interface{} {
+	order = t.unwrapOrder(v, order)
+	switch val := v.(type) {
+	case map[string]interface{}:
+		// Output wrapping a value in a type tag the input did not have
+		if len(val) == 1 {
+			for key, inner := range val {
+				if _, tag := t.rules.lookup(key); tag && !order.has(key) {
+					return &OrderedMap{Keys: []string{key}, Values: map[string]interface{}{key: t.orderValue(inner, order)}}
+				}
+			}
+		}
+		return t.orderObject(val, order)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = t.orderValue(item, order.Item(i))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// unwrapOrder returns the order of the value within the type tag wrapping
+// the input of v, when v no longer has that wrapper, or else order.
+func (t *Transformer) unwrapOrder(v interface{}, order *KeyOrder) *KeyOrder {
+	if order == nil || len(order.keys) != 1 {
+		return order
+	}
+	tag := order.keys[0]
+	if _, ok := t.rules.lookup(tag); !ok {
+		return order
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		if _, kept := m[tag]; kept {
+			return order
+		}
+	}
+	return order.child(tag)
+}
+
+// orderObject orders the keys of m, and the objects within it, by order.
+func (t *Transformer) orderObject(m map[string]interface{}, order *KeyOrder) *OrderedMap {
+	// Each output key ranks at the first input key it matches, as is or
+	// renamed the way the transformation renames keys
+	rank := make(map[string]int)
+	source := make(map[string]string)
+	if order != nil {
+		for i, key := range order.keys {
+			for _, name := range []string{key, t.outputKey(sanitizeKey(key))} {
+				if _, ok := rank[name]; !ok {
+					rank[name], source[name] = i, key
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iok := rank[keys[i]]
+		rj, jok := rank[keys[j]]
+		if iok != jok {
+			return iok
+		}
+		if iok && ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+
+	values := make(map[string]interface{}, len(m))
+	for _, key := range keys {
+		var child *KeyOrder
+		if src, ok := source[key]; ok {
+			child = order.child(src)
+		}
+		values[key] = t.orderValue(m[key], child)
+	}
+	return &OrderedMap{Keys: keys, Values: values}
+}
@@ -0,0 +1,63 @@
+package transform
+
+import (
+	"sort"
+	"sync"
+)
+
+// attributeResult is the outcome of transforming one top-level attribute.
+type attributeResult struct {
+	key   string
+	value interface{}
+	ok    bool
+}
+
+// transformParallel transforms the top-level attributes of inputMap on up to
+// t.parallelism goroutines. Results are merged in sorted input key order, so
+// keys that collide once sanitized resolve the same way on every run.
+func (t *Transformer) transformParallel(inputMap map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(inputMap))
+	for key := range inputMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]attributeResult, len(keys))
+	workers := t.parallelism
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+
+	// Each worker takes every workers-th key, so no coordination is needed
+	var wg sync.WaitGroup
+	panics := make(chan interface{}, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Hand rule panics back to the caller's goroutine
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+				}
+			}()
+			for i := w; i < len(keys); i += workers {
+				key, value, ok := t.transformAttribute(nil, keys[i], inputMap[keys[i]])
+				results[i] = attributeResult{key: key, value: value, ok: ok}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(panics)
+	if r, ok := <-panics; ok {
+		panic(r)
+	}
+
+	output := make(map[string]interface{}, len(results))
+	for _, result := range results {
+		if result.ok {
+			output[result.key] = result.value
+		}
+	}
+	return output
+}
@@ -0,0 +1,196 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+)
+
+// ErrTrailingData is returned when the input holds more than one JSON value.
+var ErrTrailingData = errors.New("invalid character after top-level value")
+
+// ParseSchema reads and parses the JSON schema file, applying opts as
+// ParseReader does. Files named .yaml or .yml are parsed with
+// ParseYAMLReader instead, and files named .cbor with ParseCBORReader.
+func ParseSchema(fileName string, opts ...ParseOption) (map[string]interface{}, error) {
+	return ParseSchemaContext(context.Background(), fileName, opts...)
+}
+
+// ParseSchemaContext is ParseSchema recorded as a span of the trace in ctx.
+// Reading the file fails with the error of ctx once ctx is done.
+func ParseSchemaContext(ctx context.Context, fileName string, opts ...ParseOption) (_ map[string]interface{}, err error) {
+	_, span := tracer.Start(ctx, "ParseSchema", trace.WithAttributes(attribute.String("file", fileName)))
+	defer func() { endSpan(span, err) }()
+
+	// Check if the file is a JSON, YAML or CBOR file
+	yamlFile, cborFile := IsYAMLFile(fileName), IsCBORFile(fileName)
+	if !yamlFile && !cborFile && !strings.Contains(fileName, ".json") {
+		return nil, ErrNotJSONFile
+	}
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Decompress .gz and .zst files, such as DynamoDB exports, on the fly
+	r, err := compress.NewReader(fileName, f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	in := NewContextReader(ctx, r)
+	switch {
+	case yamlFile:
+		return ParseYAMLReader(in, opts...)
+	case cborFile:
+		return ParseCBORReader(in, opts...)
+	case IsJSONCFile(fileName):
+		opts = append(opts[:len(opts):len(opts)], AllowComments())
+	}
+	return ParseReader(in, opts...)
+}
+
+// ParseReader reads and parses a JSON document from r, such as os.Stdin.
+// Options can limit the input or reject duplicate keys, which are otherwise
+// resolved by keeping the last value. UTF-16 input and byte order marks are
+// handled by NewTextReader.
+func ParseReader(r io.Reader, opts ...ParseOption) (map[string]interface{}, error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	inputBytes, err := readInput(NewTextReader(r), cfg)
+	if err != nil {
+		return nil, err
+	}
+	return parseChecked(inputBytes, cfg)
+}
+
+// readInput reads all of r, failing with a LimitError past the size limit of
+// cfg.
+func readInput(r io.Reader, cfg parseConfig) ([]byte, error) {
+	// Read one byte past the limit to tell whether it was exceeded
+	if cfg.maxSize > 0 {
+		r = io.LimitReader(r, cfg.maxSize+1)
+	}
+	inputBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.maxSize > 0 && int64(len(inputBytes)) > cfg.maxSize {
+		return nil, &LimitError{Limit: "input size", Max: cfg.maxSize}
+	}
+	return inputBytes, nil
+}
+
+// parseChecked parses the JSON document data after the checks of cfg. With
+// AcceptArray, the documents of a root array are stored instead.
+func parseChecked(data []byte, cfg parseConfig) (map[string]interface{}, error) {
+	if cfg.allowComments {
+		data = StripComments(data)
+	}
+	if cfg.scan() {
+		if err := scanDocument(data, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.items != nil {
+		*cfg.items = nil
+		if isArray(data) {
+			items := []map[string]interface{}{}
+			if err := decodeDocument(data, &items); err != nil {
+				return nil, err
+			}
+			*cfg.items = items
+			return nil, nil
+		}
+	}
+	return parseBytes(data)
+}
+
+// parseBytes unmarshals the JSON content into a map with the default codec.
+// Plain numbers are kept as json.Number so large integers are not rounded
+// through float64.
+func parseBytes(data []byte) (map[string]interface{}, error) {
+	var output map[string]interface{}
+	if err := decodeDocument(data, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// decodeDocument unmarshals the single JSON value in data into v with the
+// default codec.
+func decodeDocument(data []byte, v interface{}) error {
+	dec := codec.Default().NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(v); err != nil {
+		return locate(data, err)
+	}
+
+	// Match json.Unmarshal, which rejects anything after the top-level value
+	var extra interface{}
+	if err := dec.Decode(&extra); err != io.EOF {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+// SyntaxError locates an error decoding a JSON document by the line and
+// column of the input it occurred at.
+type SyntaxError struct {
+	// Line and Column are one-based; Column counts bytes.
+	Line, Column int
+	Err          error
+}
+
+// Error returns the message of the underlying error prefixed with its location.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// locate wraps an error from decoding data in a *SyntaxError when it carries
+// the byte offset it occurred at, as encoding/json errors do.
+func locate(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	// The offset is just past the offending byte
+	before := data[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return &SyntaxError{
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: max(len(before)-lineStart, 1),
+		Err:    err,
+	}
+}
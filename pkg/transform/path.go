@@ -0,0 +1,181 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// JSONPointer formats path as an RFC 6901 JSON Pointer such as
+// "/users/3/createdAt", escaping "~" and "/" within keys.
+func JSONPointer(path []string) string {
+	var pointer string
+	for _, key := range path {
+		pointer = schemautil.JoinPointer(pointer, key)
+	}
+	return pointer
+}
+
+// TypeError reports a value whose underlying JSON type does not match its
+// type tag, such as {"S": 1}. The built-in rules return it instead of
+// panicking, which omits the value like any other invalid result.
+type TypeError struct {
+	// Type is the type tag wrapping the value.
+	Type string
+	// Want names the JSON type the tag requires, such as "a string".
+	Want string
+	// Value is the offending raw value.
+	Value interface{}
+}
+
+// Error describes the mismatch, such as "S must wrap a string, found number".
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("%s must wrap %s, found %s", e.Type, e.Want, valueKind(e.Value))
+}
+
+// Is reports whether target is ErrTypeMismatch.
+func (e *TypeError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// PathError locates an error at a value within a document.
+type PathError struct {
+	// Pointer is the JSON Pointer of the value, such as "/users/3/createdAt".
+	Pointer string
+	Err     error
+}
+
+// Error returns the message of the underlying error prefixed with the
+// pointer, unless it points at the whole document.
+func (e *PathError) Error() string {
+	if e.Pointer == "" {
+		return e.Err.Error()
+	}
+	return e.Pointer + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// CheckTypes checks that every value wrapped by a built-in type tag in input
+// has the underlying JSON type the tag requires. It returns a *PathError
+// wrapping a *TypeError for the first mismatch in key order, which Transform
+// would otherwise silently omit. Values of custom tags are not checked.
+func (t *Transformer) CheckTypes(input map[string]interface{}) error {
+	c := &typeChecker{t: t}
+	return c.object(input)
+}
+
+// typeChecker walks a typed document, tracking the path of the current value.
+type typeChecker struct {
+	t    *Transformer
+	path []string
+}
+
+// object checks every attribute of the typed document m.
+func (c *typeChecker) object(m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		// Plain values and untagged maps are dropped, not mistyped
+		typed, ok := m[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c.path = append(c.path, key)
+		for typeKey, raw := range typed {
+			if err := c.value(sanitizeKey(typeKey), raw); err != nil {
+				return err
+			}
+		}
+		c.path = c.path[:len(c.path)-1]
+	}
+	return nil
+}
+
+// value checks the raw value wrapped by typeKey at the current path.
+func (c *typeChecker) value(typeKey string, raw interface{}) error {
+	switch typeKey {
+	case "S", "N", "B":
+		if _, ok := raw.(string); !ok {
+			return c.fail(typeKey, "a string", raw)
+		}
+	case "BOOL":
+		switch raw.(type) {
+		case bool, string:
+		default:
+			return c.fail(typeKey, "a boolean or string", raw)
+		}
+	case "SS", "NS", "BS":
+		list, ok := raw.([]interface{})
+		if !ok {
+			return c.fail(typeKey, "an array", raw)
+		}
+		return c.elements(list, func(item interface{}) error {
+			return c.value(strings.TrimSuffix(typeKey, "S"), item)
+		})
+	case "M":
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return c.fail(typeKey, "an object", raw)
+		}
+		return c.object(m)
+	case "L":
+		list, ok := raw.([]interface{})
+		if !ok {
+			return c.fail(typeKey, "an array", raw)
+		}
+		return c.elements(list, func(item interface{}) error {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			if typeKey, typed, ok := c.t.typedValue(m); ok {
+				return c.value(typeKey, typed)
+			}
+			return c.object(m)
+		})
+	}
+	return nil
+}
+
+// elements runs check on every element of list, addressed by its index.
+func (c *typeChecker) elements(list []interface{}, check func(item interface{}) error) error {
+	for i, item := range list {
+		c.path = append(c.path, strconv.Itoa(i))
+		if err := check(item); err != nil {
+			return err
+		}
+		c.path = c.path[:len(c.path)-1]
+	}
+	return nil
+}
+
+// fail returns a *PathError for a raw value of typeKey that is not want.
+func (c *typeChecker) fail(typeKey, want string, raw interface{}) error {
+	return &PathError{Pointer: JSONPointer(c.path), Err: &TypeError{Type: typeKey, Want: want, Value: raw}}
+}
+
+// TransformChecked is Transform for callers honouring OnErrorFail: it
+// returns the error from Check without transforming anything when input has
+// an invalid value, and the error from CheckOutput when the output lacks a
+// required field.
+func (t *Transformer) TransformChecked(input map[string]interface{}) (map[string]interface{}, error) {
+	return t.TransformRecord(0, input)
+}
+
+// infallible adapts fn to the signature of the checked transformations.
+func infallible(fn func(map[string]interface{}) map[string]interface{}) func(map[string]interface{}) (map[string]interface{}, error) {
+	return func(input map[string]interface{}) (map[string]interface{}, error) {
+		return fn(input), nil
+	}
+}
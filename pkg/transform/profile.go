@@ -0,0 +1,116 @@
+package transform
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile records the cumulative time spent transforming values and the
+// number of calls, per type tag and per key path, to find hot spots in huge
+// documents. Times are inclusive, so an M or L value also accounts for the
+// values nested in it. List indices, and any other all-digit key, are
+// recorded as "#" so the elements of a list share one path. A Profile is
+// safe for concurrent use.
+type Profile struct {
+	mu    sync.Mutex
+	rules map[string]*ProfileEntry
+	paths map[string]*ProfileEntry
+}
+
+// ProfileEntry is the time recorded for one type tag or key path.
+type ProfileEntry struct {
+	Name  string
+	Calls int64
+	Total time.Duration
+}
+
+// Average returns the mean time per call.
+func (e ProfileEntry) Average() time.Duration {
+	if e.Calls == 0 {
+		return 0
+	}
+	return e.Total / time.Duration(e.Calls)
+}
+
+// NewProfile returns an empty Profile.
+func NewProfile() *Profile {
+	return &Profile{
+		rules: make(map[string]*ProfileEntry),
+		paths: make(map[string]*ProfileEntry),
+	}
+}
+
+// Middleware returns middleware timing every value it transforms into p.
+// Register it with WithMiddleware.
+func (p *Profile) Middleware() Middleware {
+	return func(next TransformFunc) TransformFunc {
+		return func(a Attribute) (interface{}, bool) {
+			start := time.Now()
+			result, ok := next(a)
+			p.record(a, time.Since(start))
+			return result, ok
+		}
+	}
+}
+
+// record adds one call taking elapsed to the entries for a's type and path.
+func (p *Profile) record(a Attribute, elapsed time.Duration) {
+	path := profilePath(a.Path)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	addProfileEntry(p.rules, a.Type, elapsed)
+	addProfileEntry(p.paths, path, elapsed)
+}
+
+// addProfileEntry adds one call taking elapsed to the entry for name.
+func addProfileEntry(entries map[string]*ProfileEntry, name string, elapsed time.Duration) {
+	entry, ok := entries[name]
+	if !ok {
+		entry = &ProfileEntry{Name: name}
+		entries[name] = entry
+	}
+	entry.Calls++
+	entry.Total += elapsed
+}
+
+// profilePath joins path with dots, replacing all-digit keys with "#".
+func profilePath(path []string) string {
+	parts := make([]string, len(path))
+	for i, key := range path {
+		parts[i] = key
+		if key != "" && strings.Trim(key, "0123456789") == "" {
+			parts[i] = "#"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Rules returns the entries per type tag, slowest first.
+func (p *Profile) Rules() []ProfileEntry {
+	return p.sorted(p.rules)
+}
+
+// Paths returns the entries per key path, slowest first.
+func (p *Profile) Paths() []ProfileEntry {
+	return p.sorted(p.paths)
+}
+
+// sorted returns a copy of entries ordered by total time, then name.
+func (p *Profile) sorted(entries map[string]*ProfileEntry) []ProfileEntry {
+	p.mu.Lock()
+	out := make([]ProfileEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, *entry)
+	}
+	p.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
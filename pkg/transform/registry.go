@@ -0,0 +1,134 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrRuleExists is returned by RegisterRule when the type tag already
+	// has a rule; use OverrideRule to replace it.
+	ErrRuleExists = errors.New("rule already registered")
+	// ErrRuleNotFound is returned by DeregisterRule when the type tag has no rule.
+	ErrRuleNotFound = errors.New("rule not registered")
+)
+
+// Registry maps type tags such as "S" or "N" to the rules that transform
+// their values. It is safe for concurrent use, so rules can be added or
+// replaced while documents are being transformed.
+type Registry struct {
+	mu    sync.RWMutex
+	rules map[string]ruleEntry
+}
+
+// ruleEntry is a registered rule. The built-in M and L rules also carry a
+// nested variant that tracks the key path of the values they recurse into.
+type ruleEntry struct {
+	rule   TransformationRule
+	nested func(path []string, v interface{}) interface{}
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{rules: make(map[string]ruleEntry)}
+}
+
+// RegisterRule adds rule for typeKey, such as a custom "TS" or "UUID" tag.
+// It fails if typeKey already has a rule.
+func (r *Registry) RegisterRule(typeKey string, rule TransformationRule) error {
+	if rule == nil {
+		return fmt.Errorf("rule for %q is nil", typeKey)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[typeKey]; ok {
+		return fmt.Errorf("%q: %w", typeKey, ErrRuleExists)
+	}
+	r.rules[typeKey] = ruleEntry{rule: rule}
+	return nil
+}
+
+// OverrideRule sets rule for typeKey, replacing any existing rule. It fails
+// if rule is nil; use DeregisterRule to remove a rule.
+func (r *Registry) OverrideRule(typeKey string, rule TransformationRule) error {
+	if rule == nil {
+		return fmt.Errorf("rule for %q is nil", typeKey)
+	}
+	r.set(typeKey, ruleEntry{rule: rule})
+	return nil
+}
+
+// overrideNested sets rule for typeKey along with its path-tracking variant.
+func (r *Registry) overrideNested(typeKey string, rule TransformationRule, nested func(path []string, v interface{}) interface{}) {
+	r.set(typeKey, ruleEntry{rule: rule, nested: nested})
+}
+
+// set registers entry for typeKey, replacing any existing one.
+func (r *Registry) set(typeKey string, entry ruleEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[typeKey] = entry
+}
+
+// DeregisterRule removes the rule for typeKey, so values tagged with it are
+// treated as unknown types.
+func (r *Registry) DeregisterRule(typeKey string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rules[typeKey]; !ok {
+		return fmt.Errorf("%q: %w", typeKey, ErrRuleNotFound)
+	}
+	delete(r.rules, typeKey)
+	return nil
+}
+
+// Rule returns the rule registered for typeKey.
+func (r *Registry) Rule(typeKey string) (TransformationRule, bool) {
+	entry, ok := r.lookup(typeKey)
+	return entry.rule, ok
+}
+
+// lookup returns the entry registered for typeKey.
+func (r *Registry) lookup(typeKey string) (ruleEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.rules[typeKey]
+	return entry, ok
+}
+
+// Types returns the registered type tags in sorted order.
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.rules))
+	for typeKey := range r.rules {
+		types = append(types, typeKey)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Registry returns the rules used by t. Changes to it take effect
+// immediately, including for documents being transformed concurrently.
+func (t *Transformer) Registry() *Registry {
+	return t.rules
+}
+
+// RegisterRule adds a rule for typeKey to the rules used by TransformJSON
+// and the other package-level functions.
+func RegisterRule(typeKey string, rule TransformationRule) error {
+	return std.rules.RegisterRule(typeKey, rule)
+}
+
+// OverrideRule replaces the rule for typeKey used by the package-level
+// functions. It fails if rule is nil.
+func OverrideRule(typeKey string, rule TransformationRule) error {
+	return std.rules.OverrideRule(typeKey, rule)
+}
+
+// DeregisterRule removes the rule for typeKey used by the package-level functions.
+func DeregisterRule(typeKey string) error {
+	return std.rules.DeregisterRule(typeKey)
+}
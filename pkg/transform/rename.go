@@ -0,0 +1,70 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// KeyRename renames output keys, either exactly matching From or matching
+// Pattern, at every nesting level.
+type KeyRename struct {
+	// From is the exact key to rename. It is ignored when Pattern is set.
+	From string
+	// Pattern matches the keys to rename. The matched parts of a key are
+	// replaced with To, which may refer to submatches as $1 or ${name}.
+	Pattern *regexp.Regexp
+	// To is the new key, or the replacement of Pattern.
+	To string
+}
+
+// ExactRename returns a KeyRename renaming the key from to to.
+func ExactRename(from, to string) KeyRename {
+	return KeyRename{From: from, To: to}
+}
+
+// PatternRename returns a KeyRename replacing the parts of keys matching the
+// regular expression pattern with replacement, as regexp.ReplaceAllString
+// does, so "^(.*)_id$" and "${1}Id" rename user_id to userId.
+func PatternRename(pattern, replacement string) (KeyRename, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return KeyRename{}, fmt.Errorf("rename pattern %q: %w", pattern, err)
+	}
+	return KeyRename{Pattern: re, To: replacement}, nil
+}
+
+// WithKeyRenames renames the keys of the output at every nesting level. Each
+// key is renamed by the first of renames matching it, with exact renames
+// taking precedence over patterns, and keys matching none are kept. Keys
+// renamed to an empty string are dropped. Paths seen by middleware and
+// reported by Validate keep the input keys, and Reverse does not rename.
+func WithKeyRenames(renames ...KeyRename) Option {
+	return func(t *Transformer) {
+		for _, r := range renames {
+			if r.Pattern != nil {
+				t.renamePatterns = append(t.renamePatterns, r)
+				continue
+			}
+			if t.renames == nil {
+				t.renames = make(map[string]string)
+			}
+			if _, ok := t.renames[r.From]; !ok {
+				t.renames[r.From] = r.To
+			}
+		}
+	}
+}
+
+// outputKey returns the output key for the sanitized input key: its rename,
+// if any matches, or else the key in the configured case.
+func (t *Transformer) outputKey(key string) string {
+	if to, ok := t.renames[key]; ok {
+		return to
+	}
+	for _, r := range t.renamePatterns {
+		if r.Pattern.MatchString(key) {
+			return r.Pattern.ReplaceAllString(key, r.To)
+		}
+	}
+	return convertCase(key, t.keyCase)
+}
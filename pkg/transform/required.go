@@ -0,0 +1,117 @@
+package transform
+
+import "strings"
+
+// WarningHandler receives the warnings found in transformed output, such as
+// missing required fields or schema violations, along with the zero-based
+// index of the record within its stream; whole documents are record 0.
+type WarningHandler func(record int, w Warning)
+
+// WithRequired declares output fields every transformed document must have,
+// by dotted path after any renaming, such as "id" or "address.city". Missing
+// fields are reported by CheckOutput: with OnErrorFail as an error and
+// otherwise to the handler set with WithWarningHandler. Defaults set with
+// WithDefaults are applied before the check.
+func WithRequired(fields ...string) Option {
+	return func(t *Transformer) {
+		for _, field := range fields {
+			t.required = append(t.required, strings.Split(field, "."))
+		}
+	}
+}
+
+// WithOutputCheck adds check to the checks of CheckOutput, such as
+// validation against a schema. It returns a warning for every problem with a
+// transformed document.
+func WithOutputCheck(check func(output map[string]interface{}) []Warning) Option {
+	return func(t *Transformer) {
+		t.outputChecks = append(t.outputChecks, check)
+	}
+}
+
+// WithWarningHandler sets the handler receiving the warnings CheckOutput
+// finds when the error mode is not OnErrorFail.
+func WithWarningHandler(h WarningHandler) Option {
+	return func(t *Transformer) {
+		t.warn = h
+	}
+}
+
+// MissingFields returns a warning wrapping ErrMissingField for every required
+// field absent from the transformed output, in declaration order.
+func (t *Transformer) MissingFields(output map[string]interface{}) []Warning {
+	var warnings []Warning
+	for _, path := range t.required {
+		if !hasField(output, path) && !t.hasFlattened(output, path) {
+			warnings = append(warnings, Warning{Path: JSONPointer(path), Reason: ErrMissingField.Error(), Err: ErrMissingField})
+		}
+	}
+	return warnings
+}
+
+// OutputWarnings returns the warnings for the transformed output: the missing
+// required fields followed by those of the checks added with
+// WithOutputCheck.
+func (t *Transformer) OutputWarnings(output map[string]interface{}) []Warning {
+	warnings := t.MissingFields(output)
+	for _, check := range t.outputChecks {
+		warnings = append(warnings, check(output)...)
+	}
+	return warnings
+}
+
+// CheckOutput checks the transformed output of the record at index with
+// OutputWarnings. With OnErrorFail it returns the first warning as a
+// *PathError; otherwise each is passed to the warning handler and it
+// returns nil.
+func (t *Transformer) CheckOutput(index int, output map[string]interface{}) error {
+	warnings := t.OutputWarnings(output)
+	if len(warnings) == 0 {
+		return nil
+	}
+	if t.onError == OnErrorFail {
+		return &PathError{Pointer: warnings[0].Path, Err: warnings[0].Err}
+	}
+	if t.warn != nil {
+		for _, w := range warnings {
+			t.warn(index, w)
+		}
+	}
+	return nil
+}
+
+// TransformRecord is TransformChecked for the record at index of a record
+// stream, also checking its output with CheckOutput.
+func (t *Transformer) TransformRecord(index int, input map[string]interface{}) (map[string]interface{}, error) {
+	if err := t.Check(input); err != nil {
+		return nil, err
+	}
+	output := t.Transform(input)
+	if err := t.CheckOutput(index, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// hasFlattened reports whether output, reshaped with WithFlatten or
+// WithUnflatten, holds the key joining path.
+func (t *Transformer) hasFlattened(output map[string]interface{}, path []string) bool {
+	if t.reshape == nil || len(path) < 2 {
+		return false
+	}
+	_, ok := output[strings.Join(path, t.delimiter)]
+	return ok
+}
+
+// hasField reports whether doc holds a value, which may be null, at path.
+func hasField(doc map[string]interface{}, path []string) bool {
+	for _, key := range path[:len(path)-1] {
+		next, ok := doc[key].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		doc = next
+	}
+	_, ok := doc[path[len(path)-1]]
+	return ok
+}
@@ -0,0 +1,101 @@
+package transform
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// ReverseJSON converts plain JSON into typed JSON with the default settings.
+func ReverseJSON(inputMap map[string]interface{}) map[string]interface{} {
+	return std.Reverse(inputMap)
+}
+
+// Reverse converts plain JSON into DynamoDB AttributeValue-style JSON, wrapping
+// every value in its S, N, BOOL, NULL, M or L type key. As in DynamoDB JSON,
+// S and N values are strings while BOOL and NULL values are booleans, so the
+// result can be fed back through Transform. With WithReverseSets, arrays of
+// unique strings or numbers become SS or NS sets.
+func (t *Transformer) Reverse(inputMap map[string]interface{}) map[string]interface{} {
+	if t.unshape != nil {
+		inputMap = t.unshape(inputMap, t.delimiter)
+	}
+	output := make(map[string]interface{}, len(inputMap))
+	for key, value := range inputMap {
+		if typed := t.reverseValue(value); typed != nil {
+			output[key] = typed
+		}
+	}
+	return output
+}
+
+// reverseValue wraps a single plain value in its type key.
+// It returns nil for values that have no typed representation.
+func (t *Transformer) reverseValue(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case nil:
+		return map[string]interface{}{"NULL": true}
+	case string:
+		return map[string]interface{}{"S": val}
+	case bool:
+		return map[string]interface{}{"BOOL": val}
+	case float64:
+		return map[string]interface{}{"N": strconv.FormatFloat(val, 'f', -1, 64)}
+	case json.Number:
+		return map[string]interface{}{"N": val.String()}
+	case map[string]interface{}:
+		return map[string]interface{}{"M": t.Reverse(val)}
+	case []interface{}:
+		if t.reverseSets {
+			if set := reverseSet(val); set != nil {
+				return set
+			}
+		}
+		list := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			if typed := t.reverseValue(item); typed != nil {
+				list = append(list, typed)
+			}
+		}
+		return map[string]interface{}{"L": list}
+	default:
+		return nil
+	}
+}
+
+// reverseSet encodes val as an SS or NS set when it is non-empty and holds
+// only unique strings or only unique numbers. It returns nil otherwise.
+func reverseSet(val []interface{}) map[string]interface{} {
+	if len(val) == 0 {
+		return nil
+	}
+
+	var setType string
+	elems := make([]interface{}, 0, len(val))
+	seen := make(map[string]bool, len(val))
+	for _, item := range val {
+		var elemType, elem string
+		switch v := item.(type) {
+		case string:
+			elemType, elem = "SS", v
+		case float64:
+			elemType, elem = "NS", strconv.FormatFloat(v, 'f', -1, 64)
+		case json.Number:
+			elemType, elem = "NS", v.String()
+		default:
+			return nil
+		}
+
+		// Sets cannot mix element types or contain duplicates
+		if setType != "" && setType != elemType {
+			return nil
+		}
+		if seen[elem] {
+			return nil
+		}
+		setType = elemType
+		seen[elem] = true
+		elems = append(elems, elem)
+	}
+
+	return map[string]interface{}{setType: elems}
+}
@@ -0,0 +1,57 @@
+package transform_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// TestReverseScalars checks that Reverse writes S and N values as strings and
+// BOOL and NULL values as booleans, as DynamoDB JSON does, and that the result
+// transforms back into the original document.
+func TestReverseScalars(t *testing.T) {
+	plain := map[string]interface{}{
+		"name":    "ada",
+		"age":     json.Number("36"),
+		"active":  true,
+		"deleted": false,
+		"note":    nil,
+		"tags":    []interface{}{"a", true, nil},
+		"address": map[string]interface{}{"verified": false},
+	}
+	want := map[string]interface{}{
+		"name":    map[string]interface{}{"S": "ada"},
+		"age":     map[string]interface{}{"N": "36"},
+		"active":  map[string]interface{}{"BOOL": true},
+		"deleted": map[string]interface{}{"BOOL": false},
+		"note":    map[string]interface{}{"NULL": true},
+		"tags": map[string]interface{}{"L": []interface{}{
+			map[string]interface{}{"S": "a"},
+			map[string]interface{}{"BOOL": true},
+			map[string]interface{}{"NULL": true},
+		}},
+		"address": map[string]interface{}{"M": map[string]interface{}{
+			"verified": map[string]interface{}{"BOOL": false},
+		}},
+	}
+
+	got := transform.ReverseJSON(plain)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReverseJSON() = %#v, want %#v", got, want)
+	}
+
+	typed, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := transform.TransformBytes(typed)
+	if err != nil {
+		t.Fatalf("TransformBytes(%s) error: %v", typed, err)
+	}
+	const wantBack = `{"active":true,"address":{"verified":false},"age":36,"deleted":false,"name":"ada","note":null,"tags":["a",true,null]}`
+	if string(back) != wantBack {
+		t.Errorf("TransformBytes(%s) = %s, want %s", typed, back, wantBack)
+	}
+}
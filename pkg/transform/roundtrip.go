@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// VerifyRoundTrip transforms input, reverses the plain output, as encoded
+// and decoded again, back into typed JSON and returns a warning wrapping
+// ErrLossyRoundTrip for every attribute that does not come back as it was:
+// dropped attributes, numbers losing precision or formatting, timestamps
+// reformatted into numbers, sets turned into lists and other changed types
+// or values. Warnings are ordered by key, and Value holds the typed input.
+func (t *Transformer) VerifyRoundTrip(input map[string]interface{}) ([]Warning, error) {
+	// Reverse sees what a consumer of the encoded output would
+	encoded, err := json.Marshal(t.Transform(input))
+	if err != nil {
+		return nil, err
+	}
+	plain, err := parseBytes(encoded)
+	if err != nil {
+		return nil, err
+	}
+	r := &roundTrip{t: t}
+	r.object("", input, t.Reverse(plain))
+	return r.warnings, nil
+}
+
+// roundTrip collects the differences between a typed document and the one
+// it comes back as.
+type roundTrip struct {
+	t        *Transformer
+	warnings []Warning
+}
+
+// add records a lossy value at path.
+func (r *roundTrip) add(path string, value interface{}, format string, args ...interface{}) {
+	err := invalidValue(ErrLossyRoundTrip, format, args...)
+	r.warnings = append(r.warnings, Warning{Path: path, Value: value, Reason: err.Error(), Err: err})
+}
+
+// object compares the attributes of the typed object in, found at path, with
+// those of back.
+func (r *roundTrip) object(path string, in, back map[string]interface{}) {
+	for _, key := range sortedKeys(in) {
+		attrPath := schemautil.JoinPointer(path, key)
+		other, ok := back[sanitizeKey(key)]
+		if !ok {
+			r.add(attrPath, in[key], "dropped by the round trip")
+			continue
+		}
+		r.value(attrPath, in[key], other)
+	}
+	for _, key := range sortedKeys(back) {
+		if _, ok := in[key]; !ok {
+			r.add(schemautil.JoinPointer(path, key), back[key], "added by the round trip")
+		}
+	}
+}
+
+// value compares the typed value in, found at path, with the value back it
+// comes back as.
+func (r *roundTrip) value(path string, in, back interface{}) {
+	inMap, ok := in.(map[string]interface{})
+	if !ok {
+		return
+	}
+	inType, inValue, ok := r.t.typedValue(inMap)
+	if !ok {
+		// Untyped objects are left to Validate
+		return
+	}
+	backMap, _ := back.(map[string]interface{})
+	backType, backValue, _ := r.t.typedValue(backMap)
+
+	if inType != backType {
+		if s, ok := inValue.(string); ok && inType == "S" && backType == "N" {
+			if _, isTime := r.t.parseTime(s); isTime {
+				r.add(path, in, "timestamp %q comes back as the number %v", s, backValue)
+				return
+			}
+		}
+		r.add(path, in, "%s value comes back as %s", inType, backType)
+		return
+	}
+
+	switch inType {
+	case "M":
+		inObj, _ := inValue.(map[string]interface{})
+		backObj, _ := backValue.(map[string]interface{})
+		r.object(path, inObj, backObj)
+	case "L", "SS", "NS", "BS":
+		inList, _ := inValue.([]interface{})
+		backList, _ := backValue.([]interface{})
+		for i, elem := range inList {
+			elemPath := fmt.Sprintf("%s/%d", path, i)
+			if i >= len(backList) {
+				r.add(elemPath, elem, "dropped by the round trip")
+				continue
+			}
+			if inType == "L" {
+				r.value(elemPath, elem, backList[i])
+			} else {
+				r.scalar(elemPath, inType[:1], elem, elem, backList[i])
+			}
+		}
+	case "NULL":
+	default:
+		r.scalar(path, inType, in, inValue, backValue)
+	}
+}
+
+// scalar compares the raw value in of the type tag typ with the raw value
+// back, reporting the typed value typed when they differ.
+func (r *roundTrip) scalar(path, typ string, typed, in, back interface{}) {
+	inText, backText := fmt.Sprint(in), fmt.Sprint(back)
+	if inText == backText {
+		return
+	}
+	if typ == "N" {
+		inNum, inOK := new(big.Rat).SetString(inText)
+		backNum, backOK := new(big.Rat).SetString(backText)
+		if inOK && backOK && inNum.Cmp(backNum) == 0 {
+			r.add(path, typed, "number %s comes back formatted as %s", inText, backText)
+		} else {
+			r.add(path, typed, "number %s loses precision, coming back as %s", inText, backText)
+		}
+		return
+	}
+	r.add(path, typed, "%s value %q comes back as %q", typ, inText, backText)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
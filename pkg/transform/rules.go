@@ -0,0 +1,313 @@
+package transform
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatString transforms string values, converting RFC3339 formatted strings to Unix Epoch.
+func FormatString(v interface{}) interface{} {
+	strVal, ok := v.(string)
+	if !ok {
+		return &TypeError{Type: "S", Want: "a string", Value: v}
+	}
+	if t, err := time.Parse(time.RFC3339, strVal); err == nil {
+		return t.Unix()
+	}
+	return strVal
+}
+
+// FormatNum transforms numeric values. Integers are kept exact as int64, or as
+// a json.Number when they overflow int64, and everything else is parsed into float64.
+func FormatNum(v interface{}) interface{} {
+	numStr, ok := v.(string)
+	if !ok {
+		return &TypeError{Type: "N", Want: "a string", Value: v}
+	}
+	if val, err := strconv.ParseInt(numStr, 10, 64); err == nil {
+		return val
+	}
+	if num, ok := bigInteger(numStr); ok {
+		return num
+	}
+	return FormatFloat(v)
+}
+
+// FormatFloat transforms numeric values, parsing them into float64. Values
+// that are not finite numbers, including "NaN" and "Infinity", become 0.
+func FormatFloat(v interface{}) interface{} {
+	numStr, ok := v.(string)
+	if !ok {
+		return &TypeError{Type: "N", Want: "a string", Value: v}
+	}
+	num := 0.0
+	if val, ok := parseFinite(numStr); ok {
+		num = val
+	}
+	return num
+}
+
+// parseFinite parses numStr into a float64, reporting whether it is a
+// number JSON can represent: strconv.ParseFloat also accepts "NaN", "Inf"
+// and "Infinity", which cannot be marshaled.
+func parseFinite(numStr string) (float64, bool) {
+	val, err := strconv.ParseFloat(numStr, 64)
+	if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, false
+	}
+	return val, true
+}
+
+// FormatBool transforms boolean values. Native JSON booleans, as found in
+// DynamoDB Streams and export payloads, are passed through.
+func FormatBool(v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	boolStr, ok := v.(string)
+	if !ok {
+		return &TypeError{Type: "BOOL", Want: "a boolean or string", Value: v}
+	}
+	switch boolStr {
+	case "1", "t", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// formatBool is FormatBool using the configured truthy and falsy values,
+// handling unrecognised values according to the error mode.
+func (t *Transformer) formatBool(v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	boolStr, ok := v.(string)
+	if !ok {
+		return t.invalid(&TypeError{Type: "BOOL", Want: "a boolean or string", Value: v}, false)
+	}
+	if !t.validBool(boolStr) {
+		return t.invalid(invalidValue(ErrInvalidBoolean, "invalid boolean %q", boolStr), false)
+	}
+	if t.truthy != nil {
+		return t.truthy[boolStr]
+	}
+	return FormatBool(boolStr)
+}
+
+// FormatNull transforms null values.
+func FormatNull(v interface{}) interface{} {
+	return nil // Always returns nil for NULL type
+}
+
+// formatString is FormatString using the configured date layouts and epoch
+// unit, rejecting empty strings in strict mode.
+func (t *Transformer) formatString(v interface{}) interface{} {
+	strVal, ok := v.(string)
+	if !ok {
+		return t.invalid(&TypeError{Type: "S", Want: "a string", Value: v}, "")
+	}
+	if t.strict && strVal == "" {
+		return ErrEmptyString
+	}
+	if tm, ok := t.parseTime(strVal); ok {
+		t.stats.addDateConversion()
+		if t.extendedJSON {
+			return extendedDate(tm)
+		}
+		return epoch(tm, t.epochUnit)
+	}
+	return strVal
+}
+
+// formatNum is FormatNum, or FormatFloat when float numbers are enabled,
+// rejecting unparseable numbers unless the error mode replaces them with 0.
+func (t *Transformer) formatNum(v interface{}) interface{} {
+	n := t.parseNum(v)
+	if t.extendedJSON {
+		return extendedNumber(n)
+	}
+	return n
+}
+
+// parseNum converts the N value v for formatNum.
+func (t *Transformer) parseNum(v interface{}) interface{} {
+	numStr, ok := v.(string)
+	if !ok {
+		return t.invalid(&TypeError{Type: "N", Want: "a string", Value: v}, 0.0)
+	}
+	skip := t.skipInvalid() || t.omitInvalidNumbers
+	if skip || t.stats != nil {
+		if _, ok := parseFinite(numStr); !ok {
+			t.stats.addInvalidNumber()
+			if skip {
+				return invalidValue(ErrUnparsableNumber, "invalid number %q", numStr)
+			}
+		}
+	}
+	if t.floatNumbers {
+		return FormatFloat(v)
+	}
+	return FormatNum(v)
+}
+
+// bigInteger returns numStr as a canonical json.Number if it is an integer
+// literal, so integers too large for int64 survive marshaling unchanged.
+func bigInteger(numStr string) (json.Number, bool) {
+	sign, digits := "", numStr
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	} else if strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	if digits == "" {
+		return "", false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+
+	// Leading zeros are not valid in JSON numbers
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return "0", true
+	}
+	return json.Number(sign + digits), true
+}
+
+// FormatMap recursively transforms nested maps (objects) with the default rules.
+func FormatMap(v interface{}) interface{} {
+	return std.formatMap(v)
+}
+
+// FormatList transforms list values (arrays) with the default rules.
+func FormatList(v interface{}) interface{} {
+	return std.formatList(v)
+}
+
+// formatMap recursively transforms nested maps (objects).
+func (t *Transformer) formatMap(v interface{}) interface{} {
+	return t.formatMapAt(nil, v)
+}
+
+// formatMapAt is formatMap for a map found at path within the document.
+func (t *Transformer) formatMapAt(path []string, v interface{}) interface{} {
+	submap, ok := v.(map[string]interface{})
+	if !ok {
+		return t.invalid(&TypeError{Type: "M", Want: "an object", Value: v}, map[string]interface{}{})
+	}
+	return t.transformAt(path, submap)
+}
+
+// formatList transforms list values (arrays). Typed elements such as
+// {"S": "x"} are converted with their rule and other maps are transformed as
+// nested documents. Any remaining elements are dropped unless list
+// passthrough is enabled.
+func (t *Transformer) formatList(v interface{}) interface{} {
+	return t.formatListAt(nil, v)
+}
+
+// formatListAt is formatList for a list found at path within the document.
+// Elements are addressed by their decimal index.
+func (t *Transformer) formatListAt(path []string, v interface{}) interface{} {
+	listValue, ok := v.([]interface{})
+	if !ok {
+		return t.invalid(&TypeError{Type: "L", Want: "an array", Value: v}, []interface{}{})
+	}
+	outList := make([]interface{}, 0, len(listValue))
+	for i, listItem := range listValue {
+		elemPath := path
+		if t.tracksPaths() {
+			elemPath = t.childPath(path, strconv.Itoa(i))
+		}
+		keep, partial := t.selectField(elemPath)
+		if !keep {
+			continue
+		}
+		var (
+			input, result interface{}
+			valid         bool
+		)
+		switch val := listItem.(type) {
+		case map[string]interface{}:
+			if typeKey, typed, ok := t.typedValue(val); ok {
+				t.stats.countType(typeKey, true)
+				input = typed
+				result, valid = t.apply(Attribute{Path: elemPath, Type: typeKey, Value: typed})
+			} else {
+				input, result, valid = val, t.transformAt(elemPath, val), true
+			}
+		default:
+			result, valid = val, t.listPassthrough
+		}
+		// Elements only leading to included attributes need some of them,
+		// and those emptied by exclude paths are dropped
+		if valid && (!partial || nonEmptyContainer(result)) && !t.emptiedByExclude(elemPath, input, result) {
+			outList = append(outList, result)
+		}
+	}
+	return outList
+}
+
+// typedValue reports whether m is a single typed value such as {"N": "1"},
+// returning its type key and the wrapped value.
+func (t *Transformer) typedValue(m map[string]interface{}) (string, interface{}, bool) {
+	if len(m) != 1 {
+		return "", nil, false
+	}
+	for k, v := range m {
+		k = sanitizeKey(k)
+		if _, ok := t.rules.lookup(k); ok {
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
+
+// FormatStringSet transforms string sets (SS) into arrays of strings.
+func FormatStringSet(v interface{}) interface{} {
+	return std.formatSet("S")(v)
+}
+
+// FormatNumberSet transforms number sets (NS) into arrays of numbers.
+func FormatNumberSet(v interface{}) interface{} {
+	return std.formatSet("N")(v)
+}
+
+// FormatBinarySet transforms binary sets (BS) into arrays of binary values.
+func FormatBinarySet(v interface{}) interface{} {
+	return std.formatSet("B")(v)
+}
+
+// formatSet returns a rule that transforms a set of string-encoded elements,
+// converting each one with the rule registered for elemType. Elements are
+// passed through unchanged when no such rule exists, and elements that are
+// not strings are dropped.
+func (t *Transformer) formatSet(elemType string) TransformationRule {
+	return func(v interface{}) interface{} {
+		setValue, ok := v.([]interface{})
+		if !ok {
+			return t.invalid(&TypeError{Type: elemType + "S", Want: "an array", Value: v}, []interface{}{})
+		}
+		outList := make([]interface{}, 0, len(setValue))
+		for _, item := range setValue {
+			elem, ok := item.(string)
+			if !ok {
+				continue
+			}
+			if rule, ok := t.rules.Rule(elemType); ok {
+				if result, valid := applyRule(rule, elem); valid {
+					outList = append(outList, result)
+				}
+			} else {
+				outList = append(outList, elem)
+			}
+		}
+		return outList
+	}
+}
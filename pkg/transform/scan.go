@@ -0,0 +1,231 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ParseOption configures the checks ParseReader and ParseSchema apply to the
+// input before decoding it.
+type ParseOption func(*parseConfig)
+
+// parseConfig holds the checks selected by ParseOptions.
+type parseConfig struct {
+	rejectDuplicates bool
+	maxSize          int64
+	maxDepth         int
+	maxKeys          int
+	allowComments    bool
+	// keyOrder receives the order of the input keys when set
+	keyOrder *KeyOrder
+	// items receives the documents of a root array when set
+	items *[]map[string]interface{}
+}
+
+// scan reports whether the input must be scanned token by token.
+func (c parseConfig) scan() bool {
+	return c.rejectDuplicates || c.maxDepth > 0 || c.maxKeys > 0 || c.keyOrder != nil
+}
+
+// RejectDuplicateKeys fails parsing with a *DuplicateKeyError listing every
+// object member whose key repeats an earlier one of the same object.
+func RejectDuplicateKeys() ParseOption {
+	return func(c *parseConfig) {
+		c.rejectDuplicates = true
+	}
+}
+
+// MaxInputSize fails parsing with a *LimitError when the input is larger
+// than n bytes, after decompression. Values below 1 disable the limit.
+func MaxInputSize(n int64) ParseOption {
+	return func(c *parseConfig) {
+		c.maxSize = n
+	}
+}
+
+// MaxDepth fails parsing with a *LimitError when objects and arrays, type
+// tag wrappers included, are nested more than n deep. The top-level object is
+// at depth 1. Values below 1 disable the limit.
+func MaxDepth(n int) ParseOption {
+	return func(c *parseConfig) {
+		c.maxDepth = n
+	}
+}
+
+// MaxKeys fails parsing with a *LimitError when the objects of the input,
+// type tag wrappers included, hold more than n keys in total. Values below 1
+// disable the limit.
+func MaxKeys(n int) ParseOption {
+	return func(c *parseConfig) {
+		c.maxKeys = n
+	}
+}
+
+// LimitError reports input exceeding a limit set by MaxInputSize, MaxDepth
+// or MaxKeys.
+type LimitError struct {
+	// Limit names the limit: "input size", "nesting depth" or "key count".
+	Limit string
+	Max   int64
+	// Pointer is the JSON Pointer of the value that exceeded the limit
+	// within the input, and is empty for the input size.
+	Pointer string
+}
+
+// Error names the limit, its maximum and where it was exceeded.
+func (e *LimitError) Error() string {
+	// Only the input size is not exceeded at a value
+	if e.Pointer == "" {
+		return fmt.Sprintf("%s exceeds the limit of %d bytes", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("%s exceeds the limit of %d at %s", e.Limit, e.Max, e.Pointer)
+}
+
+// Is reports whether target is ErrLimitExceeded.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// DuplicateKeyError reports the keys repeated within objects of a JSON
+// document.
+type DuplicateKeyError struct {
+	// Pointers are the JSON Pointers of the repeated members within the
+	// input, in document order; the first occurrence of each key is not
+	// included.
+	Pointers []string
+}
+
+// Error names the first duplicate key and how many others there are.
+func (e *DuplicateKeyError) Error() string {
+	if len(e.Pointers) == 1 {
+		return "duplicate key at " + e.Pointers[0]
+	}
+	return fmt.Sprintf("%d duplicate keys, first at %s", len(e.Pointers), e.Pointers[0])
+}
+
+// Is reports whether target is ErrDuplicateKey.
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+// scanFrame is an object or array being scanned by scanDocument.
+type scanFrame struct {
+	// keys holds the keys seen so far in an object, and is nil for arrays
+	keys    map[string]bool
+	key     string
+	wantKey bool
+	index   int
+	// order records the keys of the object, or the elements of the array,
+	// when the key order is recorded
+	order *KeyOrder
+}
+
+// scanDocument walks the first JSON value in data token by token, enforcing
+// the depth and key limits of cfg and, when it rejects duplicates, collecting
+// the JSON Pointers of object members whose key repeats an earlier member of
+// the same object. The order of the keys is recorded in cfg.keyOrder if set.
+func scanDocument(data []byte, cfg parseConfig) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var (
+		stack []*scanFrame
+		path  []string
+		dups  []string
+		keys  int
+	)
+	// endValue leaves the value that just ended, returning to its container
+	endValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		path = path[:len(path)-1]
+		if top := stack[len(stack)-1]; top.keys != nil {
+			top.wantKey = true
+		}
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return locate(data, err)
+		}
+
+		delim, isDelim := tok.(json.Delim)
+		switch {
+		case isDelim && (delim == '}' || delim == ']'):
+			stack = stack[:len(stack)-1]
+			endValue()
+		case len(stack) > 0 && stack[len(stack)-1].wantKey:
+			// Object keys are always strings
+			top := stack[len(stack)-1]
+			key := tok.(string)
+			if keys++; cfg.maxKeys > 0 && keys > cfg.maxKeys {
+				return &LimitError{Limit: "key count", Max: int64(cfg.maxKeys), Pointer: JSONPointer(append(path, key))}
+			}
+			dup := top.keys[key]
+			top.keys[key] = true
+			if dup && cfg.rejectDuplicates {
+				dups = append(dups, JSONPointer(append(path, key)))
+			}
+			// A repeated key keeps its first position
+			if top.order != nil && !dup {
+				top.order.keys = append(top.order.keys, key)
+			}
+			top.key, top.wantKey = key, false
+		default:
+			// Objects and arrays record the order of their own contents
+			var order *KeyOrder
+			if isDelim && cfg.keyOrder != nil {
+				order = &KeyOrder{}
+				if len(stack) == 0 {
+					*cfg.keyOrder = KeyOrder{}
+					order = cfg.keyOrder
+				}
+			}
+
+			// A value starts, addressed by its key or index in its container
+			if len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.keys != nil {
+					path = append(path, top.key)
+					top.order.setChild(top.key, order)
+				} else {
+					path = append(path, strconv.Itoa(top.index))
+					top.index++
+					if top.order != nil {
+						top.order.elems = append(top.order.elems, order)
+					}
+				}
+			}
+			if !isDelim {
+				endValue()
+				break
+			}
+			if cfg.maxDepth > 0 && len(stack) >= cfg.maxDepth {
+				return &LimitError{Limit: "nesting depth", Max: int64(cfg.maxDepth), Pointer: JSONPointer(path)}
+			}
+			frame := &scanFrame{order: order}
+			if delim == '{' {
+				frame.keys, frame.wantKey = make(map[string]bool), true
+			}
+			stack = append(stack, frame)
+		}
+
+		// Stop after the top-level value, leaving trailing data to parseBytes
+		if len(stack) == 0 {
+			break
+		}
+	}
+
+	if len(dups) > 0 {
+		return &DuplicateKeyError{Pointers: dups}
+	}
+	return nil
+}
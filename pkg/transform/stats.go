@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Stats counts what a Transformer did, for observability of large jobs. It
+// is safe for concurrent use; a nil *Stats counts nothing.
+type Stats struct {
+	records         atomic.Int64
+	droppedKeys     atomic.Int64
+	invalidNumbers  atomic.Int64
+	dateConversions atomic.Int64
+
+	mu    sync.Mutex
+	types map[string]int64
+}
+
+// StatsReport is a snapshot of Stats.
+type StatsReport struct {
+	// Records is the number of documents or records transformed.
+	Records int64 `json:"records"`
+	// Types counts the typed values seen per registered type tag, and those
+	// with any other tag under UnknownType.
+	Types map[string]int64 `json:"types"`
+	// DroppedKeys counts attributes dropped because their key was empty
+	// once surrounding whitespace was trimmed.
+	DroppedKeys int64 `json:"droppedKeys"`
+	// InvalidNumbers counts N values that could not be parsed.
+	InvalidNumbers int64 `json:"invalidNumbers"`
+	// DateConversions counts S values converted into timestamps.
+	DateConversions int64 `json:"dateConversions"`
+}
+
+// UnknownType is the type tag StatsReport.Types counts values under when no
+// rule is registered for their own tag, so input cannot add a count for
+// every distinct tag it makes up.
+const UnknownType = "unknown"
+
+// NewStats returns an empty Stats.
+func NewStats() *Stats {
+	return &Stats{types: make(map[string]int64)}
+}
+
+// WithStats records what the Transformer does in s.
+func WithStats(s *Stats) Option {
+	return func(t *Transformer) {
+		t.stats = s
+	}
+}
+
+// Report returns a snapshot of the counts recorded so far.
+func (s *Stats) Report() StatsReport {
+	s.mu.Lock()
+	types := make(map[string]int64, len(s.types))
+	for typeKey, n := range s.types {
+		types[typeKey] = n
+	}
+	s.mu.Unlock()
+
+	return StatsReport{
+		Records:         s.records.Load(),
+		Types:           types,
+		DroppedKeys:     s.droppedKeys.Load(),
+		InvalidNumbers:  s.invalidNumbers.Load(),
+		DateConversions: s.dateConversions.Load(),
+	}
+}
+
+// countType records a value tagged with typeKey, counted as UnknownType
+// unless a rule is known for it.
+func (s *Stats) countType(typeKey string, known bool) {
+	if s == nil {
+		return
+	}
+	if !known {
+		typeKey = UnknownType
+	}
+	s.mu.Lock()
+	s.types[typeKey]++
+	s.mu.Unlock()
+}
+
+// addRecord records a transformed document or record.
+func (s *Stats) addRecord() {
+	if s != nil {
+		s.records.Add(1)
+	}
+}
+
+// addDroppedKey records an attribute dropped for its empty key.
+func (s *Stats) addDroppedKey() {
+	if s != nil {
+		s.droppedKeys.Add(1)
+	}
+}
+
+// addInvalidNumber records an unparseable N value.
+func (s *Stats) addInvalidNumber() {
+	if s != nil {
+		s.invalidNumbers.Add(1)
+	}
+}
+
+// addDateConversion records an S value converted into a timestamp.
+func (s *Stats) addDateConversion() {
+	if s != nil {
+		s.dateConversions.Add(1)
+	}
+}
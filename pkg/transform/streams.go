@@ -0,0 +1,107 @@
+package transform
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// StreamRecord is a DynamoDB Streams record whose images have been
+// transformed into plain JSON, along with the event metadata.
+type StreamRecord struct {
+	EventID                     string                 `json:"eventID,omitempty"`
+	EventName                   string                 `json:"eventName"`
+	SequenceNumber              string                 `json:"sequenceNumber,omitempty"`
+	ApproximateCreationDateTime json.Number            `json:"approximateCreationDateTime,omitempty"`
+	Keys                        map[string]interface{} `json:"keys,omitempty"`
+	NewImage                    map[string]interface{} `json:"newImage,omitempty"`
+	OldImage                    map[string]interface{} `json:"oldImage,omitempty"`
+}
+
+// streamEvent mirrors the parts of a DynamoDB Streams event payload that are
+// carried over into StreamRecords.
+type streamEvent struct {
+	Records []struct {
+		EventID   string `json:"eventID"`
+		EventName string `json:"eventName"`
+		DynamoDB  struct {
+			SequenceNumber              string                 `json:"SequenceNumber"`
+			ApproximateCreationDateTime json.Number            `json:"ApproximateCreationDateTime"`
+			Keys                        map[string]interface{} `json:"Keys"`
+			NewImage                    map[string]interface{} `json:"NewImage"`
+			OldImage                    map[string]interface{} `json:"OldImage"`
+		} `json:"dynamodb"`
+	} `json:"Records"`
+}
+
+// image returns the document a record filter sees for r: its new image, or
+// its old image for removals, or else its keys.
+func (r StreamRecord) image() map[string]interface{} {
+	switch {
+	case r.NewImage != nil:
+		return r.NewImage
+	case r.OldImage != nil:
+		return r.OldImage
+	default:
+		return r.Keys
+	}
+}
+
+// transformImage transforms the item image of the stream record at index,
+// applying defaults and checking its required fields. Absent images stay nil.
+func (t *Transformer) transformImage(index int, image map[string]interface{}) (map[string]interface{}, error) {
+	if image == nil {
+		return nil, nil
+	}
+	out := t.transformDocument(image)
+	t.applyDefaults(out)
+	if t.reshape != nil {
+		out = t.reshape(out, t.delimiter)
+	}
+	if err := t.CheckOutput(index, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransformStreamEvent reads a DynamoDB Streams event payload from r, as
+// delivered to stream consumers, and transforms the keys and images of every
+// record in Records.
+func (t *Transformer) TransformStreamEvent(r io.Reader) ([]StreamRecord, error) {
+	dec := json.NewDecoder(NewTextReader(r))
+	dec.UseNumber()
+
+	var event streamEvent
+	if err := dec.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	records := make([]StreamRecord, 0, len(event.Records))
+	for index, rec := range event.Records {
+		out := StreamRecord{
+			EventID:                     rec.EventID,
+			EventName:                   rec.EventName,
+			SequenceNumber:              rec.DynamoDB.SequenceNumber,
+			ApproximateCreationDateTime: rec.DynamoDB.ApproximateCreationDateTime,
+		}
+		t.stats.addRecord()
+		// Only transform the images the stream view type actually includes
+		if rec.DynamoDB.Keys != nil {
+			out.Keys = t.transformDocument(rec.DynamoDB.Keys)
+		}
+		var err error
+		if out.NewImage, err = t.transformImage(index, rec.DynamoDB.NewImage); err != nil {
+			return nil, &RecordError{Index: index, Err: err}
+		}
+		if out.OldImage, err = t.transformImage(index, rec.DynamoDB.OldImage); err != nil {
+			return nil, &RecordError{Index: index, Err: err}
+		}
+		keep, err := t.KeepRecord(out.image())
+		if err != nil {
+			return nil, &RecordError{Index: index, Err: err}
+		}
+		if keep {
+			records = append(records, out)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,130 @@
+package transform
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// EpochUnit selects the unit timestamps are converted to.
+type EpochUnit int
+
+const (
+	// EpochSeconds converts timestamps to Unix seconds.
+	EpochSeconds EpochUnit = iota
+	// EpochMilliseconds converts timestamps to Unix milliseconds.
+	EpochMilliseconds
+	// EpochMicroseconds converts timestamps to Unix microseconds.
+	EpochMicroseconds
+	// EpochNanoseconds converts timestamps to Unix nanoseconds.
+	EpochNanoseconds
+)
+
+// ParseEpochUnit parses the name of an EpochUnit: seconds, milliseconds,
+// microseconds or nanoseconds, or their abbreviations s, ms, us and ns.
+func ParseEpochUnit(name string) (EpochUnit, error) {
+	switch name {
+	case "s", "seconds":
+		return EpochSeconds, nil
+	case "ms", "milliseconds":
+		return EpochMilliseconds, nil
+	case "us", "microseconds":
+		return EpochMicroseconds, nil
+	case "ns", "nanoseconds":
+		return EpochNanoseconds, nil
+	default:
+		return 0, fmt.Errorf("unknown epoch unit %q", name)
+	}
+}
+
+// WithEpochUnit sets the unit timestamps in S values are converted to.
+func WithEpochUnit(unit EpochUnit) Option {
+	return func(t *Transformer) {
+		t.epochUnit = unit
+	}
+}
+
+// epoch returns tm as a Unix timestamp in the given unit.
+func epoch(tm time.Time, unit EpochUnit) int64 {
+	switch unit {
+	case EpochMilliseconds:
+		return tm.UnixMilli()
+	case EpochMicroseconds:
+		return tm.UnixMicro()
+	case EpochNanoseconds:
+		return tm.UnixNano()
+	default:
+		return tm.Unix()
+	}
+}
+
+// LayoutEpoch and LayoutEpochMillis are pseudo layouts matching strings that
+// hold a Unix timestamp in seconds or milliseconds.
+const (
+	LayoutEpoch       = "epoch"
+	LayoutEpochMillis = "epoch_ms"
+)
+
+// namedLayouts maps layout names accepted by WithDateLayouts to time layouts.
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+}
+
+// WithDateLayouts adds time layouts that S values are tried against, in order,
+// after RFC3339. Each layout is either a Go reference layout such as
+// "2006-01-02", the name of a standard layout such as "RFC1123", or one of
+// LayoutEpoch and LayoutEpochMillis.
+func WithDateLayouts(layouts ...string) Option {
+	return func(t *Transformer) {
+		for _, layout := range layouts {
+			if named, ok := namedLayouts[layout]; ok {
+				layout = named
+			}
+			t.dateLayouts = append(t.dateLayouts, layout)
+		}
+	}
+}
+
+// WithAcceptedDateLayouts replaces the date layouts S values are tried
+// against, including the default RFC3339, with layouts, which are interpreted
+// as by WithDateLayouts. With no layouts, S values are never converted to
+// timestamps.
+func WithAcceptedDateLayouts(layouts ...string) Option {
+	return func(t *Transformer) {
+		t.dateLayouts = nil
+		WithDateLayouts(layouts...)(t)
+	}
+}
+
+// parseTime parses strVal with the first matching date layout.
+func (t *Transformer) parseTime(strVal string) (time.Time, bool) {
+	for _, layout := range t.dateLayouts {
+		switch layout {
+		case LayoutEpoch, LayoutEpochMillis:
+			n, err := strconv.ParseInt(strVal, 10, 64)
+			if err != nil {
+				continue
+			}
+			if layout == LayoutEpochMillis {
+				return time.UnixMilli(n), true
+			}
+			return time.Unix(n, 0), true
+		default:
+			if tm, err := time.Parse(layout, strVal); err == nil {
+				return tm, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,32 @@
+package transform
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records the package's spans with the global OpenTelemetry tracer
+// provider, which discards them unless the application installs one.
+var tracer = otel.Tracer("github.com/Ravali181221/Ravali_Challenge/pkg/transform")
+
+// TransformContext is Transform recorded as a span of the trace in ctx, so
+// transformations embedded in pipelines show up in distributed traces.
+// Transform itself records nothing, as it is called once per NDJSON record.
+func (t *Transformer) TransformContext(ctx context.Context, inputMap map[string]interface{}) map[string]interface{} {
+	_, span := tracer.Start(ctx, "Transform", trace.WithAttributes(attribute.Int("attributes", len(inputMap))))
+	defer span.End()
+	return t.Transform(inputMap)
+}
+
+// endSpan ends span, marking it failed when err is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
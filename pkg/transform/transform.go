@@ -0,0 +1,384 @@
+// Package transform converts DynamoDB-style typed JSON into plain JSON.
+package transform
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TransformationRule represents a function that transforms a value based on a schema key type.
+// A rule may return an error to signal that the value is invalid, in which case
+// the attribute is omitted from the output.
+type TransformationRule func(interface{}) interface{}
+
+// Option configures a Transformer.
+type Option func(*Transformer)
+
+// Transformer applies transformation rules to typed JSON documents.
+type Transformer struct {
+	rules *Registry
+
+	// strict omits invalid values instead of replacing them with defaults
+	strict bool
+
+	// dateLayouts are tried in order to recognise timestamps in S values,
+	// which are converted to epochUnit
+	dateLayouts []string
+	epochUnit   EpochUnit
+
+	// floatNumbers parses every N value into float64, as older versions did
+	floatNumbers bool
+
+	// omitInvalidNumbers drops unparseable N values instead of zeroing them
+	omitInvalidNumbers bool
+
+	// onError decides whether invalid values are replaced, omitted or reported
+	onError ErrorMode
+
+	// stats, when set, counts what the transformer does
+	stats *Stats
+
+	// truthy and falsy replace the default BOOL string values when set
+	truthy, falsy map[string]bool
+
+	// listPassthrough keeps list elements that are not typed values or maps
+	listPassthrough bool
+
+	binaryFormat BinaryFormat
+	binaryDir    string
+
+	// extendedJSON writes numbers, timestamps and binary values as MongoDB
+	// Extended JSON
+	extendedJSON bool
+
+	// renames and renamePatterns rename output keys, see WithKeyRenames
+	renames        map[string]string
+	renamePatterns []KeyRename
+	keyCase        KeyCase
+
+	// include and exclude select the attributes in the output by key path
+	include, exclude []FieldPath
+
+	// dropKeys and keepKeys filter the attributes of every object by key
+	dropKeys, keepKeys []*regexp.Regexp
+
+	// defaults fill in output fields absent from transformed documents
+	defaults []defaultValue
+
+	// reshape flattens or unflattens transformed documents, and unshape does
+	// the inverse to the input of Reverse, joining or splitting keys with
+	// delimiter
+	reshape, unshape func(map[string]interface{}, string) map[string]interface{}
+	delimiter        string
+
+	// required lists the output fields CheckOutput reports when missing, to
+	// warn unless failing
+	required     [][]string
+	outputChecks []func(map[string]interface{}) []Warning
+	warn         WarningHandler
+
+	// droppedKey receives the keys dropped, see WithDroppedKeyHandler
+	droppedKey DroppedKeyHandler
+
+	// filter selects the records of record streams, see WithRecordFilter
+	filter RecordFilter
+
+	// reverseSets makes Reverse emit SS and NS for homogeneous arrays
+	reverseSets bool
+
+	// parallelism is the number of goroutines used by Transform and the
+	// NDJSON functions
+	parallelism int
+
+	// middleware wraps apply, which transforms every typed value
+	middleware []Middleware
+	apply      TransformFunc
+}
+
+// New returns a Transformer with the default rules, adjusted by opts.
+func New(opts ...Option) *Transformer {
+	t := &Transformer{
+		rules:       NewRegistry(),
+		dateLayouts: []string{time.RFC3339},
+	}
+	t.rules.set("S", ruleEntry{rule: t.formatString})
+	t.rules.set("N", ruleEntry{rule: t.formatNum})
+	t.rules.set("BOOL", ruleEntry{rule: t.formatBool})
+	t.rules.set("NULL", ruleEntry{rule: FormatNull})
+
+	// Nested types recurse through this transformer so options apply at every level
+	t.rules.overrideNested("M", t.formatMap, t.formatMapAt)
+	t.rules.overrideNested("L", t.formatList, t.formatListAt)
+	t.rules.set("B", ruleEntry{rule: t.formatBinary})
+	t.rules.set("SS", ruleEntry{rule: t.formatSet("S")})
+	t.rules.set("NS", ruleEntry{rule: t.formatSet("N")})
+	t.rules.set("BS", ruleEntry{rule: t.formatSet("B")})
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	// Wrap rule application in the middleware, the first registered outermost
+	t.apply = t.applyAttribute
+	for i := len(t.middleware) - 1; i >= 0; i-- {
+		t.apply = t.middleware[i](t.apply)
+	}
+	return t
+}
+
+// WithRule registers rule for the given schema key type, replacing any existing rule.
+// A nil rule leaves the rules unchanged.
+func WithRule(typeKey string, rule TransformationRule) Option {
+	return func(t *Transformer) {
+		if rule != nil {
+			t.rules.set(typeKey, ruleEntry{rule: rule})
+		}
+	}
+}
+
+// WithStrict enables strict spec conformance: attributes with unparseable N
+// values, empty S values, invalid B or BOOL values or unknown type keys are
+// omitted from the output instead of being replaced with defaults, as with
+// OnErrorSkip.
+func WithStrict(enabled bool) Option {
+	return func(t *Transformer) {
+		t.strict = enabled
+	}
+}
+
+// WithOmitInvalidNumbers omits attributes whose N value cannot be parsed
+// instead of replacing it with 0, as strict mode does, without enabling the
+// rest of strict mode.
+func WithOmitInvalidNumbers(enabled bool) Option {
+	return func(t *Transformer) {
+		t.omitInvalidNumbers = enabled
+	}
+}
+
+// WithBoolValues replaces the strings BOOL values are recognised by. Values
+// in truthy become true. When falsy is non-empty, values in it become false
+// and any other value is invalid and handled according to the error mode;
+// otherwise every value not in truthy becomes false.
+func WithBoolValues(truthy, falsy []string) Option {
+	return func(t *Transformer) {
+		t.truthy = stringSet(truthy)
+		t.falsy = stringSet(falsy)
+	}
+}
+
+// stringSet returns the members of values as a set.
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// WithFloatNumbers parses every N value into float64 instead of preserving
+// integer precision. Integers beyond 2^53 may lose precision in this mode.
+func WithFloatNumbers(enabled bool) Option {
+	return func(t *Transformer) {
+		t.floatNumbers = enabled
+	}
+}
+
+// WithListPassthrough keeps plain scalars and other unrecognised list elements
+// as they are instead of dropping them from L values.
+func WithListPassthrough(enabled bool) Option {
+	return func(t *Transformer) {
+		t.listPassthrough = enabled
+	}
+}
+
+// WithReverseSets makes Reverse encode non-empty arrays of unique strings or
+// numbers as SS or NS sets instead of L lists.
+func WithReverseSets(enabled bool) Option {
+	return func(t *Transformer) {
+		t.reverseSets = enabled
+	}
+}
+
+// WithParallelism transforms the top-level attributes of each document, and
+// the records of an NDJSON stream, on up to n goroutines. Values of n below 2
+// keep the serial behaviour.
+func WithParallelism(n int) Option {
+	return func(t *Transformer) {
+		t.parallelism = n
+	}
+}
+
+// std is the Transformer used by the package-level functions.
+var std = New()
+
+// TransformJSON applies the default transformation rules to inputMap. It
+// also returns a warning for every value the transformation dropped or
+// replaced with a default, as reported by Validate.
+func TransformJSON(inputMap map[string]interface{}) (map[string]interface{}, []Warning) {
+	warnings := std.Validate(inputMap)
+	return std.TransformContext(context.Background(), inputMap), warnings
+}
+
+// Transform recursively applies transformation rules to the input JSON.
+// With WithParallelism, top-level attributes are transformed concurrently.
+func (t *Transformer) Transform(inputMap map[string]interface{}) map[string]interface{} {
+	t.stats.addRecord()
+	output := t.transformDocument(inputMap)
+	t.applyDefaults(output)
+	if t.reshape != nil {
+		output = t.reshape(output, t.delimiter)
+	}
+	return output
+}
+
+// transformDocument is Transform without counting a record, for callers
+// transforming parts of one record.
+func (t *Transformer) transformDocument(inputMap map[string]interface{}) map[string]interface{} {
+	if t.parallelism > 1 && len(inputMap) > 1 {
+		return t.transformParallel(inputMap)
+	}
+	return t.transform(inputMap)
+}
+
+// transform applies transformation rules to inputMap serially. Nested maps
+// and lists recurse through it rather than Transform, so parallelism only
+// applies at the top level.
+func (t *Transformer) transform(inputMap map[string]interface{}) map[string]interface{} {
+	return t.transformAt(nil, inputMap)
+}
+
+// transformAt is transform for a map found at path within the document.
+func (t *Transformer) transformAt(path []string, inputMap map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(inputMap))
+
+	// Iterate over each key-value pair in the input JSON
+	for key, value := range inputMap {
+		if outKey, outValue, ok := t.transformAttribute(path, key, value); ok {
+			output[outKey] = outValue
+		}
+	}
+
+	return output
+}
+
+// transformAttribute transforms a single typed attribute of the map at path,
+// reporting whether it should appear in the output.
+func (t *Transformer) transformAttribute(path []string, key string, value interface{}) (string, interface{}, bool) {
+	if sanitizeKey(key) == "" {
+		t.dropKey(path, key, DroppedEmptyKey)
+		return "", nil, false
+	}
+	key = sanitizeKey(key)
+
+	// Only maps (objects) hold typed values
+	val, ok := value.(map[string]interface{})
+	if !ok {
+		return "", nil, false
+	}
+
+	if !t.keepKey(key) {
+		return "", nil, false
+	}
+
+	attrPath := t.childPath(path, key)
+	keep, partial := t.selectField(attrPath)
+	if !keep {
+		return "", nil, false
+	}
+
+	var (
+		input, result interface{}
+		found         bool
+	)
+	// An attribute normally wraps a single type key. Several are tried in
+	// sorted order and the first valid one wins, so the output does not
+	// depend on map iteration order
+	var single [1]string
+	typeKeys := single[:0]
+	for k := range val {
+		typeKeys = append(typeKeys, k)
+	}
+	slices.Sort(typeKeys)
+	for _, k := range typeKeys {
+		v := val[k]
+		k = sanitizeKey(k)
+		// Apply transformation rule if one exists for the key type. Middleware
+		// also sees unknown type keys, so it can handle custom tags itself
+		_, known := t.rules.lookup(k)
+		t.stats.countType(k, known)
+		if known || len(t.middleware) > 0 {
+			if r, valid := t.apply(Attribute{Path: attrPath, Type: k, Value: v}); valid {
+				if !found {
+					input, result, found = v, r, true
+				}
+				continue
+			}
+		}
+		if !known && t.strict {
+			// Strict mode omits attributes with unknown type keys entirely
+			return "", nil, false
+		}
+	}
+	if !found || partial && !nonEmptyContainer(result) || t.emptiedByExclude(attrPath, input, result) {
+		return "", nil, false
+	}
+	outKey := t.outputKey(key)
+	if outKey == "" {
+		t.dropKey(path, key, DroppedRenamedKey)
+		return "", nil, false
+	}
+	return outKey, result, true
+}
+
+// applyAttribute runs the rule registered for a.Type on a.Value, reporting
+// whether the result is valid. It is the innermost TransformFunc of the
+// middleware chain.
+func (t *Transformer) applyAttribute(a Attribute) (interface{}, bool) {
+	entry, ok := t.rules.lookup(a.Type)
+	if !ok {
+		return nil, false
+	}
+	if entry.nested != nil {
+		return validResult(entry.nested(a.Path, a.Value))
+	}
+	return applyRule(entry.rule, a.Value)
+}
+
+// childPath returns path extended with key. Paths are only tracked when
+// middleware could observe them or fields are selected by path.
+func (t *Transformer) childPath(path []string, key string) []string {
+	if !t.tracksPaths() {
+		return nil
+	}
+	child := make([]string, len(path)+1)
+	copy(child, path)
+	child[len(path)] = key
+	return child
+}
+
+// tracksPaths reports whether the key path of every value must be known.
+func (t *Transformer) tracksPaths() bool {
+	return len(t.middleware) > 0 || t.selectsFields() || t.droppedKey != nil
+}
+
+// applyRule runs rule on v and reports whether the result is valid.
+// Rules signal an invalid value by returning an error.
+func applyRule(rule TransformationRule, v interface{}) (interface{}, bool) {
+	return validResult(rule(v))
+}
+
+// validResult reports whether a rule's result is valid, that is not an error.
+func validResult(result interface{}) (interface{}, bool) {
+	if _, invalid := result.(error); invalid {
+		return nil, false
+	}
+	return result, true
+}
+
+// sanitizeKey trims leading and trailing whitespace from a key.
+func sanitizeKey(key string) string {
+	return strings.TrimSpace(key)
+}
@@ -0,0 +1,51 @@
+package transform
+
+// Untyped returns doc with the type tags of its attributes dropped, as a
+// naive reading of typed JSON would: {"N": "1"} becomes "1" and
+// {"NULL": true} null, while M and L values are untyped in turn. Nothing
+// else is converted, so comparing the result with the transformed document
+// shows what the rules changed. Values without a single known type tag are
+// kept as they are.
+func (t *Transformer) Untyped(doc map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(doc))
+	for key, value := range doc {
+		out[key] = t.untypedValue(value)
+	}
+	return out
+}
+
+// untypedValue returns the typed value v without its type tag.
+func (t *Transformer) untypedValue(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	typeKey, value, ok := t.typedValue(m)
+	if !ok {
+		return v
+	}
+	switch typeKey {
+	case "NULL":
+		return nil
+	case "M":
+		if nested, ok := value.(map[string]interface{}); ok {
+			return t.Untyped(nested)
+		}
+	case "L":
+		if list, ok := value.([]interface{}); ok {
+			out := make([]interface{}, len(list))
+			for i, elem := range list {
+				if nested, ok := elem.(map[string]interface{}); ok {
+					if _, _, typed := t.typedValue(nested); !typed {
+						// Plain objects in lists are documents of their own
+						out[i] = t.Untyped(nested)
+						continue
+					}
+				}
+				out[i] = t.untypedValue(elem)
+			}
+			return out
+		}
+	}
+	return value
+}
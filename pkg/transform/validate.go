@@ -0,0 +1,244 @@
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// Warning is a value that does not conform to the DynamoDB typed JSON
+// format, which the transformation would drop or replace with a default.
+type Warning struct {
+	// Path is the JSON Pointer of the value, such as "/users/3/createdAt",
+	// with list and set elements addressed by their decimal index.
+	Path string
+	// Value is the offending value: the raw value wrapped by its type tag,
+	// or the attribute itself when it is not a typed value.
+	Value interface{}
+	// Reason describes what is wrong with the value, such as "empty key",
+	// "unknown type tag" or "invalid number".
+	Reason string
+	// Err is the error Reason is the message of. It wraps one of the
+	// sentinel errors such as ErrInvalidTypeTag, or is the error returned by
+	// a custom rule.
+	Err error
+}
+
+// String returns the warning as "path: reason", or just the reason for the
+// whole document.
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Reason
+	}
+	return w.Path + ": " + w.Reason
+}
+
+// Validate checks input against the DynamoDB typed JSON format and returns a
+// warning for every problem found, ordered by key. Built-in type tags are
+// checked against the format itself; values of custom tags are run through
+// their rule, which reports a warning by returning an error.
+func (t *Transformer) Validate(input map[string]interface{}) []Warning {
+	v := &validator{t: t}
+	v.object("", input)
+	return v.warnings
+}
+
+// TransformWithWarnings is Transform returning, along with the output, the
+// warnings Validate reports for input, so callers can surface the values the
+// transformation dropped or replaced with a default.
+func (t *Transformer) TransformWithWarnings(input map[string]interface{}) (map[string]interface{}, []Warning) {
+	warnings := t.Validate(input)
+	return t.Transform(input), warnings
+}
+
+// validator collects the warnings for a document.
+type validator struct {
+	t        *Transformer
+	warnings []Warning
+}
+
+// add records a warning for value at path.
+func (v *validator) add(path string, value interface{}, err error) {
+	v.warnings = append(v.warnings, Warning{Path: path, Value: value, Reason: err.Error(), Err: err})
+}
+
+// object checks every attribute of the typed document m found at path.
+func (v *validator) object(path string, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		attrPath := schemautil.JoinPointer(path, key)
+		if sanitizeKey(key) == "" {
+			v.add(attrPath, m[key], ErrEmptyKey)
+			continue
+		}
+		v.attribute(attrPath, m[key])
+	}
+}
+
+// attribute checks that value is a single typed value such as {"S": "x"}.
+func (v *validator) attribute(path string, value interface{}) {
+	typed, ok := value.(map[string]interface{})
+	if !ok {
+		v.add(path, value, invalidValue(ErrInvalidTypeTag, "expected an object holding a type tag, found %s", valueKind(value)))
+		return
+	}
+	if len(typed) != 1 {
+		v.add(path, value, invalidValue(ErrInvalidTypeTag, "expected exactly one type tag, found %d", len(typed)))
+		return
+	}
+	for typeKey, raw := range typed {
+		v.value(path, sanitizeKey(typeKey), raw)
+	}
+}
+
+// value checks the raw value wrapped by typeKey at path.
+func (v *validator) value(path, typeKey string, raw interface{}) {
+	switch typeKey {
+	case "S":
+		if str, ok := v.str(path, typeKey, raw); ok && str == "" && v.t.strict {
+			v.add(path, raw, ErrEmptyString)
+		}
+	case "N":
+		if str, ok := v.str(path, typeKey, raw); ok {
+			if _, ok := parseFinite(str); !ok {
+				v.add(path, raw, invalidValue(ErrUnparsableNumber, "invalid number %q", str))
+			}
+		}
+	case "BOOL":
+		switch val := raw.(type) {
+		case bool:
+		case string:
+			if !v.t.validBool(val) {
+				v.add(path, raw, invalidValue(ErrInvalidBoolean, "invalid boolean %q", val))
+			}
+		default:
+			v.add(path, raw, &TypeError{Type: typeKey, Want: "a boolean or string", Value: raw})
+		}
+	case "NULL":
+		if raw != true && raw != "true" {
+			v.add(path, raw, invalidValue(ErrInvalidNull, "NULL must wrap true, found %s", valueKind(raw)))
+		}
+	case "B":
+		if str, ok := v.str(path, typeKey, raw); ok {
+			if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+				v.add(path, raw, invalidValue(ErrInvalidBinary, "invalid base64: %v", err))
+			}
+		}
+	case "SS", "NS", "BS":
+		if list, ok := v.list(path, typeKey, raw); ok {
+			for i, item := range list {
+				v.value(schemautil.JoinPointer(path, strconv.Itoa(i)), strings.TrimSuffix(typeKey, "S"), item)
+			}
+		}
+	case "M":
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			v.add(path, raw, &TypeError{Type: typeKey, Want: "an object", Value: raw})
+			return
+		}
+		v.object(path, m)
+	case "L":
+		if list, ok := v.list(path, typeKey, raw); ok {
+			v.elements(path, list)
+		}
+	default:
+		v.custom(path, typeKey, raw)
+	}
+}
+
+// elements checks the elements of an L value, which are typed values or
+// nested documents.
+func (v *validator) elements(path string, list []interface{}) {
+	for i, item := range list {
+		elemPath := schemautil.JoinPointer(path, strconv.Itoa(i))
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			if !v.t.listPassthrough {
+				v.add(elemPath, item, invalidValue(ErrInvalidTypeTag, "plain %s in a list is dropped", valueKind(item)))
+			}
+			continue
+		}
+		if typeKey, typed, ok := v.t.typedValue(m); ok {
+			v.value(elemPath, typeKey, typed)
+		} else {
+			v.object(elemPath, m)
+		}
+	}
+}
+
+// custom checks a value of a type tag without a built-in check by running
+// its rule, reporting an error result or a panic as a warning.
+func (v *validator) custom(path, typeKey string, raw interface{}) {
+	rule, ok := v.t.rules.Rule(typeKey)
+	if !ok {
+		v.add(path, raw, invalidValue(ErrInvalidTypeTag, "unknown type tag %q", typeKey))
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			v.add(path, raw, fmt.Errorf("%s rule failed: %v", typeKey, r))
+		}
+	}()
+	if err, invalid := rule(raw).(error); invalid {
+		v.add(path, raw, err)
+	}
+}
+
+// str returns raw as a string, reporting a warning if it is not one.
+func (v *validator) str(path, typeKey string, raw interface{}) (string, bool) {
+	str, ok := raw.(string)
+	if !ok {
+		v.add(path, raw, &TypeError{Type: typeKey, Want: "a string", Value: raw})
+	}
+	return str, ok
+}
+
+// list returns raw as an array, reporting a warning if it is not one.
+func (v *validator) list(path, typeKey string, raw interface{}) ([]interface{}, bool) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		v.add(path, raw, &TypeError{Type: typeKey, Want: "an array", Value: raw})
+	}
+	return list, ok
+}
+
+// validBool reports whether str is a recognised BOOL value.
+func (t *Transformer) validBool(str string) bool {
+	if t.truthy != nil {
+		return t.truthy[str] || len(t.falsy) == 0 || t.falsy[str]
+	}
+	switch str {
+	case "1", "t", "true", "0", "f", "false":
+		return true
+	default:
+		return false
+	}
+}
+
+// valueKind names the JSON kind of a decoded value for error messages.
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,163 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IsYAMLFile reports whether fileName names a YAML document by its .yaml or
+// .yml extension, before any .gz or .zst compression extension.
+func IsYAMLFile(fileName string) bool {
+	for _, ext := range []string{".gz", ".zst"} {
+		fileName = strings.TrimSuffix(fileName, ext)
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseYAMLReader reads a YAML document from r and parses it like
+// ParseReader parses the equivalent JSON, applying opts the same way.
+// Numbers keep their precision, timestamps and binary values stay strings,
+// and aliases are expanded. The size limit applies to the YAML input.
+func ParseYAMLReader(r io.Reader, opts ...ParseOption) (map[string]interface{}, error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	inputBytes, err := readInput(NewTextReader(r), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(inputBytes))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err == io.EOF {
+		return nil, errors.New("empty YAML document")
+	} else if err != nil {
+		return nil, err
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); err != io.EOF {
+		return nil, ErrTrailingData
+	}
+
+	var buf bytes.Buffer
+	if err := writeYAMLAsJSON(&buf, &doc); err != nil {
+		return nil, err
+	}
+	return parseChecked(buf.Bytes(), cfg)
+}
+
+// jsonNumber matches the numbers JSON can hold literally.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// writeYAMLAsJSON writes the YAML node n to buf as the equivalent JSON,
+// keeping every key of a mapping in order, duplicates included, so the
+// checks of ParseReader see them.
+func writeYAMLAsJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeYAMLAsJSON(buf, n.Content[0])
+	case yaml.AliasNode:
+		return writeYAMLAsJSON(buf, n.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("yaml: line %d: mapping keys must be scalars", key.Line)
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, key.Value)
+			buf.WriteByte(':')
+			if err := writeYAMLAsJSON(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, elem := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeYAMLAsJSON(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	default:
+		return writeYAMLScalar(buf, n)
+	}
+}
+
+// writeYAMLScalar writes the YAML scalar n to buf as JSON, by its resolved
+// tag.
+func writeYAMLScalar(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(b))
+	case "!!int", "!!float":
+		// Numbers written as JSON would write them keep their exact text
+		if jsonNumber.MatchString(n.Value) {
+			buf.WriteString(n.Value)
+			return nil
+		}
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		switch v := v.(type) {
+		case int:
+			buf.WriteString(strconv.Itoa(v))
+		case int64:
+			buf.WriteString(strconv.FormatInt(v, 10))
+		case uint64:
+			buf.WriteString(strconv.FormatUint(v, 10))
+		case float64:
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				return fmt.Errorf("yaml: line %d: %s cannot be represented in JSON", n.Line, n.Value)
+			}
+			buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		default:
+			writeJSONString(buf, n.Value)
+		}
+	default:
+		writeJSONString(buf, n.Value)
+	}
+	return nil
+}
+
+// writeJSONString writes s to buf as a JSON string.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	out, _ := json.Marshal(s)
+	buf.Write(out)
+}
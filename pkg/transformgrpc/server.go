@@ -0,0 +1,89 @@
+// Package transformgrpc serves a transform.Transformer over gRPC.
+package transformgrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transformpb"
+)
+
+// Server implements transformpb.TransformServiceServer.
+type Server struct {
+	transformpb.UnimplementedTransformServiceServer
+
+	t *transform.Transformer
+}
+
+// NewServer returns a Server that converts documents with t.
+func NewServer(t *transform.Transformer) *Server {
+	return &Server{t: t}
+}
+
+// Transform converts a single document.
+func (s *Server) Transform(ctx context.Context, req *transformpb.TransformRequest) (*transformpb.TransformResponse, error) {
+	doc, err := s.convert(ctx, req.GetDocument(), req.GetReverse())
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, status.FromContextError(ctxErr).Err()
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &transformpb.TransformResponse{Document: doc}, nil
+}
+
+// TransformStream converts every document received on stream, reporting
+// per-document failures in the response so the stream keeps going.
+func (s *Server) TransformStream(stream transformpb.TransformService_TransformStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &transformpb.TransformStreamResponse{}
+		if doc, err := s.convert(stream.Context(), req.GetDocument(), req.GetReverse()); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Document = doc
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// convert parses a JSON document, transforms it within the trace in ctx and
+// encodes the result, failing once ctx is done.
+func (s *Server) convert(ctx context.Context, document []byte, reverse bool) (out []byte, err error) {
+	inputMap, err := transform.ParseReader(bytes.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	// A panicking rule must not take the whole server down
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("transform failed: %v", r)
+		}
+	}()
+
+	var output map[string]interface{}
+	if reverse {
+		output = s.t.Reverse(inputMap)
+	} else if output, err = s.t.TransformRecordContext(ctx, 0, inputMap); err != nil {
+		return nil, err
+	}
+	return codec.Default().Marshal(output)
+}
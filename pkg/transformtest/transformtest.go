@@ -0,0 +1,158 @@
+// Package transformtest helps programs embedding the transformer regression
+// test their schemas against golden files: the transformed output of a typed
+// input is compared with a recorded copy, and recorded again when tests run
+// with -update-golden.
+//
+// A test can check every input in a directory at once:
+//
+//	func TestSchemas(t *testing.T) {
+//		transformtest.RunDir(t, transform.New(), "testdata")
+//	}
+//
+// and record the outputs with go test -run TestSchemas -update-golden.
+package transformtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/jsonpatch"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Update makes the assertions record the actual output as the golden file
+// instead of comparing with it. It is set with the -update-golden test flag.
+var Update = flag.Bool("update-golden", false, "Record the transformed outputs as the golden files of transformtest instead of comparing with them")
+
+// GoldenSuffix ends the names of the golden files RunDir compares the
+// transformed inputs with: the output of orders.json is kept in
+// orders.golden.json.
+const GoldenSuffix = ".golden.json"
+
+// RunDir runs a subtest for every typed JSON input in dir, named after the
+// file, asserting with Golden that its transformed output matches the golden
+// file next to it. Golden files themselves are not inputs.
+func RunDir(t *testing.T, tr *transform.Transformer, dir string) {
+	t.Helper()
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, input := range inputs {
+		if strings.HasSuffix(input, GoldenSuffix) {
+			continue
+		}
+		found = true
+		name := filepath.Base(input)
+		t.Run(strings.TrimSuffix(name, ".json"), func(t *testing.T) {
+			Golden(t, tr, input, strings.TrimSuffix(input, ".json")+GoldenSuffix)
+		})
+	}
+	if !found {
+		t.Fatalf("no JSON inputs in %s", dir)
+	}
+}
+
+// Golden transforms the typed JSON document in the file input with tr and
+// asserts that the result matches the golden file, with AssertGolden.
+func Golden(t testing.TB, tr *transform.Transformer, input, golden string) {
+	t.Helper()
+	doc, err := transform.ParseSchema(input)
+	if err != nil {
+		t.Fatalf("%s: %v", input, err)
+	}
+	out, err := tr.TransformChecked(doc)
+	if err != nil {
+		t.Fatalf("%s: %v", input, err)
+	}
+	AssertGolden(t, golden, out)
+}
+
+// AssertGolden asserts that got, encoded as JSON, matches the JSON in the
+// golden file, reporting every differing path as a JSON Patch operation
+// turning the golden value into got. Key order and formatting do not
+// matter. With Update, got is written to the file instead, normalized, and
+// the file created if needed.
+func AssertGolden(t testing.TB, golden string, got interface{}) {
+	t.Helper()
+	actual, err := Normalize(got)
+	if err != nil {
+		t.Fatalf("encoding output: %v", err)
+	}
+	if *Update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(golden)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("%s does not exist; run the test with -update-golden to record it", golden)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	var want interface{}
+	if err := decode(data, &want); err != nil {
+		t.Fatalf("%s: %v", golden, err)
+	}
+	var have interface{}
+	if err := decode(actual, &have); err != nil {
+		t.Fatalf("decoding output: %v", err)
+	}
+	patch := jsonpatch.Diff(want, have)
+	if len(patch) == 0 {
+		return
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "output differs from %s; run the test with -update-golden to accept it:", golden)
+	for _, op := range patch {
+		line, _ := json.Marshal(op)
+		fmt.Fprintf(&msg, "\n\t%s", line)
+	}
+	t.Error(msg.String())
+}
+
+// Normalize encodes v as JSON with sorted keys, indented with two spaces
+// and ending in a newline, the form golden files are recorded in. Decoded
+// JSON given as []byte or json.RawMessage is normalized as well.
+func Normalize(v interface{}) ([]byte, error) {
+	switch raw := v.(type) {
+	case []byte:
+		if err := decode(raw, &v); err != nil {
+			return nil, err
+		}
+	case json.RawMessage:
+		if err := decode(raw, &v); err != nil {
+			return nil, err
+		}
+	}
+	sorted, err := transform.MarshalSorted(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, sorted, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// decode decodes the JSON data into v, keeping numbers exact.
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
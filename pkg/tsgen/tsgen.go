@@ -0,0 +1,188 @@
+// Package tsgen generates TypeScript interfaces from a JSON Schema describing
+// plain JSON documents, such as the one schemagen infers from transformed
+// output, for consumers written in TypeScript.
+package tsgen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ravali181221/Ravali_Challenge/internal/schemautil"
+)
+
+// Generate returns TypeScript source exporting the interface typeName for
+// documents described by schema, along with an interface for every nested
+// object with known properties. Properties that are not required are
+// optional, nullable ones are unions with null, and values of several types
+// are unions of them.
+func Generate(schema map[string]interface{}, typeName string) ([]byte, error) {
+	if !identifier.MatchString(typeName) {
+		return nil, fmt.Errorf("invalid type name %q", typeName)
+	}
+
+	g := &generator{names: make(map[string]bool)}
+	if _, ok := g.object(schema, typeName, "A transformed document."); !ok {
+		return nil, fmt.Errorf("schema does not describe an object with properties")
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen ts; DO NOT EDIT.\n")
+	for _, it := range g.interfaces {
+		fmt.Fprintf(&b, "\n/** %s */\n", it.doc)
+		fmt.Fprintf(&b, "export interface %s {\n", it.name)
+		for _, p := range it.props {
+			optional := ""
+			if p.optional {
+				optional = "?"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", propertyName(p.key), optional, p.tsType)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes(), nil
+}
+
+// identifier matches the TypeScript identifiers interfaces are named with.
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// generator collects the interfaces declared for a schema.
+type generator struct {
+	interfaces []*interfaceType
+	names      map[string]bool
+}
+
+// interfaceType is an interface declaration.
+type interfaceType struct {
+	name  string
+	doc   string
+	props []property
+}
+
+// property is a property of an interface declaration.
+type property struct {
+	key, tsType string
+	optional    bool
+}
+
+// object declares an interface for the object schema, named after hint and
+// documented as doc, and returns its name. It reports false when schema has
+// no properties, which leaves nothing to declare.
+func (g *generator) object(schema map[string]interface{}, hint, doc string) (string, bool) {
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return "", false
+	}
+	required := make(map[string]bool)
+	for _, key := range schemautil.StringList(schema["required"]) {
+		required[key] = true
+	}
+
+	it := &interfaceType{name: g.typeName(hint), doc: doc}
+	g.interfaces = append(g.interfaces, it)
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		prop, _ := props[key].(map[string]interface{})
+		tsType := g.tsType(prop, interfaceName(key), fmt.Sprintf("The value of %q.", key))
+		it.props = append(it.props, property{key: key, tsType: tsType, optional: !required[key]})
+	}
+	return it.name, true
+}
+
+// tsType returns the TypeScript type of values described by schema.
+// Interfaces declared for the values are named after hint and documented as
+// doc.
+func (g *generator) tsType(schema map[string]interface{}, hint, doc string) string {
+	types := schemautil.StringList(schema["type"])
+	if len(types) == 0 {
+		return "unknown"
+	}
+
+	union := make([]string, 0, len(types))
+	for _, t := range types {
+		switch t {
+		case "object":
+			if name, ok := g.object(schema, hint, doc); ok {
+				union = append(union, name)
+			} else {
+				// An index signature rather than Record<string, unknown>,
+				// which an interface named Record would shadow
+				union = append(union, "{ [key: string]: unknown }")
+			}
+		case "array":
+			items, _ := schema["items"].(map[string]interface{})
+			elem := g.tsType(items, schemautil.Singular(hint), strings.Replace(doc, "The value of", "An element of", 1))
+			if strings.Contains(elem, " | ") {
+				elem = "(" + elem + ")"
+			}
+			union = append(union, elem+"[]")
+		case "string":
+			union = append(union, "string")
+		case "integer", "number":
+			union = append(union, "number")
+		case "boolean":
+			union = append(union, "boolean")
+		case "null":
+			union = append(union, "null")
+		default:
+			union = append(union, "unknown")
+		}
+	}
+	return strings.Join(union, " | ")
+}
+
+// typeName returns hint as an unused interface name.
+func (g *generator) typeName(hint string) string {
+	name := hint
+	for i := 2; g.names[name]; i++ {
+		name = hint + strconv.Itoa(i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// propertyName returns key as written in an interface, quoted when it is not
+// an identifier.
+func propertyName(key string) string {
+	if identifier.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// interfaceName returns an interface name for values found at the JSON key,
+// such as UserAddress for user_address.
+func interfaceName(key string) string {
+	var b strings.Builder
+	word := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			word = true
+			continue
+		}
+		if word {
+			r = unicode.ToUpper(r)
+			word = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" {
+		return "Value"
+	}
+	if first := []rune(name)[0]; !unicode.IsUpper(first) {
+		// Digits and caseless letters cannot start a type name
+		name = "X" + name
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package tsgen_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/schemagen"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/tsgen"
+)
+
+// update rewrites the golden files with the generated output.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// infer returns the schema schemagen infers for the JSON documents docs.
+func infer(t *testing.T, docs ...string) map[string]interface{} {
+	t.Helper()
+	inferrer := schemagen.New()
+	for _, doc := range docs {
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+		inferrer.Add(v)
+	}
+	return inferrer.Schema()
+}
+
+// TestGenerate compares the source generated for inferred schemas with the
+// golden files in testdata.
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []string
+	}{
+		{
+			name: "property names",
+			docs: []string{`{"user_id":1,"firstName":"a","html-body":"b","2fa":true,"$ref":"r"}`},
+		},
+		{
+			name: "optional and unions",
+			docs: []string{
+				`{"id":1,"name":"a","tags":["x"],"meta":{},"note":null,"any":[1,"x"],"e":[]}`,
+				`{"id":2.5,"tags":[],"meta":{},"note":"n","any":["y"],"e":[]}`,
+			},
+		},
+		{
+			name: "nested",
+			docs: []string{
+				`{"home_address":{"city":"Paris","geo":{"lat":1.5}},"addresses":[{"city":"Lyon"}],"entries":[{"sku":"a"},null]}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tsgen.Generate(infer(t, tt.docs...), "Record")
+			if err != nil {
+				t.Fatalf("Generate = %v", err)
+			}
+			golden := filepath.Join("testdata", strings.ReplaceAll(tt.name, " ", "_")+".ts.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Generate =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestGenerateErrors checks the errors for invalid names and schemas
+// without properties.
+func TestGenerateErrors(t *testing.T) {
+	if _, err := tsgen.Generate(infer(t, `{"id":1}`), "my-record"); err == nil || err.Error() != `invalid type name "my-record"` {
+		t.Errorf("Generate with an invalid name = %v", err)
+	}
+	if _, err := tsgen.Generate(infer(t, `{}`), "Record"); err == nil || err.Error() != "schema does not describe an object with properties" {
+		t.Errorf("Generate without properties = %v", err)
+	}
+}
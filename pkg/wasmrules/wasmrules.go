@@ -0,0 +1,219 @@
+// Package wasmrules loads transformation plugins compiled to WebAssembly, so
+// custom rules can be written in any language that targets WASM and run in a
+// sandbox with no file system, network or environment access and a bounded
+// amount of memory.
+//
+// # Host interface
+//
+// A plugin module must export its linear memory as "memory" and these
+// functions:
+//
+//	alloc(size i32) i32
+//	transform(ptr i32, len i32) i64
+//
+// and may export
+//
+//	dealloc(ptr i32, size i32)
+//
+// For every typed value the host calls alloc, writes a JSON request of the
+// form
+//
+//	{"path": ["user", "email"], "type": "S", "value": "a@b"}
+//
+// into the returned memory and calls transform with its location. transform
+// returns the location of a JSON-encoded result packed as ptr<<32 | len.
+// Returning 0 applies the built-in rule for the type instead, and returning
+// Omit drops the value from the output. When dealloc is exported the host
+// calls it to release both the request and the result.
+//
+// Modules built for WASI, such as TinyGo or Go wasip1 reactors, are given a
+// WASI environment with no preopened directories; a reactor's _initialize
+// function is run once when the plugin is loaded.
+package wasmrules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// Omit is the transform result that drops a value from the output.
+const Omit = ^uint64(0)
+
+// MaxMemoryPages bounds the linear memory of a plugin to 64 KiB pages, 64 MiB
+// in all, so a runaway allocation fails the value instead of exhausting the
+// host's memory.
+const MaxMemoryPages = 1024
+
+// Plugin is a loaded WebAssembly rules plugin. WASM instances are
+// single-threaded, so calls into the plugin are serialized.
+type Plugin struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	runtime wazero.Runtime
+	mod     api.Module
+
+	alloc     api.Function
+	dealloc   api.Function
+	transform api.Function
+}
+
+// request is the JSON document passed to the plugin's transform function.
+type request struct {
+	Path  []string    `json:"path"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Load compiles and instantiates the WebAssembly module at path. The plugin
+// is called within ctx: once ctx is done a running call is aborted, which
+// closes the module so later calls fail too. Its memory is limited to
+// MaxMemoryPages.
+func Load(ctx context.Context, path string) (*Plugin, error) {
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(MaxMemoryPages)
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+	p, err := instantiate(ctx, runtime, wasm)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	p.ctx = ctx
+	return p, nil
+}
+
+// instantiate instantiates wasm in runtime and resolves the host interface.
+func instantiate(ctx context.Context, runtime wazero.Runtime, wasm []byte) (*Plugin, error) {
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		return nil, err
+	}
+
+	// WASI is provided without any directories, environment or arguments
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return nil, err
+	}
+	config := wazero.NewModuleConfig().WithStartFunctions()
+	if _, ok := compiled.ExportedFunctions()["_initialize"]; ok {
+		config = config.WithStartFunctions("_initialize")
+	}
+	mod, err := runtime.InstantiateModule(ctx, compiled, config)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		runtime:   runtime,
+		mod:       mod,
+		alloc:     mod.ExportedFunction("alloc"),
+		dealloc:   mod.ExportedFunction("dealloc"),
+		transform: mod.ExportedFunction("transform"),
+	}
+	if p.alloc == nil || p.transform == nil || mod.Memory() == nil {
+		return nil, fmt.Errorf("module must export memory, alloc and transform")
+	}
+	return p, nil
+}
+
+// Close releases the plugin and its runtime.
+func (p *Plugin) Close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
+
+// Option returns the option registering the plugin as middleware.
+func (p *Plugin) Option() transform.Option {
+	return transform.WithMiddleware(p.middleware)
+}
+
+// middleware calls the plugin for every attribute, falling through to the
+// built-in rule when it returns 0.
+func (p *Plugin) middleware(next transform.TransformFunc) transform.TransformFunc {
+	return func(a transform.Attribute) (interface{}, bool) {
+		path := a.Path
+		if path == nil {
+			path = []string{}
+		}
+		req, err := json.Marshal(request{Path: path, Type: a.Type, Value: a.Value})
+		if err != nil {
+			return nil, false
+		}
+
+		// Plugin failures, including omitted values, drop the value
+		result, pass, err := p.call(p.ctx, req)
+		if err != nil {
+			return nil, false
+		}
+		if pass {
+			return next(a)
+		}
+		return result, true
+	}
+}
+
+// errOmit is returned by call when the plugin omits a value.
+var errOmit = errors.New("value omitted by plugin")
+
+// call passes req to the plugin's transform function and decodes its result.
+// pass reports that the built-in rule should be applied instead.
+func (p *Plugin) call(ctx context.Context, req []byte) (result interface{}, pass bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	res, err := p.alloc.Call(ctx, uint64(len(req)))
+	if err != nil {
+		return nil, false, err
+	}
+	reqPtr := uint32(res[0])
+	defer p.free(ctx, reqPtr, uint32(len(req)))
+	if !p.mod.Memory().Write(reqPtr, req) {
+		return nil, false, fmt.Errorf("request of %d bytes does not fit at %#x", len(req), reqPtr)
+	}
+
+	res, err = p.transform.Call(ctx, uint64(reqPtr), uint64(len(req)))
+	if err != nil {
+		return nil, false, err
+	}
+	switch packed := res[0]; packed {
+	case 0:
+		return nil, true, nil
+	case Omit:
+		return nil, false, errOmit
+	default:
+		resPtr, resLen := uint32(packed>>32), uint32(packed)
+		defer p.free(ctx, resPtr, resLen)
+		out, ok := p.mod.Memory().Read(resPtr, resLen)
+		if !ok {
+			return nil, false, fmt.Errorf("result at %#x of %d bytes is out of range", resPtr, resLen)
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(out))
+		dec.UseNumber()
+		if err := dec.Decode(&result); err != nil {
+			return nil, false, fmt.Errorf("decoding result: %w", err)
+		}
+		return result, false, nil
+	}
+}
+
+// free releases guest memory when the plugin exports dealloc.
+func (p *Plugin) free(ctx context.Context, ptr, size uint32) {
+	if p.dealloc != nil {
+		p.dealloc.Call(ctx, uint64(ptr), uint64(size))
+	}
+}
@@ -0,0 +1,230 @@
+package wasmrules_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/wasmrules"
+)
+
+// data is the content of the first bytes of each test module's memory: a
+// JSON result at offset 1, after the result 7 at offset 0.
+const data = "7{\"ok\":true}"
+
+// okResult is the transform result locating the JSON result in data.
+const okResult = 1<<32 | int64(len(data)-1)
+
+// module returns a WebAssembly module exporting memory of minPages pages,
+// with data at offset 0, an alloc function returning offset 1024 whatever
+// the size asked for, and a transform function running the instructions in
+// body, which leave its i64 result on the stack.
+func module(minPages uint64, body ...byte) []byte {
+	section := func(id byte, items ...[]byte) []byte {
+		content := binary.AppendUvarint(nil, uint64(len(items)))
+		for _, item := range items {
+			content = append(content, item...)
+		}
+		return append(append([]byte{id}, binary.AppendUvarint(nil, uint64(len(content)))...), content...)
+	}
+	code := func(instrs ...byte) []byte {
+		// No locals, and the end of the body
+		instrs = append(append([]byte{0x00}, instrs...), 0x0b)
+		return append(binary.AppendUvarint(nil, uint64(len(instrs))), instrs...)
+	}
+	export := func(name string, kind, index byte) []byte {
+		return append(append([]byte{byte(len(name))}, name...), kind, index)
+	}
+
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	wasm = append(wasm, section(1, []byte{0x60, 1, 0x7f, 1, 0x7f}, []byte{0x60, 2, 0x7f, 0x7f, 1, 0x7e})...)
+	wasm = append(wasm, section(3, []byte{0}, []byte{1})...)
+	wasm = append(wasm, section(5, binary.AppendUvarint([]byte{0x00}, minPages))...)
+	wasm = append(wasm, section(7, export("memory", 2, 0), export("alloc", 0, 0), export("transform", 0, 1))...)
+	wasm = append(wasm, section(10, code(i32Const(1024)...), code(body...))...)
+	segment := append([]byte{0x00, 0x41, 0x00, 0x0b, byte(len(data))}, data...)
+	return append(wasm, section(11, segment)...)
+}
+
+// i32Const returns the instruction pushing the i32 n.
+func i32Const(n int64) []byte {
+	return append([]byte{0x41}, sleb(n)...)
+}
+
+// i64Const returns the instruction pushing the i64 n.
+func i64Const(n int64) []byte {
+	return append([]byte{0x42}, sleb(n)...)
+}
+
+// sleb returns n in signed LEB128.
+func sleb(n int64) []byte {
+	var out []byte
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if (n == 0 && b&0x40 == 0) || (n == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+// load writes wasm to a temporary file and loads it within ctx.
+func load(t *testing.T, ctx context.Context, wasm []byte) (*wasmrules.Plugin, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.wasm")
+	if err := os.WriteFile(path, wasm, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := wasmrules.Load(ctx, path)
+	if err == nil {
+		t.Cleanup(func() { p.Close(context.Background()) })
+	}
+	return p, err
+}
+
+// transformed returns the output of a plugin transforming the document
+// {"a": {"S": "x"}}.
+func transformed(p *wasmrules.Plugin) map[string]interface{} {
+	input := map[string]interface{}{"a": map[string]interface{}{"S": "x"}}
+	return transform.New(p.Option()).Transform(input)
+}
+
+// TestLoadErrors checks that files that are not plugins are rejected when
+// loaded.
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		wasm []byte
+		want string
+	}{
+		{name: "not WebAssembly", wasm: []byte("not wasm"), want: "rules.wasm: "},
+		{name: "memory beyond the cap", wasm: module(wasmrules.MaxMemoryPages+1, i64Const(0)...), want: "rules.wasm: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, context.Background(), tt.wasm)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestResults checks how the results of a plugin's transform function are
+// applied.
+func TestResults(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+		want map[string]interface{}
+	}{
+		{name: "result", body: i64Const(okResult), want: map[string]interface{}{"a": map[string]interface{}{"ok": true}}},
+		{name: "built-in rule", body: i64Const(0), want: map[string]interface{}{"a": "x"}},
+		{name: "omitted", body: i64Const(-1), want: map[string]interface{}{}},
+		{name: "invalid result", body: i64Const(1<<32 | 2), want: map[string]interface{}{}},
+		{name: "result out of range", body: i64Const(1 << 40), want: map[string]interface{}{}},
+		// unreachable
+		{name: "trap", body: []byte{0x00}, want: map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := load(t, context.Background(), module(1, tt.body...))
+			if err != nil {
+				t.Fatalf("Load = %v", err)
+			}
+			if got := transformed(p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transform = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMemoryCap checks that a plugin cannot grow its memory beyond
+// MaxMemoryPages.
+func TestMemoryCap(t *testing.T) {
+	// Return the result of memory.grow by MaxMemoryPages, sign-extended:
+	// -1, which omits the value, when growing fails, and otherwise the
+	// previous size of one page, which locates the result 7 at offset 0
+	body := append(i32Const(wasmrules.MaxMemoryPages), 0x40, 0x00, 0xac)
+	p, err := load(t, context.Background(), module(1, body...))
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	if got := transformed(p); len(got) != 0 {
+		t.Errorf("Transform = %#v, want the value omitted", got)
+	}
+
+	// Growing within the cap succeeds
+	body = append(i32Const(wasmrules.MaxMemoryPages-1), 0x40, 0x00, 0x1a)
+	p, err = load(t, context.Background(), module(1, append(body, i64Const(0)...)...))
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	if got := transformed(p); !reflect.DeepEqual(got, map[string]interface{}{"a": "x"}) {
+		t.Errorf("Transform = %#v, want the built-in rule applied", got)
+	}
+}
+
+// TestContextCancelled checks that a plugin call running forever is
+// aborted once the context the plugin was loaded with is done.
+func TestContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// loop br 0 end
+	body := append([]byte{0x03, 0x40, 0x0c, 0x00, 0x0b}, i64Const(0)...)
+	p, err := load(t, ctx, module(1, body...))
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+
+	in := strings.Repeat(`{"a":{"S":"x"}}`+"\n", 3)
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- transform.New(p.Option()).TransformNDJSONContext(ctx, strings.NewReader(in), &out)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("TransformNDJSONContext = %v, want context.DeadlineExceeded", err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("wrote %q, want nothing", out.String())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("plugin call was not aborted")
+	}
+}
+
+// TestRequest checks the request a plugin is passed, by returning it as
+// the result.
+func TestRequest(t *testing.T) {
+	// Return the request itself: ptr<<32 | len from the parameters
+	body := []byte{
+		0x20, 0x00, 0xad, 0x42, 0x20, 0x86, // local.get 0; i64.extend_i32_u; i64.const 32; i64.shl
+		0x20, 0x01, 0xad, 0x84, // local.get 1; i64.extend_i32_u; i64.or
+	}
+	p, err := load(t, context.Background(), module(1, body...))
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	input := map[string]interface{}{"age": map[string]interface{}{"N": "42"}}
+	got := transform.New(p.Option()).Transform(input)
+	want := map[string]interface{}{
+		"age": map[string]interface{}{"path": []interface{}{"age"}, "type": "N", "value": "42"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		out, _ := json.Marshal(got)
+		t.Errorf("Transform = %s, want the request", out)
+	}
+}
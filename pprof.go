@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	runtimepprof "runtime/pprof"
+)
+
+// pprofFlags registers the --cpuprofile and --memprofile flags on fs and
+// returns a function starting the CPU profile once fs has been parsed. That
+// function returns another stopping it and writing the heap profile, to be
+// called once the command finishes.
+func pprofFlags(fs *flag.FlagSet) func() (func(), error) {
+	cpuProfileFlag := fs.String("cpuprofile", "", "Write a CPU profile of the run to this file, for go tool pprof")
+	memProfileFlag := fs.String("memprofile", "", "Write a heap profile to this file once the run finishes, for go tool pprof")
+
+	return func() (func(), error) {
+		var cpuFile *os.File
+		if *cpuProfileFlag != "" {
+			f, err := os.Create(*cpuProfileFlag)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtimepprof.StartCPUProfile(f); err != nil {
+				f.Close()
+				return nil, err
+			}
+			cpuFile = f
+		}
+
+		return func() {
+			if cpuFile != nil {
+				runtimepprof.StopCPUProfile()
+				if err := cpuFile.Close(); err != nil {
+					slog.Warn("writing CPU profile failed", "file", *cpuProfileFlag, "err", err)
+				}
+			}
+			if *memProfileFlag != "" {
+				if err := writeHeapProfile(*memProfileFlag); err != nil {
+					slog.Warn("writing heap profile failed", "file", *memProfileFlag, "err", err)
+				}
+			}
+		}, nil
+	}
+}
+
+// writeHeapProfile writes a heap profile, up to date as of the last garbage
+// collection, to the file at path.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	runtime.GC()
+	if err := runtimepprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// handlePprof registers the net/http/pprof handlers under /debug/pprof/ on mux.
+func handlePprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
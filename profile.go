@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// profileTopPaths bounds the number of key paths printed by --profile.
+const profileTopPaths = 20
+
+// writeProfile prints the time recorded per type tag and for the slowest key
+// paths in p as tables.
+func writeProfile(w io.Writer, p *transform.Profile) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "rule\tcalls\ttotal\taverage")
+	for _, entry := range p.Rules() {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", entry.Name, entry.Calls, entry.Total, entry.Average())
+	}
+
+	paths := p.Paths()
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "path (top %d of %d)\tcalls\ttotal\taverage\n", min(profileTopPaths, len(paths)), len(paths))
+	for i, entry := range paths {
+		if i == profileTopPaths {
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", entry.Name, entry.Calls, entry.Total, entry.Average())
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/s3io"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// progress reports how far a transformation has got on stderr: records
+// transformed, input bytes read, files finished in batch mode, the rate and,
+// when the input size is known, the estimated time left. On a terminal a
+// single line is rewritten in place, otherwise a line is written per
+// interval. Nothing is written for runs finishing within the first interval.
+type progress struct {
+	w        io.Writer
+	terminal bool
+	interval time.Duration
+	stats    *transform.Stats
+
+	bytes      atomic.Int64
+	total      atomic.Int64
+	files      atomic.Int64
+	filesTotal atomic.Int64
+
+	start    time.Time
+	stop     chan struct{}
+	stopped  sync.WaitGroup
+	reported bool
+}
+
+// newProgress returns a progress reporter writing to w, rewriting its line
+// in place when terminal is set, which counts the records recorded in stats.
+func newProgress(w io.Writer, terminal bool, stats *transform.Stats) *progress {
+	interval := 10 * time.Second
+	if terminal {
+		interval = 500 * time.Millisecond
+	}
+	return &progress{w: w, terminal: terminal, interval: interval, stats: stats, stop: make(chan struct{})}
+}
+
+// run starts reporting every interval until finish is called.
+func (p *progress) run() {
+	p.start = time.Now()
+	p.stopped.Add(1)
+	go func() {
+		defer p.stopped.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.report(false)
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// finish stops reporting, writing a final line if any was reported.
+func (p *progress) finish() {
+	close(p.stop)
+	p.stopped.Wait()
+	if p.reported {
+		p.report(true)
+	}
+}
+
+// report writes the current progress, ending the line when final is set or
+// stderr is not a terminal.
+func (p *progress) report(final bool) {
+	p.reported = true
+	elapsed := time.Since(p.start)
+	line := p.line(elapsed, final)
+	if !p.terminal {
+		fmt.Fprintln(p.w, line)
+		return
+	}
+	// Clear what is left of a longer previous line
+	fmt.Fprintf(p.w, "\r%s\x1b[K", line)
+	if final {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// line describes the progress made in elapsed.
+func (p *progress) line(elapsed time.Duration, final bool) string {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+	var parts []string
+	if total := p.filesTotal.Load(); total > 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d files", p.files.Load(), total))
+	}
+	if p.stats != nil {
+		records := p.stats.Report().Records
+		parts = append(parts, fmt.Sprintf("%d records (%.0f/s)", records, float64(records)/seconds))
+	}
+
+	read, total := p.bytes.Load(), p.total.Load()
+	switch {
+	case total > 0 && read <= total:
+		parts = append(parts, fmt.Sprintf("%s of %s read (%.0f%%, %s/s)", formatBytes(read), formatBytes(total), 100*float64(read)/float64(total), formatBytes(int64(float64(read)/seconds))))
+	default:
+		parts = append(parts, fmt.Sprintf("%s read (%s/s)", formatBytes(read), formatBytes(int64(float64(read)/seconds))))
+	}
+
+	if final {
+		parts = append(parts, "done in "+elapsed.Round(time.Second).String())
+	} else if total > 0 && read > 0 && read < total {
+		eta := time.Duration(float64(total-read) / float64(read) * float64(elapsed))
+		parts = append(parts, "ETA "+eta.Round(time.Second).String())
+	}
+	return "progress: " + strings.Join(parts, ", ")
+}
+
+// addTotal adds n bytes to the input size the estimated time left is based
+// on.
+func (p *progress) addTotal(n int64) {
+	if p != nil {
+		p.total.Add(n)
+	}
+}
+
+// addFiles adds n files to the number expected in batch mode.
+func (p *progress) addFiles(n int) {
+	if p != nil {
+		p.filesTotal.Add(int64(n))
+	}
+}
+
+// fileDone counts a finished file of a batch.
+func (p *progress) fileDone() {
+	if p != nil {
+		p.files.Add(1)
+	}
+}
+
+// progressKey is the context key of the progress reporter.
+type progressKey struct{}
+
+// withProgress returns ctx carrying p, so the inputs opened within it count
+// the bytes read.
+func withProgress(ctx context.Context, p *progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+// progressFrom returns the progress reporter carried by ctx, or nil.
+func progressFrom(ctx context.Context) *progress {
+	p, _ := ctx.Value(progressKey{}).(*progress)
+	return p
+}
+
+// countReads returns r counting the bytes read from it towards the progress
+// carried by ctx, if any.
+func countReads(ctx context.Context, r io.ReadCloser) io.ReadCloser {
+	p := progressFrom(ctx)
+	if p == nil {
+		return r
+	}
+	return &countingReader{ReadCloser: r, p: p}
+}
+
+// countingReader is the reader returned by countReads.
+type countingReader struct {
+	io.ReadCloser
+	p *progress
+}
+
+// Read reads from the underlying reader, counting the bytes read.
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.ReadCloser.Read(b)
+	c.p.bytes.Add(int64(n))
+	return n, err
+}
+
+// inputSize returns the size of the local file at path, or of stdin with
+// useStdin when it is redirected from a file, as read before any
+// decompression, or 0 when it is not known.
+func inputSize(path string, useStdin bool) int64 {
+	var info os.FileInfo
+	var err error
+	switch {
+	case useStdin:
+		info, err = os.Stdin.Stat()
+	case s3io.IsURL(path):
+		return 0
+	default:
+		info, err = os.Stat(path)
+	}
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	return info.Size()
+}
+
+// formatBytes formats n bytes with a decimal unit, e.g. 12.3 MB.
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, prefix := float64(n)/unit, 0
+	for value >= unit && prefix < 3 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "kMGT"[prefix])
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
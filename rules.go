@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/celrules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/luarules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/rulesconfig"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/starlarkrules"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/wasmrules"
+)
+
+// loadRules loads the custom rules in the file at path, choosing the rules
+// language from its extension, and returns the options registering them.
+// WebAssembly plugins are called within ctx.
+func loadRules(ctx context.Context, path string) ([]transform.Option, error) {
+	switch filepath.Ext(path) {
+	case ".json":
+		rules, err := celrules.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		opts := make([]transform.Option, 0, len(rules))
+		for typeKey, rule := range rules {
+			opts = append(opts, transform.WithRule(typeKey, rule))
+		}
+		return opts, nil
+	case ".yaml", ".yml":
+		cfg, err := rulesconfig.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.Options()
+	case ".lua":
+		script, err := luarules.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return script.Options(), nil
+	case ".star":
+		script, err := starlarkrules.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return []transform.Option{script.Option()}, nil
+	case ".wasm":
+		plugin, err := wasmrules.Load(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		return []transform.Option{plugin.Option()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported rules file %q, want a .yaml rules configuration, a .json file of CEL expressions, a .lua or .star script, or a .wasm plugin", path)
+	}
+}
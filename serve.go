@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transformgrpc"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transformpb"
+)
+
+// maxRequestBytes bounds the size of a request body accepted by the server.
+const maxRequestBytes = 32 << 20
+
+// serveCommand registers the flags of the serve command on fs and returns
+// the function running it, which starts an HTTP server exposing the
+// transformer on POST /transform, and optionally a gRPC TransformService,
+// and serves until interrupted.
+func serveCommand(fs *flag.FlagSet) func(args []string) error {
+	addrFlag := fs.String("addr", ":8080", "Address the HTTP server listens on; empty disables it")
+	grpcAddrFlag := fs.String("grpc-addr", "", "Address the gRPC server listens on; empty disables it")
+	requestTimeoutFlag := fs.Duration("request-timeout", 0, "Fail HTTP /transform requests still being read or transformed after this long with 503 Service Unavailable; 0 means no limit")
+	pprofFlag := fs.Bool("pprof", false, "Serve the net/http/pprof profiling endpoints under /debug/pprof/ on the HTTP server")
+	newTransformer := transformerFlags(fs)
+	parseOpts := parseOptionFlags(fs)
+
+	return func(_ []string) error {
+		// The transformer's stats feed the per-type counters on /metrics
+		stats := transform.NewStats()
+		// Plugins outlive the signal context, so in-flight requests can
+		// finish during a graceful shutdown
+		t, err := newTransformer(context.Background(), transform.WithStats(stats))
+		if err != nil {
+			return usageError(err)
+		}
+
+		if *addrFlag == "" && *grpcAddrFlag == "" {
+			return usageError(errors.New("serve needs --addr or --grpc-addr"))
+		}
+		if *requestTimeoutFlag < 0 {
+			return usageError(errors.New("--request-timeout cannot be negative"))
+		}
+
+		// Shut down gracefully on SIGINT or SIGTERM, letting in-flight requests finish
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		// A second signal kills the server without waiting for requests
+		context.AfterFunc(ctx, stop)
+
+		errs := make(chan error, 2)
+		servers := 0
+		if *addrFlag != "" {
+			servers++
+			mux := newServeMux(t, newServerMetrics(stats), parseOpts(), *requestTimeoutFlag)
+			if *pprofFlag {
+				handlePprof(mux)
+			}
+			go func() { errs <- serveHTTP(ctx, *addrFlag, mux) }()
+		}
+		if *grpcAddrFlag != "" {
+			servers++
+			go func() { errs <- serveGRPC(ctx, *grpcAddrFlag, t) }()
+		}
+
+		// Stop everything as soon as one server fails
+		var firstErr error
+		for i := 0; i < servers; i++ {
+			if err := <-errs; err != nil && firstErr == nil {
+				firstErr = err
+				stop()
+			}
+		}
+		return firstErr
+	}
+}
+
+// serveHTTP serves handler on addr until ctx is done.
+func serveHTTP(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("listening", "protocol", "http", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveGRPC serves the gRPC TransformService on addr until ctx is done.
+func serveGRPC(ctx context.Context, addr string, t *transform.Transformer) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer()
+	transformpb.RegisterTransformServiceServer(srv, transformgrpc.NewServer(t))
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	slog.Info("listening", "protocol", "grpc", "addr", addr)
+	return srv.Serve(lis)
+}
+
+// newServeMux returns the server's routes, recording the latency of
+// /transform requests in metrics and exporting them on /metrics. Request
+// bodies are parsed with parseOpts, and requests fail once timeout has
+// passed if it is not zero.
+func newServeMux(t *transform.Transformer, metrics *serverMetrics, parseOpts []transform.ParseOption, timeout time.Duration) *http.ServeMux {
+	mux := http.NewServeMux()
+	duration := metrics.duration.MustCurryWith(prometheus.Labels{"handler": "/transform"})
+	mux.Handle("/transform", promhttp.InstrumentHandlerDuration(duration, transformHandler(t, metrics, parseOpts, timeout)))
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// transformHandler transforms the typed JSON document in the request body and
+// responds with the plain JSON result. Pass ?reverse=true to convert plain
+// JSON into typed JSON instead. Documents and failures are counted in metrics.
+// Requests stop once the client goes away or, if it is not zero, timeout has
+// passed.
+func transformHandler(t *transform.Transformer, metrics *serverMetrics, parseOpts []transform.ParseOption, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			metrics.errors.WithLabelValues("method").Inc()
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		ctx := r.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		body := transform.NewContextReader(ctx, http.MaxBytesReader(w, r.Body, maxRequestBytes))
+		inputMap, err := transform.ParseReader(body, parseOpts...)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			metrics.errors.WithLabelValues("canceled").Inc()
+			writeJSONError(w, http.StatusServiceUnavailable, ctxErr)
+			return
+		}
+		if err != nil {
+			metrics.errors.WithLabelValues("parse").Inc()
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		direction := "transform"
+		if r.URL.Query().Get("reverse") == "true" {
+			direction = "reverse"
+		}
+		var output map[string]interface{}
+		err = recoverTransform(func() error {
+			if direction == "reverse" {
+				output = t.Reverse(inputMap)
+			} else {
+				output, err = t.TransformRecordContext(ctx, 0, inputMap)
+				return err
+			}
+			return nil
+		})
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			metrics.errors.WithLabelValues("canceled").Inc()
+			writeJSONError(w, http.StatusServiceUnavailable, ctxErr)
+			return
+		}
+		if err != nil {
+			metrics.errors.WithLabelValues("transform").Inc()
+			writeJSONError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		metrics.documents.WithLabelValues(direction).Inc()
+
+		writeJSON(w, http.StatusOK, output)
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	codec.Default().NewEncoder(w).Encode(v)
+}
+
+// writeJSONError writes err as a JSON error response.
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
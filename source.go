@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	awskinesis "github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/s3io"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream/dynamodb"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream/kafka"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/stream/kinesis"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// runPipeline streams records from the source at sourceURL, or from the
+// newline-delimited records returned by openInput when no source is given,
+// through t into the sink at sinkURL, or into outputPath or stdout compressed
+// with format when no sink is given, until the source is exhausted or ctx
+// is done. Cancelling ctx stops a Kinesis or Kafka consumer cleanly, as
+// those streams never end by themselves; a pipeline reading a table or file
+// fails instead, as does one whose deadline passed, once its source has
+// saved its checkpoint. With a checkpoint
+// file the source resumes where the run saving it stopped, and output
+// files are appended to rather than replaced.
+func runPipeline(ctx context.Context, sourceURL, sinkURL, checkpoint, outputPath string, format compress.Format, openInput func() (io.ReadCloser, error), t *transform.Transformer, opts stream.Options) error {
+	resume := false
+	if checkpoint != "" {
+		if _, err := os.Stat(checkpoint); err == nil {
+			resume = true
+		}
+	}
+	if resume && sinkURL == "" && s3io.IsURL(outputPath) {
+		return usageError(errors.New("resuming from a --checkpoint appends to the output and needs a local --output file"))
+	}
+
+	var src stream.Source
+	if sourceURL == "" {
+		in, err := openInput()
+		if err != nil {
+			return parseError(err)
+		}
+		rs, err := stream.NewCheckpointedReaderSource(transform.NewContextReader(ctx, in), stream.ReaderOptions{CheckpointFile: checkpoint})
+		if err != nil {
+			in.Close()
+			return parseError(err)
+		}
+		src = &readerSource{ReaderSource: rs, in: in}
+	} else {
+		var err error
+		if src, err = openSource(ctx, sourceURL, checkpoint); err != nil {
+			return usageError(err)
+		}
+	}
+
+	sink, err := openSink(ctx, sinkURL, outputPath, format, resume)
+	if err != nil {
+		src.Close()
+		return usageError(err)
+	}
+
+	err = recoverTransform(func() error {
+		return stream.Run(ctx, src, t, sink, opts)
+	})
+	if closeErr := src.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := sink.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil && !(errors.Is(err, context.Canceled) && isEndless(sourceURL)) {
+		return streamError(err)
+	}
+	return nil
+}
+
+// isEndless reports whether the source at rawURL is a stream that is only
+// ever stopped by interrupting its consumer, such as a Kinesis stream or
+// Kafka topic.
+func isEndless(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "kinesis" || u.Scheme == "kafka")
+}
+
+// openSource opens the source described by rawURL, recording its position
+// in the checkpoint file unless that is empty:
+//
+//	kinesis://stream-name?start=LATEST&checkpoint=checkpoint.json
+//	kafka://broker1:9092,broker2:9092/topic?group=consumer-group&error-topic=errors
+//	dynamodb://table-name?page-size=100&consistent=true
+//
+// The checkpoint query parameter of Kinesis sources overrides checkpoint.
+// Kafka sources resume from the offsets committed for their consumer group
+// instead.
+func openSource(ctx context.Context, rawURL, checkpoint string) (stream.Source, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+
+	switch u.Scheme {
+	case "kinesis":
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return kinesis.NewSource(ctx, awskinesis.NewFromConfig(cfg), u.Host, kinesis.Options{
+			StartPosition:  types.ShardIteratorType(query.Get("start")),
+			CheckpointFile: firstNonEmpty(query.Get("checkpoint"), checkpoint),
+		})
+	case "kafka":
+		if query.Get("group") == "" {
+			return nil, errors.New("kafka sources need a ?group= consumer group")
+		}
+		if checkpoint != "" {
+			return nil, errors.New("kafka sources resume from the offsets committed for their consumer group and take no --checkpoint")
+		}
+		return kafka.NewSource(kafka.SourceOptions{
+			Brokers:    strings.Split(u.Host, ","),
+			Topic:      strings.TrimPrefix(u.Path, "/"),
+			GroupID:    query.Get("group"),
+			ErrorTopic: query.Get("error-topic"),
+		}), nil
+	case "dynamodb":
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts := dynamodb.SourceOptions{
+			ConsistentRead: query.Get("consistent") == "true",
+			CheckpointFile: checkpoint,
+		}
+		if pageSize := query.Get("page-size"); pageSize != "" {
+			n, err := strconv.ParseInt(pageSize, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid page-size %q", pageSize)
+			}
+			opts.PageSize = int32(n)
+		}
+		return dynamodb.NewSource(awsdynamodb.NewFromConfig(cfg), u.Host, opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported source %q", rawURL)
+	}
+}
+
+// openSink opens the sink described by rawURL, or a newline-delimited JSON
+// sink writing to outputPath or stdout, compressed with format, when rawURL
+// is empty. With appendOutput a local outputPath is appended to instead of
+// being replaced:
+//
+//	kafka://broker1:9092,broker2:9092/topic
+//	dynamodb://table-name
+func openSink(ctx context.Context, rawURL, outputPath string, format compress.Format, appendOutput bool) (stream.Sink, error) {
+	if rawURL == "" {
+		var (
+			w       io.Writer
+			closers []io.Closer
+		)
+		switch {
+		case outputPath == "":
+			w = os.Stdout
+		case s3io.IsURL(outputPath):
+			sw, err := newS3Writer(ctx, outputPath)
+			if err != nil {
+				return nil, err
+			}
+			w, closers = sw, []io.Closer{sw}
+		default:
+			flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+			if appendOutput {
+				flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+			}
+			f, err := os.OpenFile(outputPath, flags, 0o666)
+			if err != nil {
+				return nil, err
+			}
+			w, closers = f, []io.Closer{f}
+		}
+
+		// The compressor is closed first so its trailer reaches the output
+		cw, err := compress.NewWriter(w, format)
+		if err != nil {
+			return nil, err
+		}
+		closers = append([]io.Closer{cw}, closers...)
+		return &closingSink{WriterSink: stream.NewWriterSink(cw), closers: closers}, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "kafka":
+		return kafka.NewSink(kafka.SinkOptions{
+			Brokers: strings.Split(u.Host, ","),
+			Topic:   strings.TrimPrefix(u.Path, "/"),
+		}), nil
+	case "dynamodb":
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return dynamodb.NewSink(awsdynamodb.NewFromConfig(cfg), u.Host, dynamodb.SinkOptions{}), nil
+	default:
+		return nil, fmt.Errorf("unsupported sink %q", rawURL)
+	}
+}
+
+// closingSink is a WriterSink that closes its compressor and file or S3
+// object along with the sink.
+type closingSink struct {
+	*stream.WriterSink
+	closers []io.Closer
+}
+
+// Close flushes the sink and closes the underlying writers in order.
+func (s *closingSink) Close() error {
+	err := s.WriterSink.Close()
+	for _, c := range s.closers {
+		if closeErr := c.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// readerSource is a ReaderSource that closes its input along with the source.
+type readerSource struct {
+	*stream.ReaderSource
+	in io.Closer
+}
+
+// Close closes the source, saving its checkpoint, and the input.
+func (s *readerSource) Close() error {
+	err := s.ReaderSource.Close()
+	if closeErr := s.in.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// firstNonEmpty returns the first of values that is not empty, or "".
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
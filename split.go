@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// splitOptions bound the chunks output is split into; a zero field sets no
+// bound.
+type splitOptions struct {
+	// size is the largest number of bytes of a chunk before compression
+	size int64
+	// records is the largest number of records of a chunk
+	records int
+}
+
+// enabled reports whether output is to be split at all.
+func (o splitOptions) enabled() bool {
+	return o.size > 0 || o.records > 0
+}
+
+// chunkPath returns the name of the chunk numbered n of the output at p,
+// which is p with -00001 and so on inserted before its extensions, e.g.
+// out-00001.ndjson.gz for out.ndjson.gz.
+func chunkPath(p string, n int) string {
+	dir, base := path.Split(p)
+	stem, ext := base, ""
+	if i := strings.Index(base, "."); i > 0 {
+		stem, ext = base[:i], base[i:]
+	}
+	return fmt.Sprintf("%s%s-%05d%s", dir, stem, n, ext)
+}
+
+// writeSplit runs write against a writer splitting the newline-delimited
+// records it writes into numbered chunks of the output at path, each written
+// like writeOutput and starting a new chunk before one would exceed opts.
+// With array each chunk is a JSON array of its records, one per line. At
+// least one chunk is written, even for empty output. Chunks completed before
+// a failure are kept.
+func writeSplit(ctx context.Context, path string, format compress.Format, opts splitOptions, array bool, write func(io.Writer) error) error {
+	sw := &splitWriter{ctx: ctx, path: path, format: format, opts: opts, array: array}
+	if err := write(sw); err != nil {
+		sw.abort(err)
+		return err
+	}
+	return sw.Close()
+}
+
+// splitWriter is the writer of writeSplit.
+type splitWriter struct {
+	ctx    context.Context
+	path   string
+	format compress.Format
+	opts   splitOptions
+	array  bool
+
+	// pending holds the start of a record whose newline is yet to come
+	pending []byte
+	// chunks is the number of chunks started
+	chunks int
+	// chunk receives the current chunk, written in the background until
+	// done reports how it went; nil between chunks
+	chunk   *io.PipeWriter
+	done    chan error
+	size    int64
+	records int
+}
+
+// Write writes the complete records of p to the current chunk, or to a new
+// one when they would exceed its bounds, keeping any trailing partial
+// record for the next call.
+func (s *splitWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			s.pending = append(s.pending, p...)
+			break
+		}
+		record := p[:i+1]
+		if len(s.pending) > 0 {
+			record = append(s.pending, record...)
+			s.pending = s.pending[:0]
+		}
+		if err := s.writeRecord(record); err != nil {
+			return 0, err
+		}
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// writeRecord writes one newline-terminated record, as an array element
+// with array.
+func (s *splitWriter) writeRecord(record []byte) error {
+	if len(bytes.TrimSpace(record)) == 0 {
+		return nil
+	}
+	n := int64(len(record))
+	if s.array {
+		// Elements are written with a separating comma before their newline
+		// and the chunk needs room for its closing bracket
+		n += int64(len(",\n]"))
+	}
+	if s.chunk != nil && s.full(n) {
+		if err := s.closeChunk(); err != nil {
+			return err
+		}
+	}
+	if s.chunk == nil {
+		if err := s.openChunk(); err != nil {
+			return err
+		}
+	}
+	if s.array {
+		if s.records > 0 {
+			if err := s.writeFrame(",\n"); err != nil {
+				return err
+			}
+		}
+		record = bytes.TrimSuffix(record, []byte("\n"))
+	}
+	if _, err := s.chunk.Write(record); err != nil {
+		return err
+	}
+	s.size += int64(len(record))
+	s.records++
+	return nil
+}
+
+// full reports whether a record of n more bytes would take the current
+// chunk past its bounds. A record larger than the size bound gets a chunk
+// of its own.
+func (s *splitWriter) full(n int64) bool {
+	if s.records == 0 {
+		return false
+	}
+	return s.opts.records > 0 && s.records >= s.opts.records || s.opts.size > 0 && s.size+n > s.opts.size
+}
+
+// openChunk starts writing the next chunk.
+func (s *splitWriter) openChunk() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	s.chunks++
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	name := chunkPath(s.path, s.chunks)
+	go func() {
+		err := writeOutput(s.ctx, name, s.format, func(w io.Writer) error {
+			_, err := io.Copy(w, pr)
+			return err
+		})
+		if err != nil {
+			err = fmt.Errorf("%s: %w", name, err)
+		}
+		pr.CloseWithError(err)
+		done <- err
+	}()
+	s.chunk, s.done, s.size, s.records = pw, done, 0, 0
+	if s.array {
+		return s.writeFrame("[\n")
+	}
+	return nil
+}
+
+// closeChunk finishes the current chunk and waits for it to be written.
+func (s *splitWriter) closeChunk() error {
+	if s.array {
+		if err := s.writeFrame("\n]\n"); err != nil {
+			return err
+		}
+	}
+	s.chunk.Close()
+	err := <-s.done
+	s.chunk = nil
+	return err
+}
+
+// writeFrame writes the brackets of an array chunk.
+func (s *splitWriter) writeFrame(frame string) error {
+	_, err := io.WriteString(s.chunk, frame)
+	s.size += int64(len(frame))
+	return err
+}
+
+// Close writes any final record without a newline and finishes the last
+// chunk, writing an empty one if there were no records.
+func (s *splitWriter) Close() error {
+	if len(s.pending) > 0 {
+		if err := s.writeRecord(append(s.pending, '\n')); err != nil {
+			s.abort(err)
+			return err
+		}
+	}
+	if s.chunk == nil && s.chunks == 0 {
+		if err := s.openChunk(); err != nil {
+			return err
+		}
+	}
+	if s.chunk == nil {
+		return nil
+	}
+	return s.closeChunk()
+}
+
+// abort discards the current chunk, which is not written, after err.
+func (s *splitWriter) abort(err error) {
+	if s.chunk == nil {
+		return
+	}
+	s.chunk.CloseWithError(err)
+	<-s.done
+	s.chunk = nil
+}
+
+// writeArrayRecords writes the elements of the JSON array out to w, one
+// compact element per line, for writeSplit to frame into chunks. It fails
+// when out is not an array.
+func writeArrayRecords(w io.Writer, out []byte) error {
+	if trimmed := bytes.TrimSpace(out); len(trimmed) == 0 || trimmed[0] != '[' {
+		return errors.New("splitting output needs an array of documents or newline-delimited records")
+	}
+	dec := transform.NewRecordDecoder(bytes.NewReader(out))
+	var buf bytes.Buffer
+	for {
+		var elem json.RawMessage
+		if err := dec.Decode(&elem); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		buf.Reset()
+		if err := json.Compact(&buf, elem); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/compress"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// statsReport is the --stats summary of a transformation.
+type statsReport struct {
+	transform.StatsReport
+	// WallTime is how long the transformation took, e.g. "1.5s".
+	WallTime string `json:"wallTime"`
+}
+
+// reportStats logs report and the wall time of the transformation if
+// logIt is set, and writes them as JSON to path if it is not empty.
+func reportStats(report transform.StatsReport, wallTime time.Duration, logIt bool, path string) error {
+	if logIt {
+		typeKeys := make([]string, 0, len(report.Types))
+		for typeKey := range report.Types {
+			typeKeys = append(typeKeys, typeKey)
+		}
+		sort.Strings(typeKeys)
+		types := make([]interface{}, 0, len(typeKeys))
+		for _, typeKey := range typeKeys {
+			types = append(types, slog.Int64(typeKey, report.Types[typeKey]))
+		}
+
+		slog.Info("transformation stats",
+			"records", report.Records,
+			"wall_time", wallTime,
+			"dropped_keys", report.DroppedKeys,
+			"invalid_numbers", report.InvalidNumbers,
+			"date_conversions", report.DateConversions,
+			slog.Group("types", types...),
+		)
+	}
+
+	if path == "" {
+		return nil
+	}
+	return writeOutput(context.Background(), path, compress.FormatOf(path), func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statsReport{StatsReport: report, WallTime: wallTime.String()})
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// tracingFlags registers the flags that configure OpenTelemetry tracing on fs
+// and returns a function installing the global tracer provider once fs has
+// been parsed. That function returns another flushing buffered spans, to be
+// called before the program exits.
+func tracingFlags(fs *flag.FlagSet) func() (func(), error) {
+	endpointFlag := fs.String("otlp-endpoint", "", "Export traces over OTLP/gRPC to this collector, e.g. localhost:4317; the standard OTEL_EXPORTER_OTLP_* variables are also honoured")
+
+	return func() (func(), error) {
+		// Tracing stays off unless an endpoint is configured
+		if *endpointFlag == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+			return func() {}, nil
+		}
+
+		ctx := context.Background()
+		var opts []otlptracegrpc.Option
+		if *endpointFlag != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(*endpointFlag))
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+		res, err := resource.New(ctx,
+			resource.WithAttributes(attribute.String("service.name", "transformer")),
+			resource.WithFromEnv(),
+			resource.WithTelemetrySDK(),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		return func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := provider.Shutdown(ctx); err != nil {
+				slog.Warn("exporting traces failed", "err", err)
+			}
+		}, nil
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Ravali181221/Ravali_Challenge/pkg/codec"
+	"github.com/Ravali181221/Ravali_Challenge/pkg/transform"
+)
+
+// validateCommand registers the flags of the validate command on fs and
+// returns the function running it, which checks typed JSON documents against
+// the DynamoDB format without producing output, printing every problem found
+// and failing with a parse error if there are any. Documents are read from
+// the files given as arguments, "-" meaning stdin, or else from --config or
+// piped stdin.
+func validateCommand(fs *flag.FlagSet) func(args []string) error {
+	schemaFlag := fs.String("config", "schema.json", "Used to read the json file, either a local path, an s3://bucket/key URL or an http(s):// URL")
+	ndjsonFlag := fs.Bool("ndjson", false, "Validate every record of newline-delimited JSON input, reporting problems by line")
+	roundTripFlag := fs.Bool("verify-roundtrip", false, "Also transform every document, reverse the result back into typed JSON and report the attributes that do not come back unchanged, such as numbers losing precision, dropped attributes or reformatted timestamps")
+	parseOpts := parseOptionFlags(fs)
+	newTransformer := transformerFlags(fs)
+	httpFlags(fs)
+
+	return func(_ []string) error {
+		t, err := newTransformer(context.Background())
+		if err != nil {
+			return usageError(err)
+		}
+		opts := validateOptions{ndjson: *ndjsonFlag, roundTrip: *roundTripFlag, parseOpts: parseOpts()}
+		if len(opts.parseOpts) > 0 && opts.ndjson {
+			return usageError(errors.New("--reject-duplicate-keys and the --max-* input limits need whole documents and cannot be combined with --ndjson"))
+		}
+
+		// Problems are only prefixed with their file when files are named
+		if fs.NArg() == 0 {
+			// Read from stdin when input is piped in and no --config flag is given
+			useStdin := !isFlagSet(fs, "config") && stdinIsPiped()
+			problems, err := validateInput(os.Stdout, t, *schemaFlag, useStdin, opts, "")
+			if err != nil {
+				return parseError(&fileError{file: *schemaFlag, err: err})
+			}
+			return problemsError(problems)
+		}
+
+		// Keep going past unreadable files so one run reports every problem
+		problems := 0
+		for _, name := range fs.Args() {
+			n, err := validateInput(os.Stdout, t, name, name == "-", opts, name)
+			if err != nil {
+				fmt.Fprintf(os.Stdout, "%s: %v\n", name, err)
+				n++
+			}
+			problems += n
+		}
+		return problemsError(problems)
+	}
+}
+
+// problemsError returns a parse error counting the problems found, or nil if
+// there are none.
+func problemsError(problems int) error {
+	if problems == 0 {
+		return nil
+	}
+	return parseError(fmt.Errorf("%d problems found", problems))
+}
+
+// validateOptions select how validate reads its input.
+type validateOptions struct {
+	ndjson bool
+	// roundTrip also reports the values lost transforming and reversing
+	roundTrip bool
+	parseOpts []transform.ParseOption
+}
+
+// validateInput validates the typed document read from the named input, or
+// with opts.ndjson each of its records, and prints every problem to w
+// prefixed with label when it is not empty. NDJSON problems are also prefixed
+// with the line of their record. It returns the number of problems printed.
+func validateInput(w io.Writer, t *transform.Transformer, file string, useStdin bool, opts validateOptions, label string) (int, error) {
+	if !opts.ndjson {
+		prefix := ""
+		if label != "" {
+			prefix = label + ": "
+		}
+		inputMap, err := readDocument(context.Background(), file, useStdin, opts.parseOpts...)
+		var dupErr *transform.DuplicateKeyError
+		if errors.As(err, &dupErr) {
+			// The document cannot be checked further until its keys are unique
+			for _, pointer := range dupErr.Pointers {
+				fmt.Fprintf(w, "%s%s: duplicate key\n", prefix, pointer)
+			}
+			return len(dupErr.Pointers), nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		warnings, err := documentWarnings(t, inputMap, opts.roundTrip)
+		if err != nil {
+			return 0, err
+		}
+		return printWarnings(w, prefix, warnings), nil
+	}
+
+	in, err := openInput(context.Background(), file, useStdin)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	dec := codec.Default().NewDecoder(transform.NewTextReader(in))
+	problems := 0
+	for index := 0; ; index++ {
+		var record map[string]interface{}
+		if err := dec.Decode(&record); err == io.EOF {
+			return problems, nil
+		} else if err != nil {
+			return problems, &transform.RecordError{Index: index, Err: err}
+		}
+		prefix := fmt.Sprintf("line %d: ", index+1)
+		if label != "" {
+			prefix = fmt.Sprintf("%s:%d: ", label, index+1)
+		}
+		warnings, err := documentWarnings(t, record, opts.roundTrip)
+		if err != nil {
+			return problems, &transform.RecordError{Index: index, Err: err}
+		}
+		problems += printWarnings(w, prefix, warnings)
+	}
+}
+
+// documentWarnings returns the problems of the typed document input followed
+// by those of its transformed output, such as missing required fields, and
+// with roundTrip the values lost transforming and reversing it.
+func documentWarnings(t *transform.Transformer, input map[string]interface{}, roundTrip bool) ([]transform.Warning, error) {
+	warnings := t.Validate(input)
+	warnings = append(warnings, t.OutputWarnings(t.Transform(input))...)
+	if !roundTrip {
+		return warnings, nil
+	}
+	lossy, err := t.VerifyRoundTrip(input)
+	if err != nil {
+		return nil, err
+	}
+	return append(warnings, lossy...), nil
+}
+
+// printWarnings prints each warning to w on its own line after prefix,
+// returning how many there were.
+func printWarnings(w io.Writer, prefix string, warnings []transform.Warning) int {
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "%s%s\n", prefix, warning)
+	}
+	return len(warnings)
+}
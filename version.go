@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, injected at build time with
+//
+//	go build -ldflags "-X main.version=1.2.3 -X main.commit=$(git rev-parse HEAD) -X main.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Values left empty fall back to what the Go toolchain recorded in the binary:
+// the module version, the VCS revision and the time of that commit.
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// buildInfo describes the build of the running binary.
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// currentBuild returns the build metadata of the running binary, preferring
+// the values injected with -ldflags over the module and VCS information
+// recorded by the Go toolchain.
+func currentBuild() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			case setting.Key == "vcs.modified" && setting.Value == "true" && commit == "":
+				info.Commit += "-dirty"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+// versionCommand registers the flags of the version command on fs and
+// returns the function running it, which prints the build metadata of the
+// binary so the build that produced a dataset can be verified.
+func versionCommand(fs *flag.FlagSet) func(args []string) error {
+	jsonFlag := fs.Bool("json", false, "Print the build metadata as a JSON object")
+
+	return func(_ []string) error {
+		info := currentBuild()
+		if *jsonFlag {
+			if err := json.NewEncoder(os.Stdout).Encode(info); err != nil {
+				return transformError(err)
+			}
+			return nil
+		}
+		_, err := fmt.Fprintf(os.Stdout, "version %s\ncommit %s\nbuilt %s\n%s %s\n", info.Version, info.Commit, info.Date, info.GoVersion, info.Platform)
+		if err != nil {
+			return transformError(err)
+		}
+		return nil
+	}
+}
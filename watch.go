@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the watcher waits for further changes before
+// re-running, so editors that write a file in several steps trigger one run.
+const watchDebounce = 100 * time.Millisecond
+
+// runWatch runs fn once and then again whenever a file under target changes,
+// until interrupted. target is either a single input file or a directory,
+// which is watched recursively. Changes under ignore, typically the output,
+// do not trigger a run. Errors from fn are reported without stopping the
+// watcher.
+func runWatch(target, ignore string, fn func() error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return usageError(err)
+	}
+	defer watcher.Close()
+
+	// Files are watched through their directory so editors that replace the
+	// file by renaming a new one into place are still noticed
+	info, err := os.Stat(target)
+	if err != nil {
+		return usageError(err)
+	}
+	isDir := info.IsDir()
+	if isDir {
+		err = filepath.WalkDir(target, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return err
+			}
+			return watcher.Add(path)
+		})
+	} else {
+		err = watcher.Add(filepath.Dir(target))
+	}
+	if err != nil {
+		return usageError(err)
+	}
+
+	runAndReport(fn)
+
+	// relevant reports whether an event on path should trigger a run
+	relevant := func(path string) bool {
+		if ignore != "" && (path == ignore || strings.HasPrefix(path, ignore+string(filepath.Separator))) {
+			return false
+		}
+		return isDir || filepath.Clean(path) == filepath.Clean(target)
+	}
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// New directories are watched too so files created in them count
+			if isDir && event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					watcher.Add(event.Name)
+				}
+			}
+			if relevant(filepath.Clean(event.Name)) && !event.Has(fsnotify.Chmod) {
+				pending = time.After(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logError("watch failed", err)
+		case <-pending:
+			pending = nil
+			runAndReport(fn)
+		}
+	}
+}
+
+// runAndReport runs fn, logging any error.
+func runAndReport(fn func() error) {
+	if err := fn(); err != nil && !errors.Is(err, errFlagsReported) {
+		logError("run failed", err)
+	}
+}
+
+// withoutWatchFlag returns args with any --watch flag replaced by
+// --watch=false, so the command can be re-run once per change even when
+// TRANSFORMER_WATCH is set.
+func withoutWatchFlag(args []string) []string {
+	out := []string{"--watch=false"}
+	for _, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if strings.HasPrefix(arg, "-") && (name == "watch" || strings.HasPrefix(name, "watch=")) {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}